@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"github.com/marvelalexius/jones/config"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var deliverNotificationsCmd = &cobra.Command{
+	Use:   "deliver-notifications",
+	Short: "Deliver notifications held back by quiet hours",
+	Long:  `This subcommand hands the push notifications and emails that quiet hours held back to the outbox, once they're over, and the worker delivers them. Schedule it every few minutes with cron, notifications are delivered no earlier than the end of the recipient's quiet hours`,
+	Run:   deliverNotifications,
+}
+
+func init() {
+	rootCmd.AddCommand(deliverNotificationsCmd)
+}
+
+func deliverNotifications(cmd *cobra.Command, args []string) {
+	appconf := config.InitConfig()
+
+	db, err := appconf.NewDatabase()
+	if err != nil {
+		logrus.Fatalln("failed to connect database", err)
+	}
+	defer appconf.CloseDatabase(db)
+
+	broker, err := newBroker(appconf, db)
+	if err != nil {
+		logrus.Fatalln("failed to start realtime broker", err)
+	}
+	defer broker.Close()
+
+	transactor := repository.NewTransactor(db)
+	userRepo := repository.NewUserRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	deviceRepo := repository.NewDeviceRepository(db)
+	notificationPreferenceRepo := repository.NewNotificationPreferenceRepository(db)
+	deferredNotificationRepo := repository.NewDeferredNotificationRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+
+	dispatcher, err := newDispatcher(appconf, transactor, userRepo, notificationRepo, deviceRepo, notificationPreferenceRepo, deferredNotificationRepo, outboxRepo, broker)
+	if err != nil {
+		logrus.Fatalln("failed to set up notification channels", err)
+	}
+
+	err = dispatcher.DeliverDeferred(cmd.Context())
+	continueOrFatal(err)
+
+	logrus.Info("queued deferred notifications")
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/marvelalexius/jones/config"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/service"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var expireMatchesCmd = &cobra.Command{
+	Use:   "expire-matches",
+	Short: "Expire matches nobody wrote to",
+	Long:  `This subcommand expires the matches whose window for a first message has passed and leaves a notification for both users in the outbox. Schedule it every few minutes with cron, matches past their expiry are already closed to messages in the meantime`,
+	Run:   expireMatches,
+}
+
+func init() {
+	rootCmd.AddCommand(expireMatchesCmd)
+}
+
+func expireMatches(cmd *cobra.Command, args []string) {
+	appconf := config.InitConfig()
+
+	db, err := appconf.NewDatabase()
+	if err != nil {
+		logrus.Fatalln("failed to connect database", err)
+	}
+	defer appconf.CloseDatabase(db)
+
+	transactor := repository.NewTransactor(db)
+	userRepo := repository.NewUserRepository(db)
+	reactionRepo := repository.NewReactionRepository(db)
+	subscriptionRepo := repository.NewSubscriptionRepository(db)
+	boostRepo := repository.NewBoostRepository(db)
+	matchRepo := repository.NewMatchRepository(db)
+	messageRepo := repository.NewMessageRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	deferredNotificationRepo := repository.NewDeferredNotificationRepository(db)
+
+	quotaService := service.NewQuotaService(userRepo, reactionRepo, subscriptionRepo, boostRepo)
+	matchService := service.NewMatchService(appconf, transactor, matchRepo, reactionRepo, messageRepo, notificationRepo, quotaService, outboxRepo, deferredNotificationRepo)
+
+	err = matchService.ExpireDue(cmd.Context())
+	continueOrFatal(err)
+
+	logrus.Info("expired matches")
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
@@ -9,12 +10,22 @@ import (
 	"github.com/marvelalexius/jones/config"
 	"github.com/marvelalexius/jones/http"
 	"github.com/marvelalexius/jones/http/middleware"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/blob"
+	"github.com/marvelalexius/jones/pkg/decktoken"
+	"github.com/marvelalexius/jones/pkg/imageproxy"
+	"github.com/marvelalexius/jones/pkg/mailer"
+	"github.com/marvelalexius/jones/pkg/moderation"
+	"github.com/marvelalexius/jones/pkg/push"
+	"github.com/marvelalexius/jones/pkg/realtime"
+	"github.com/marvelalexius/jones/pkg/signedurl"
 	stripePkg "github.com/marvelalexius/jones/pkg/stripe"
 	"github.com/marvelalexius/jones/repository"
 	"github.com/marvelalexius/jones/service"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"gorm.io/gorm"
 )
 
 var runHTTPServer = &cobra.Command{
@@ -51,24 +62,137 @@ func initHTTP() error {
 	defer appconf.CloseDatabase(db)
 
 	stripeClient := stripePkg.NewStripeClient(appconf.Stripe.Secret, appconf.Stripe.WebhookSecret)
+	moderator := moderation.NewModerator(appconf.Moderation.BlockedTerms)
+	deckTokens, err := decktoken.NewSigner(appconf.App.DeckTokenSecret, model.DeckTokenTTL, appconf.FeatureFlag.RequireDeckToken)
+	if err != nil {
+		logrus.Fatalln("failed to set up deck tokens", err)
+	}
+
+	broker, err := newBroker(appconf, db)
+	if err != nil {
+		logrus.Fatalln("failed to start realtime broker", err)
+	}
+	defer broker.Close()
+
+	hub := realtime.NewHub()
+	broker.Subscribe(hub.Deliver)
+
+	attachmentStorage, err := blob.NewLocalStorage(appconf.Attachment.StorageDir)
+	if err != nil {
+		logrus.Fatalln("failed to open attachment storage", err)
+	}
+	attachmentURLs, err := signedurl.NewSigner(appconf.Attachment.URLSecret, appconf.Attachment.BaseURL, model.AttachmentURLTTL)
+	if err != nil {
+		logrus.Fatalln("failed to set up attachment urls", err)
+	}
 
+	imageProxy, err := imageproxy.NewProxy(appconf.Media.TeaserURLTemplate)
+	if err != nil {
+		logrus.Fatalln("failed to set up image proxy", err)
+	}
+
+	transactor := repository.NewTransactor(db)
 	userRepo := repository.NewUserRepository(db)
 	reactionRepo := repository.NewReactionRepository(db)
 	subscriptionRepo := repository.NewSubscriptionRepository(db)
 	notificationRepo := repository.NewNotificationRepository(db)
+	topPickRepo := repository.NewTopPickRepository(db)
+	matchRepo := repository.NewMatchRepository(db)
+	creditRepo := repository.NewCreditRepository(db)
+	boostRepo := repository.NewBoostRepository(db)
+	conversationRepo := repository.NewConversationRepository(db)
+	messageRepo := repository.NewMessageRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	deviceRepo := repository.NewDeviceRepository(db)
+	notificationPreferenceRepo := repository.NewNotificationPreferenceRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	deferredNotificationRepo := repository.NewDeferredNotificationRepository(db)
+
+	// events published by a separate worker wouldn't reach this node's streams
+	if appconf.Realtime.Broker == "memory" {
+		worker, err := newOutboxWorker(appconf, db, broker)
+		if err != nil {
+			logrus.Fatalln("failed to set up notification channels", err)
+		}
+
+		go worker.Run(context.Background(), appconf.Worker.PollInterval)
+	}
 
-	userService := service.NewUserService(appconf, userRepo, reactionRepo)
-	reactionService := service.NewReactionService(userRepo, reactionRepo, subscriptionRepo, notificationRepo)
-	subscriptionService := service.NewSubscriptionService(appconf, stripeClient, userRepo, subscriptionRepo)
+	quotaService := service.NewQuotaService(userRepo, reactionRepo, subscriptionRepo, boostRepo)
+	userService := service.NewUserService(appconf, userRepo, reactionRepo, matchRepo, boostRepo, deckTokens)
+	reactionService := service.NewReactionService(appconf, transactor, userRepo, reactionRepo, subscriptionRepo, notificationRepo, matchRepo, conversationRepo, creditRepo, boostRepo, quotaService, moderator, deckTokens, attachmentURLs, imageProxy, broker, outboxRepo, deferredNotificationRepo)
+	subscriptionService := service.NewSubscriptionService(appconf, transactor, stripeClient, userRepo, subscriptionRepo, outboxRepo)
+	topPickService := service.NewTopPickService(userRepo, reactionRepo, matchRepo, subscriptionRepo, topPickRepo, deckTokens)
+	matchService := service.NewMatchService(appconf, transactor, matchRepo, reactionRepo, messageRepo, notificationRepo, quotaService, outboxRepo, deferredNotificationRepo)
+	creditService := service.NewCreditService(appconf, stripeClient, userRepo, creditRepo)
+	boostService := service.NewBoostService(transactor, boostRepo, creditRepo, quotaService)
+	messageService := service.NewMessageService(transactor, matchRepo, conversationRepo, messageRepo, attachmentRepo, reactionRepo, notificationRepo, moderator, attachmentStorage, attachmentURLs, broker, outboxRepo, deferredNotificationRepo)
+	notificationService := service.NewNotificationService(notificationRepo)
+	deviceService := service.NewDeviceService(deviceRepo)
+	notificationPreferenceService := service.NewNotificationPreferenceService(userRepo, notificationPreferenceRepo)
 
 	route := gin.New()
 	route.Use(gin.Recovery())
-	route.Use(gin.Logger())
+	route.Use(middleware.Logger())
 	route.Use(gin.ErrorLogger())
-	route.Use(middleware.CORS())
+	route.Use(middleware.CORS(appconf.App.AllowedOrigins))
 
-	httpService := http.NewHTTPService(appconf, &userService, &reactionService, &subscriptionService)
+	httpService := http.NewHTTPService(appconf, &userService, &reactionService, &subscriptionService, &topPickService, &matchService, &creditService, &quotaService, &boostService, &messageService, &notificationService, &deviceService, &notificationPreferenceService, hub)
 	httpService.Routes(route)
 
 	return route.Run(":8080")
 }
+
+// newBroker picks how realtime events reach the other nodes, several nodes need postgres.
+func newBroker(appconf *config.Config, db *gorm.DB) (realtime.IBroker, error) {
+	if appconf.Realtime.Broker == "postgres" {
+		return realtime.NewPostgresBroker(db, appconf.DSN())
+	}
+
+	return realtime.NewMemoryBroker(), nil
+}
+
+// newDispatcher sets up the channels notifications go out on, push and email are left out until they're configured.
+func newDispatcher(appconf *config.Config, transactor repository.ITransactor, userRepo repository.IUserRepository, notificationRepo repository.INotificationRepository, deviceRepo repository.IDeviceRepository, preferenceRepo repository.INotificationPreferenceRepository, deferredRepo repository.IDeferredNotificationRepository, outboxRepo repository.IOutboxRepository, broker realtime.IPublisher) (service.INotificationDispatcher, error) {
+	channels := map[string]service.INotificationChannel{
+		model.NotificationChannelInApp: service.NewInAppChannel(notificationRepo, broker),
+	}
+
+	providers := map[string]push.IProvider{}
+	if appconf.Push.FCMCredentialsFile != "" {
+		credentials, err := os.ReadFile(appconf.Push.FCMCredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+
+		providers[model.PlatformAndroid], err = push.NewFCMProvider(credentials)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if appconf.Push.APNsKeyFile != "" {
+		authKey, err := os.ReadFile(appconf.Push.APNsKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		providers[model.PlatformIOS], err = push.NewAPNsProvider(authKey, appconf.Push.APNsKeyID, appconf.Push.APNsTeamID, appconf.Push.APNsTopic, appconf.Push.APNsSandbox)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(providers) > 0 {
+		channels[model.NotificationChannelPush] = service.NewPushChannel(deviceRepo, providers)
+	}
+
+	if appconf.Mail.Host != "" {
+		smtpMailer, err := mailer.NewSMTPMailer(appconf.Mail.Host, appconf.Mail.Port, appconf.Mail.Username, appconf.Mail.Password, appconf.Mail.From)
+		if err != nil {
+			return nil, err
+		}
+
+		channels[model.NotificationChannelEmail] = service.NewEmailChannel(smtpMailer)
+	}
+
+	return service.NewNotificationDispatcher(transactor, userRepo, preferenceRepo, deferredRepo, outboxRepo, channels), nil
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"github.com/marvelalexius/jones/config"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/decktoken"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/service"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var topPicksCmd = &cobra.Command{
+	Use:   "top-picks",
+	Short: "Generate daily top picks",
+	Long:  `This subcommand generates the daily top picks batch for every user. Schedule it hourly with cron, picks expire at midnight in each user's timezone and users whose picks are still active are skipped`,
+	Run:   generateTopPicks,
+}
+
+func init() {
+	rootCmd.AddCommand(topPicksCmd)
+}
+
+func generateTopPicks(cmd *cobra.Command, args []string) {
+	appconf := config.InitConfig()
+
+	db, err := appconf.NewDatabase()
+	if err != nil {
+		logrus.Fatalln("failed to connect database", err)
+	}
+	defer appconf.CloseDatabase(db)
+
+	userRepo := repository.NewUserRepository(db)
+	reactionRepo := repository.NewReactionRepository(db)
+	matchRepo := repository.NewMatchRepository(db)
+	subscriptionRepo := repository.NewSubscriptionRepository(db)
+	topPickRepo := repository.NewTopPickRepository(db)
+
+	deckTokens, err := decktoken.NewSigner(appconf.App.DeckTokenSecret, model.DeckTokenTTL, appconf.FeatureFlag.RequireDeckToken)
+	if err != nil {
+		logrus.Fatalln("failed to set up deck tokens", err)
+	}
+
+	topPickService := service.NewTopPickService(userRepo, reactionRepo, matchRepo, subscriptionRepo, topPickRepo, deckTokens)
+
+	err = topPickService.Generate(cmd.Context())
+	continueOrFatal(err)
+
+	logrus.Info("top picks generated")
+}
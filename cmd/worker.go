@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"os/signal"
+	"syscall"
+
+	"github.com/marvelalexius/jones/config"
+	"github.com/marvelalexius/jones/pkg/realtime"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/service"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "Send what the API wrote to the outbox",
+	Long:  `This subcommand runs until it's stopped, sending the notifications the API wrote to the outbox. Failed messages are retried with exponential backoff and moved to the dead letters once they run out of attempts. Several workers can run side by side. With the memory broker the API server runs its own worker, the events would never reach its streams from here`,
+	Run:   runWorker,
+}
+
+func init() {
+	rootCmd.AddCommand(workerCmd)
+}
+
+func runWorker(cmd *cobra.Command, args []string) {
+	appconf := config.InitConfig()
+
+	db, err := appconf.NewDatabase()
+	if err != nil {
+		logrus.Fatalln("failed to connect database", err)
+	}
+	defer appconf.CloseDatabase(db)
+
+	broker, err := newBroker(appconf, db)
+	if err != nil {
+		logrus.Fatalln("failed to start realtime broker", err)
+	}
+	defer broker.Close()
+
+	worker, err := newOutboxWorker(appconf, db, broker)
+	if err != nil {
+		logrus.Fatalln("failed to set up notification channels", err)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logrus.Info("outbox worker started")
+
+	worker.Run(ctx, appconf.Worker.PollInterval)
+
+	logrus.Info("outbox worker stopped")
+}
+
+// newOutboxWorker sets up a worker that hands notifications to the channels that are configured.
+func newOutboxWorker(appconf *config.Config, db *gorm.DB, broker realtime.IPublisher) (service.IOutboxWorker, error) {
+	transactor := repository.NewTransactor(db)
+	userRepo := repository.NewUserRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	deviceRepo := repository.NewDeviceRepository(db)
+	notificationPreferenceRepo := repository.NewNotificationPreferenceRepository(db)
+	deferredNotificationRepo := repository.NewDeferredNotificationRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+
+	dispatcher, err := newDispatcher(appconf, transactor, userRepo, notificationRepo, deviceRepo, notificationPreferenceRepo, deferredNotificationRepo, outboxRepo, broker)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.NewOutboxWorker(transactor, outboxRepo, dispatcher, appconf.Worker.MaxAttempts), nil
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
@@ -13,10 +15,17 @@ import (
 type App struct {
 	Secret             string
 	RefreshTokenSecret string
+	DeckTokenSecret    string
+	// AllowedOrigins are the web origins browsers may call the api and open streams from, when it's empty
+	// any origin may call the api but streams only accept their own.
+	AllowedOrigins []string
 }
 
 type FeatureFlag struct {
 	EnableStripe bool
+
+	// RequireDeckToken refuses swipes on profiles that weren't served to the user in a deck
+	RequireDeckToken bool
 }
 
 type DB struct {
@@ -31,12 +40,89 @@ type DB struct {
 type Stripe struct {
 	Secret        string
 	WebhookSecret string
+
+	// CreditPriceIDs maps a credit type to the stripe price of a single credit pack
+	CreditPriceIDs map[string]string
+}
+
+type Moderation struct {
+	// BlockedTerms are words and phrases that can't appear in anything users write to each other
+	BlockedTerms []string
+}
+
+type Discovery struct {
+	// PassCooldown is how long a passed profile stays out of the deck, zero keeps it out for good
+	PassCooldown time.Duration
+}
+
+type Matches struct {
+	// ExpiresAfter is how long a new match has for its first message, zero keeps matches open for good
+	ExpiresAfter time.Duration
+	// FirstMove decides who sends the first message: ANYONE, WOMEN_FIRST or FIRST_LIKER
+	FirstMove string
+}
+
+type Media struct {
+	// TeaserURLTemplate points at the image proxy serving blurred, low resolution copies of a photo,
+	// {url} is replaced with the escaped original. The server fetches through it, it's required.
+	TeaserURLTemplate string
+}
+
+type Attachment struct {
+	// StorageDir is where chat attachments are kept on disk
+	StorageDir string
+	// URLSecret signs the short-lived URLs attachments are served from
+	URLSecret string
+	// BaseURL is the api root signed attachment URLs are built on, e.g. https://api.example.com/api/v1
+	BaseURL string
+}
+
+type Realtime struct {
+	// Broker fans realtime events out between nodes, "memory" for a single node or "postgres" for several
+	Broker string
+}
+
+type Push struct {
+	// FCMCredentialsFile is the firebase service account JSON, android devices get no push without it
+	FCMCredentialsFile string
+	// APNsKeyFile is the .p8 auth key, iOS devices get no push without it
+	APNsKeyFile string
+	APNsKeyID   string
+	APNsTeamID  string
+	// APNsTopic is the app's bundle id
+	APNsTopic   string
+	APNsSandbox bool
+}
+
+type Mail struct {
+	// Host is the SMTP server, nothing is emailed while it's empty
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type Worker struct {
+	// PollInterval is how long the worker waits between looks at an empty outbox
+	PollInterval time.Duration
+	// MaxAttempts is how many times a message is tried before it's moved to the dead letters
+	MaxAttempts int
 }
 
 type Config struct {
 	App         App
 	DB          DB
 	Stripe      Stripe
+	Moderation  Moderation
+	Discovery   Discovery
+	Matches     Matches
+	Media       Media
+	Attachment  Attachment
+	Realtime    Realtime
+	Push        Push
+	Mail        Mail
+	Worker      Worker
 	FeatureFlag FeatureFlag
 }
 
@@ -45,6 +131,12 @@ func InitConfig() *Config {
 
 	c.App.Secret = os.Getenv("APP_SECRET")
 	c.App.RefreshTokenSecret = os.Getenv("APP_REFRESH_TOKEN_SECRET")
+	c.App.DeckTokenSecret = os.Getenv("APP_DECK_TOKEN_SECRET")
+	for _, origin := range strings.Split(os.Getenv("APP_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			c.App.AllowedOrigins = append(c.App.AllowedOrigins, origin)
+		}
+	}
 	c.DB.Host = os.Getenv("DB_HOST")
 	c.DB.Port, _ = strconv.Atoi(os.Getenv("DB_PORT"))
 	c.DB.Database = os.Getenv("DB_NAME")
@@ -54,8 +146,70 @@ func InitConfig() *Config {
 
 	c.Stripe.Secret = os.Getenv("STRIPE_SECRET_KEY")
 	c.Stripe.WebhookSecret = os.Getenv("STRIPE_WEBHOOK_SECRET")
+	c.Stripe.CreditPriceIDs = map[string]string{
+		"SUPER_LIKE": os.Getenv("STRIPE_SUPER_LIKE_PRICE_ID"),
+		"BOOST":      os.Getenv("STRIPE_BOOST_PRICE_ID"),
+	}
+
+	c.Moderation.BlockedTerms = strings.Split(os.Getenv("MODERATION_BLOCKED_TERMS"), ",")
+
+	c.Discovery.PassCooldown = 30 * 24 * time.Hour
+	if days, err := strconv.Atoi(os.Getenv("DISCOVERY_PASS_COOLDOWN_DAYS")); err == nil {
+		c.Discovery.PassCooldown = time.Duration(days) * 24 * time.Hour
+	}
+
+	if hours, err := strconv.Atoi(os.Getenv("MATCH_EXPIRES_AFTER_HOURS")); err == nil {
+		c.Matches.ExpiresAfter = time.Duration(hours) * time.Hour
+	}
+	c.Matches.FirstMove = os.Getenv("MATCH_FIRST_MOVE")
+	if c.Matches.FirstMove == "" {
+		c.Matches.FirstMove = "ANYONE"
+	}
+
+	c.Media.TeaserURLTemplate = os.Getenv("MEDIA_TEASER_URL_TEMPLATE")
+
+	c.Attachment.StorageDir = os.Getenv("ATTACHMENT_STORAGE_DIR")
+	if c.Attachment.StorageDir == "" {
+		c.Attachment.StorageDir = "storage/attachments"
+	}
+	c.Attachment.URLSecret = os.Getenv("ATTACHMENT_URL_SECRET")
+	c.Attachment.BaseURL = os.Getenv("ATTACHMENT_BASE_URL")
+	if c.Attachment.BaseURL == "" {
+		c.Attachment.BaseURL = "/api/v1"
+	}
+
+	c.Realtime.Broker = os.Getenv("REALTIME_BROKER")
+	if c.Realtime.Broker == "" {
+		c.Realtime.Broker = "memory"
+	}
+
+	c.Push.FCMCredentialsFile = os.Getenv("PUSH_FCM_CREDENTIALS_FILE")
+	c.Push.APNsKeyFile = os.Getenv("PUSH_APNS_KEY_FILE")
+	c.Push.APNsKeyID = os.Getenv("PUSH_APNS_KEY_ID")
+	c.Push.APNsTeamID = os.Getenv("PUSH_APNS_TEAM_ID")
+	c.Push.APNsTopic = os.Getenv("PUSH_APNS_TOPIC")
+	c.Push.APNsSandbox = os.Getenv("PUSH_APNS_SANDBOX") == "true"
+
+	c.Mail.Host = os.Getenv("MAIL_HOST")
+	c.Mail.Port = 587
+	if port, err := strconv.Atoi(os.Getenv("MAIL_PORT")); err == nil {
+		c.Mail.Port = port
+	}
+	c.Mail.Username = os.Getenv("MAIL_USERNAME")
+	c.Mail.Password = os.Getenv("MAIL_PASSWORD")
+	c.Mail.From = os.Getenv("MAIL_FROM")
+
+	c.Worker.PollInterval = 2 * time.Second
+	if seconds, err := strconv.Atoi(os.Getenv("WORKER_POLL_INTERVAL_SECONDS")); err == nil {
+		c.Worker.PollInterval = time.Duration(seconds) * time.Second
+	}
+	c.Worker.MaxAttempts = 10
+	if attempts, err := strconv.Atoi(os.Getenv("WORKER_MAX_ATTEMPTS")); err == nil {
+		c.Worker.MaxAttempts = attempts
+	}
 
 	c.FeatureFlag.EnableStripe = os.Getenv("FEATURE_FLAG_ENABLE_STRIPE") == "true"
+	c.FeatureFlag.RequireDeckToken = os.Getenv("FEATURE_FLAG_REQUIRE_DECK_TOKEN") == "true"
 
 	return &c
 }
@@ -75,9 +229,12 @@ func PostgresDSN() string {
 	return dsn
 }
 
+func (c *Config) DSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", c.DB.Host, c.DB.Port, c.DB.User, c.DB.Password, c.DB.Database)
+}
+
 func (c *Config) NewDatabase() (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable", c.DB.Host, c.DB.Port, c.DB.User, c.DB.Password, c.DB.Database)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(c.DSN()), &gorm.Config{TranslateError: true})
 	if err != nil {
 		logrus.Fatalln("failed to connect database", err)
 
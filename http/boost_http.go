@@ -0,0 +1,91 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/service"
+	"github.com/marvelalexius/jones/utils"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+func (h *HTTPService) ActivateBoost(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when activating boost",
+		})
+
+		return
+	}
+
+	boost, err := h.BoostService.Activate(c, userID.(string))
+	if err != nil {
+		logger.Errorln(c, "failed to activate boost", err)
+
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrBoostActive):
+			status = http.StatusConflict
+		case errors.Is(err, service.ErrNoBoostsLeft):
+			status = http.StatusPaymentRequired
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when activating boost",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    boost,
+	})
+}
+
+func (h *HTTPService) FindAllBoosts(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding boosts",
+		})
+
+		return
+	}
+
+	var req model.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Errorln(c, "failed to bind query", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	boosts, total, err := h.BoostService.FindAll(c, userID.(string), req)
+	if err != nil {
+		logger.Errorln(c, "failed to find boosts", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding boosts",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	req.Normalize()
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    boosts,
+		Meta:    req.ToMeta(total),
+	})
+}
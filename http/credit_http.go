@@ -0,0 +1,86 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/service"
+	"github.com/marvelalexius/jones/utils"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+func (h *HTTPService) CreditBalances(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when getting credits",
+		})
+
+		return
+	}
+
+	balances, err := h.CreditService.Balances(c, userID.(string))
+	if err != nil {
+		logger.Errorln(c, "failed to get credits", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when getting credits",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    balances,
+	})
+}
+
+func (h *HTTPService) PurchaseCredits(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when purchasing credits",
+		})
+
+		return
+	}
+
+	var req model.CreditPurchaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorln(c, "failed to bind json", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	checkoutUrl, err := h.CreditService.Purchase(c, userID.(string), req)
+	if err != nil {
+		logger.Errorln(c, "failed to purchase credits", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrPaymentsDisabled) {
+			status = http.StatusServiceUnavailable
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when purchasing credits",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    map[string]interface{}{"checkout_url": checkoutUrl},
+	})
+}
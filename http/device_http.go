@@ -0,0 +1,85 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/service"
+	"github.com/marvelalexius/jones/utils"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+func (h *HTTPService) RegisterDevice(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when registering device",
+		})
+
+		return
+	}
+
+	var req model.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorln(c, "failed to bind json", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	device, err := h.DeviceService.Register(c, userID.(string), req)
+	if err != nil {
+		logger.Errorln(c, "failed to register device", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when registering device",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    device,
+	})
+}
+
+func (h *HTTPService) UnregisterDevice(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when unregistering device",
+		})
+
+		return
+	}
+
+	err := h.DeviceService.Unregister(c, userID.(string), c.Param("token"))
+	if err != nil {
+		logger.Errorln(c, "failed to unregister device", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrDeviceNotFound) {
+			status = http.StatusNotFound
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when unregistering device",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+	})
+}
@@ -4,18 +4,29 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/marvelalexius/jones/config"
 	"github.com/marvelalexius/jones/http/middleware"
+	"github.com/marvelalexius/jones/pkg/realtime"
 	"github.com/marvelalexius/jones/service"
 )
 
 type HTTPService struct {
-	Conf                *config.Config
-	UserService         service.IUserService
-	ReactionService     service.IReactionService
-	SubscriptionService service.ISubscriptionService
+	Conf                          *config.Config
+	UserService                   service.IUserService
+	ReactionService               service.IReactionService
+	SubscriptionService           service.ISubscriptionService
+	TopPickService                service.ITopPickService
+	MatchService                  service.IMatchService
+	CreditService                 service.ICreditService
+	QuotaService                  service.IQuotaService
+	BoostService                  service.IBoostService
+	MessageService                service.IMessageService
+	NotificationService           service.INotificationService
+	DeviceService                 service.IDeviceService
+	NotificationPreferenceService service.INotificationPreferenceService
+	Hub                           *realtime.Hub
 }
 
-func NewHTTPService(appconf *config.Config, userService *service.IUserService, reactionService *service.IReactionService, subscriptionService *service.ISubscriptionService) *HTTPService {
-	return &HTTPService{Conf: appconf, UserService: *userService, ReactionService: *reactionService, SubscriptionService: *subscriptionService}
+func NewHTTPService(appconf *config.Config, userService *service.IUserService, reactionService *service.IReactionService, subscriptionService *service.ISubscriptionService, topPickService *service.ITopPickService, matchService *service.IMatchService, creditService *service.ICreditService, quotaService *service.IQuotaService, boostService *service.IBoostService, messageService *service.IMessageService, notificationService *service.INotificationService, deviceService *service.IDeviceService, notificationPreferenceService *service.INotificationPreferenceService, hub *realtime.Hub) *HTTPService {
+	return &HTTPService{Conf: appconf, UserService: *userService, ReactionService: *reactionService, SubscriptionService: *subscriptionService, TopPickService: *topPickService, MatchService: *matchService, CreditService: *creditService, QuotaService: *quotaService, BoostService: *boostService, MessageService: *messageService, NotificationService: *notificationService, DeviceService: *deviceService, NotificationPreferenceService: *notificationPreferenceService, Hub: hub}
 }
 
 func (h *HTTPService) Routes(route *gin.Engine) {
@@ -27,11 +38,50 @@ func (h *HTTPService) Routes(route *gin.Engine) {
 			v1.POST("/auth/login", h.Login)
 			v1.POST("/auth/refresh", h.RefreshAuthToken)
 
+			// signed URLs authenticate attachments, they're loaded where the access token can't be sent
+			v1.GET("/attachments/:id", h.ServeAttachment)
+			v1.GET("/reactions/likes/teasers/:id", h.ServeLikeTeaser)
+
 			authed := v1.Group("").Use(middleware.JWTAuthMiddleware(h.Conf))
 			authed.GET("/users", h.FindAllUsers)
+			authed.GET("/users/top-picks", h.TopPicks)
+			authed.GET("/me/quotas", h.Quotas)
+			authed.PUT("/me/timezone", h.UpdateTimezone)
+			authed.PUT("/me/language", h.UpdateLanguage)
+			authed.POST("/me/devices", h.RegisterDevice)
+			authed.DELETE("/me/devices/:token", h.UnregisterDevice)
+			authed.GET("/me/notification-preferences", h.FindNotificationPreferences)
+			authed.PUT("/me/notification-preferences", h.UpdateNotificationPreferences)
+			authed.PUT("/me/quiet-hours", h.UpdateQuietHours)
+			authed.DELETE("/me/quiet-hours", h.ClearQuietHours)
 			authed.POST("/reactions", h.React)
+			authed.POST("/reactions/batch", h.BatchReact)
+			authed.POST("/reactions/undo", h.Rewind)
+			authed.POST("/reactions/start-over", h.StartOver)
 			authed.GET("/reactions/likes", h.SeeLikes)
+			authed.GET("/reactions/likes/summary", h.SummarizeLikes)
+			authed.GET("/reactions/sent", h.FindSentReactions)
+			authed.GET("/matches", h.FindAllMatches)
+			authed.GET("/matches/:id", h.FindMatch)
+			authed.DELETE("/matches/:id", h.Unmatch)
+			authed.POST("/matches/:id/extend", h.ExtendMatch)
+			authed.GET("/matches/:id/messages", h.FindMessages)
+			authed.POST("/matches/:id/messages", h.SendMessage)
+			authed.POST("/matches/:id/messages/attachments", h.SendAttachment)
+			authed.DELETE("/matches/:id/messages/:messageId", h.DeleteMessage)
+			authed.GET("/conversations", h.FindAllConversations)
+			authed.GET("/ws", h.WebSocket)
+			authed.GET("/notifications", h.FindAllNotifications)
+			authed.GET("/notifications/unread-count", h.CountUnreadNotifications)
+			authed.GET("/notifications/stream", h.StreamNotifications)
+			authed.PATCH("/notifications/:id/read", h.MarkNotificationRead)
+			authed.POST("/notifications/read-all", h.MarkAllNotificationsRead)
+			authed.DELETE("/notifications/:id", h.DeleteNotification)
 			authed.POST("/subscription", h.Subscribe)
+			authed.POST("/boosts", h.ActivateBoost)
+			authed.GET("/boosts", h.FindAllBoosts)
+			authed.GET("/credits", h.CreditBalances)
+			authed.POST("/credits/purchase", h.PurchaseCredits)
 
 			if h.Conf.FeatureFlag.EnableStripe {
 				v1.POST("/payment/callback", h.HandleCallback)
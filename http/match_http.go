@@ -0,0 +1,174 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/service"
+	"github.com/marvelalexius/jones/utils"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+func (h *HTTPService) FindAllMatches(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding matches",
+		})
+
+		return
+	}
+
+	var req model.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Errorln(c, "failed to bind query", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	matches, total, err := h.MatchService.FindAll(c, userID.(string), req)
+	if err != nil {
+		logger.Errorln(c, "failed to find matches", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding matches",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	req.Normalize()
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    matches,
+		Meta:    req.ToMeta(total),
+	})
+}
+
+func (h *HTTPService) FindMatch(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding match",
+		})
+
+		return
+	}
+
+	match, err := h.MatchService.FindByID(c, userID.(string), c.Param("id"))
+	if err != nil {
+		logger.Errorln(c, "failed to find match", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrMatchNotFound) {
+			status = http.StatusNotFound
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when finding match",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    match,
+	})
+}
+
+func (h *HTTPService) Unmatch(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when unmatching",
+		})
+
+		return
+	}
+
+	var req model.UnmatchRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Errorln(c, "failed to bind json", err)
+			ve := utils.ValidationResponse(err)
+			utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+				Message: "something went wrong when validating the requests",
+				Errors:  ve,
+			})
+
+			return
+		}
+	}
+
+	err := h.MatchService.Unmatch(c, userID.(string), c.Param("id"), req)
+	if err != nil {
+		logger.Errorln(c, "failed to unmatch", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrMatchNotFound) {
+			status = http.StatusNotFound
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when unmatching",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+	})
+}
+
+func (h *HTTPService) ExtendMatch(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when extending match",
+		})
+
+		return
+	}
+
+	match, err := h.MatchService.Extend(c, userID.(string), c.Param("id"))
+	if err != nil {
+		logger.Errorln(c, "failed to extend match", err)
+
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrMatchNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrExtendNotAllowed):
+			status = http.StatusForbidden
+		case errors.Is(err, service.ErrMatchNotExpiring), errors.Is(err, service.ErrMatchAlreadyExtended):
+			status = http.StatusConflict
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when extending match",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    match,
+	})
+}
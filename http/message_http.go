@@ -0,0 +1,311 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/service"
+	"github.com/marvelalexius/jones/utils"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+func (h *HTTPService) FindAllConversations(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding conversations",
+		})
+
+		return
+	}
+
+	var req model.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Errorln(c, "failed to bind query", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	conversations, total, err := h.MessageService.FindConversations(c, userID.(string), req)
+	if err != nil {
+		logger.Errorln(c, "failed to find conversations", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding conversations",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	req.Normalize()
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    conversations,
+		Meta:    req.ToMeta(total),
+	})
+}
+
+func (h *HTTPService) FindMessages(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding messages",
+		})
+
+		return
+	}
+
+	var req model.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Errorln(c, "failed to bind query", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	messages, total, err := h.MessageService.FindMessages(c, userID.(string), c.Param("id"), req)
+	if err != nil {
+		logger.Errorln(c, "failed to find messages", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrMatchNotFound) {
+			status = http.StatusNotFound
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when finding messages",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	req.Normalize()
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    messages,
+		Meta:    req.ToMeta(total),
+	})
+}
+
+func (h *HTTPService) SendMessage(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when sending message",
+		})
+
+		return
+	}
+
+	var req model.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorln(c, "failed to bind json", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	message, err := h.MessageService.Send(c, userID.(string), c.Param("id"), req)
+	if err != nil {
+		logger.Errorln(c, "failed to send message", err)
+
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrMatchNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrEmptyMessage):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrMessageRejected):
+			status = http.StatusUnprocessableEntity
+		case errors.Is(err, service.ErrNotYourMove):
+			status = http.StatusForbidden
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when sending message",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    message,
+	})
+}
+
+func (h *HTTPService) SendAttachment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when sending attachment",
+		})
+
+		return
+	}
+
+	// the form is parsed to disk, an oversized upload is cut off before it's written out
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, model.MaxAttachmentUploadSize)
+
+	var req model.SendAttachmentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		logger.Errorln(c, "failed to bind form", err)
+
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, utils.ErrorRes{
+				Message: "something went wrong when sending attachment",
+				Errors:  service.ErrAttachmentTooLarge.Error(),
+			})
+
+			return
+		}
+
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		logger.Errorln(c, "failed to get attachment file", err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  []utils.ValidationErrorMsg{{Field: "file", Message: "This field is required"}},
+		})
+
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Errorln(c, "failed to open attachment file", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when sending attachment",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+	defer file.Close()
+
+	message, err := h.MessageService.SendAttachment(c, userID.(string), c.Param("id"), req, file, fileHeader.Size)
+	if err != nil {
+		logger.Errorln(c, "failed to send attachment", err)
+
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrMatchNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrEmptyAttachment), errors.Is(err, service.ErrVoiceNoteTooLong):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrAttachmentTooLarge):
+			status = http.StatusRequestEntityTooLarge
+		case errors.Is(err, service.ErrUnsupportedAttachment):
+			status = http.StatusUnsupportedMediaType
+		case errors.Is(err, service.ErrMessageRejected):
+			status = http.StatusUnprocessableEntity
+		case errors.Is(err, service.ErrNotYourMove):
+			status = http.StatusForbidden
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when sending attachment",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    message,
+	})
+}
+
+func (h *HTTPService) ServeAttachment(c *gin.Context) {
+	attachment, file, err := h.MessageService.OpenAttachment(c, c.Param("id"), c.Request.URL.Query())
+	if err != nil {
+		logger.Errorln(c, "failed to open attachment", err)
+
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidAttachmentURL):
+			status = http.StatusForbidden
+		case errors.Is(err, service.ErrAttachmentNotFound):
+			status = http.StatusNotFound
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when opening attachment",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, file, map[string]string{
+		// the URL stops working soon, nothing else should keep a copy
+		"Cache-Control":          "private, max-age=300",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (h *HTTPService) DeleteMessage(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when deleting message",
+		})
+
+		return
+	}
+
+	err := h.MessageService.Delete(c, userID.(string), c.Param("id"), c.Param("messageId"))
+	if err != nil {
+		logger.Errorln(c, "failed to delete message", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrMatchNotFound) || errors.Is(err, service.ErrMessageNotFound) {
+			status = http.StatusNotFound
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when deleting message",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+	})
+}
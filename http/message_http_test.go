@@ -0,0 +1,109 @@
+package http
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marvelalexius/jones/mocks"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testRouter serves a handler as the given user, the way it's reached behind the JWT middleware.
+func testRouter(method, path string, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	route := gin.New()
+	route.Handle(method, path, func(c *gin.Context) {
+		c.Set("userID", "user1")
+		handler(c)
+	})
+
+	return route
+}
+
+func attachmentForm(t *testing.T, size int64) (*bytes.Buffer, string) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	assert.NoError(t, form.WriteField("kind", model.AttachmentPhoto))
+
+	file, err := form.CreateFormFile("file", "photo.jpg")
+	assert.NoError(t, err)
+	_, err = file.Write(make([]byte, size))
+	assert.NoError(t, err)
+	assert.NoError(t, form.Close())
+
+	return &body, form.FormDataContentType()
+}
+
+func TestHTTPService_SendMessage(t *testing.T) {
+	tests := []struct {
+		name           string
+		sendErr        error
+		expectedStatus int
+	}{
+		{name: "Success", expectedStatus: http.StatusOK},
+		{name: "Error - Not Your Move", sendErr: service.ErrNotYourMove, expectedStatus: http.StatusForbidden},
+		{name: "Error - Match Not Found", sendErr: service.ErrMatchNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messageService := new(mocks.IMessageService)
+			messageService.On("Send", mock.Anything, "user1", "match1", model.SendMessageRequest{Body: "hi"}).Return(model.Message{ID: "message1"}, tt.sendErr)
+
+			h := &HTTPService{MessageService: messageService}
+			route := testRouter(http.MethodPost, "/matches/:id/messages", h.SendMessage)
+
+			req := httptest.NewRequest(http.MethodPost, "/matches/match1/messages", strings.NewReader(`{"body":"hi"}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			route.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			messageService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHTTPService_SendAttachment(t *testing.T) {
+	tests := []struct {
+		name           string
+		size           int64
+		sendErr        error
+		expectSend     bool
+		expectedStatus int
+	}{
+		{name: "Success", size: 10, expectSend: true, expectedStatus: http.StatusOK},
+		{name: "Error - Not Your Move", size: 10, sendErr: service.ErrNotYourMove, expectSend: true, expectedStatus: http.StatusForbidden},
+		{name: "Error - Upload Too Large", size: model.MaxAttachmentUploadSize, expectedStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messageService := new(mocks.IMessageService)
+			if tt.expectSend {
+				messageService.On("SendAttachment", mock.Anything, "user1", "match1", model.SendAttachmentRequest{Kind: model.AttachmentPhoto}, mock.Anything, tt.size).Return(model.Message{ID: "message1"}, tt.sendErr)
+			}
+
+			h := &HTTPService{MessageService: messageService}
+			route := testRouter(http.MethodPost, "/matches/:id/messages/attachments", h.SendAttachment)
+
+			body, contentType := attachmentForm(t, tt.size)
+			req := httptest.NewRequest(http.MethodPost, "/matches/match1/messages/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			rec := httptest.NewRecorder()
+			route.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			messageService.AssertExpectations(t)
+		})
+	}
+}
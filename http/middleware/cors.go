@@ -2,13 +2,23 @@ package middleware
 
 import (
 	"log"
+	"net/http"
+	"net/url"
+	"slices"
 
 	"github.com/gin-gonic/gin"
 )
 
-func CORS() gin.HandlerFunc {
+func CORS(origins []string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		ctx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		if len(origins) == 0 {
+			ctx.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			ctx.Writer.Header().Add("Vary", "Origin")
+			if origin := ctx.Request.Header.Get("Origin"); slices.Contains(origins, origin) {
+				ctx.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+		}
 		ctx.Writer.Header().Set("Access-Control-Max-Age", "86400")
 		ctx.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, PATCH, DELETE, UPDATE")
 		ctx.Writer.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Authorization, Refresh-Token, X-Retry")
@@ -24,3 +34,20 @@ func CORS() gin.HandlerFunc {
 		}
 	}
 }
+
+// AllowedOrigin reports whether a browser on the request's origin may open a stream. Requests without an
+// Origin don't come from a browser, and without an allow-list only the api's own origin is trusted.
+func AllowedOrigin(origins []string, req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	if len(origins) > 0 {
+		return slices.Contains(origins, origin)
+	}
+
+	u, err := url.Parse(origin)
+
+	return err == nil && u.Host == req.Host
+}
@@ -22,6 +22,11 @@ func extractToken(c *gin.Context) (string, error) {
 		bearerToken = c.Request.Header.Get("X-Authorization")
 	}
 
+	// browsers can't set headers on a websocket handshake or an EventSource
+	if bearerToken == "" && (strings.EqualFold(c.Request.Header.Get("Upgrade"), "websocket") || strings.Contains(c.Request.Header.Get("Accept"), "text/event-stream")) {
+		bearerToken = c.Query("access_token")
+	}
+
 	if len(strings.Split(bearerToken, " ")) == 2 {
 		bearerToken = strings.Split(bearerToken, " ")[1]
 	}
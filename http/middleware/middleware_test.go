@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactQuery(t *testing.T) {
+	assert.Equal(t, "/api/v1/ws", redactQuery("/api/v1/ws"))
+	assert.Equal(t, "/api/v1/ws?access_token=REDACTED", redactQuery("/api/v1/ws?access_token=eyJhbGciOiJIUzI1NiJ9.e30.sig"))
+	assert.Equal(t, "/api/v1/notifications/stream?access_token=REDACTED&last_event_id=42", redactQuery("/api/v1/notifications/stream?last_event_id=42&access_token=eyJhbGciOiJIUzI1NiJ9.e30.sig"))
+	assert.Equal(t, "/api/v1/users?page=2", redactQuery("/api/v1/users?page=2"))
+}
+
+func TestAllowedOrigin(t *testing.T) {
+	tests := []struct {
+		name     string
+		origins  []string
+		origin   string
+		expected bool
+	}{
+		{name: "no origin from mobile clients", origin: "", expected: true},
+		{name: "listed origin", origins: []string{"https://app.jones.test"}, origin: "https://app.jones.test", expected: true},
+		{name: "unlisted origin", origins: []string{"https://app.jones.test"}, origin: "https://evil.test", expected: false},
+		{name: "own origin without a list", origin: "https://api.jones.test", expected: true},
+		{name: "other origin without a list", origin: "https://evil.test", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "https://api.jones.test/api/v1/ws", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+
+			assert.Equal(t, tt.expected, AllowedOrigin(tt.origins, req))
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/realtime"
+	"github.com/marvelalexius/jones/service"
+	"github.com/marvelalexius/jones/utils"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+// sseHeartbeatInterval keeps idle streams open through proxies.
+const sseHeartbeatInterval = 30 * time.Second
+
+func (h *HTTPService) StreamNotifications(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when streaming notifications",
+		})
+
+		return
+	}
+
+	// EventSource sends the header on reconnects, the query lets a fresh page resume too
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	// registered before the replay so nothing created in between is missed, duplicates are skipped below
+	client := realtime.NewClient(userID.(string))
+	h.Hub.Register(client)
+	defer h.Hub.Unregister(client)
+
+	var missed []model.Notification
+	if lastEventID != "" {
+		var err error
+		missed, err = h.NotificationService.FindMissed(c, userID.(string), lastEventID)
+		if err != nil {
+			logger.Errorln(c, "failed to find missed notifications", err)
+
+			if errors.Is(err, service.ErrInvalidLastEventID) {
+				utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+					Message: err.Error(),
+				})
+
+				return
+			}
+
+			utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+				Message: "something went wrong when streaming notifications",
+				Errors:  err.Error(),
+			})
+
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// live events that came in while replaying may have been replayed already, they're skipped by ID.
+	// Sequences are handed out before commit, so a live one lower than the last replayed is still new
+	replayed := make(map[string]bool)
+	for len(missed) > 0 {
+		for _, notification := range missed {
+			renderNotification(c, notification)
+			replayed[notification.ID] = true
+			lastEventID = strconv.FormatInt(notification.Seq, 10)
+		}
+		c.Writer.Flush()
+
+		if len(missed) < model.NotificationReplayLimit {
+			break
+		}
+
+		var err error
+		missed, err = h.NotificationService.FindMissed(c, userID.(string), lastEventID)
+		if err != nil {
+			// the client reconnects with the last id it got and picks up from there
+			logger.Errorln(c, "failed to find missed notifications", err)
+
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(sseHeartbeatInterval)
+	defer ticker.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-client.Send:
+			if !ok {
+				return false
+			}
+
+			if event.Type != realtime.EventNotification {
+				return true
+			}
+
+			var notification model.Notification
+			if err := json.Unmarshal(event.Payload, &notification); err != nil {
+				logger.Errorln(c, "failed to decode notification event", err)
+
+				return true
+			}
+
+			if replayed[notification.ID] {
+				return true
+			}
+
+			renderNotification(c, notification)
+
+			return true
+		case <-ticker.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+
+			return err == nil
+		}
+	})
+}
+
+func (h *HTTPService) FindAllNotifications(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding notifications",
+		})
+
+		return
+	}
+
+	var req model.NotificationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Errorln(c, "failed to bind query", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	notifications, total, err := h.NotificationService.FindAll(c, userID.(string), req)
+	if err != nil {
+		logger.Errorln(c, "failed to find notifications", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding notifications",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	req.Normalize()
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    notifications,
+		Meta:    req.ToMeta(total),
+	})
+}
+
+func (h *HTTPService) CountUnreadNotifications(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when counting unread notifications",
+		})
+
+		return
+	}
+
+	unread, err := h.NotificationService.CountUnread(c, userID.(string))
+	if err != nil {
+		logger.Errorln(c, "failed to count unread notifications", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when counting unread notifications",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    map[string]interface{}{"unread": unread},
+	})
+}
+
+func (h *HTTPService) MarkNotificationRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when marking notification as read",
+		})
+
+		return
+	}
+
+	err := h.NotificationService.MarkRead(c, userID.(string), c.Param("id"))
+	if err != nil {
+		logger.Errorln(c, "failed to mark notification as read", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrNotificationNotFound) {
+			status = http.StatusNotFound
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when marking notification as read",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+	})
+}
+
+func (h *HTTPService) MarkAllNotificationsRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when marking notifications as read",
+		})
+
+		return
+	}
+
+	marked, err := h.NotificationService.MarkAllRead(c, userID.(string))
+	if err != nil {
+		logger.Errorln(c, "failed to mark notifications as read", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when marking notifications as read",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    map[string]interface{}{"marked": marked},
+	})
+}
+
+func (h *HTTPService) DeleteNotification(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when deleting notification",
+		})
+
+		return
+	}
+
+	err := h.NotificationService.Delete(c, userID.(string), c.Param("id"))
+	if err != nil {
+		logger.Errorln(c, "failed to delete notification", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrNotificationNotFound) {
+			status = http.StatusNotFound
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when deleting notification",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+	})
+}
+
+func renderNotification(c *gin.Context, notification model.Notification) {
+	c.Render(-1, sse.Event{
+		Id:    strconv.FormatInt(notification.Seq, 10),
+		Event: realtime.EventNotification,
+		Data:  notification,
+	})
+}
@@ -0,0 +1,153 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/service"
+	"github.com/marvelalexius/jones/utils"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+func (h *HTTPService) FindNotificationPreferences(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding notification preferences",
+		})
+
+		return
+	}
+
+	preferences, err := h.NotificationPreferenceService.Find(c, userID.(string))
+	if err != nil {
+		logger.Errorln(c, "failed to find notification preferences", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding notification preferences",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    preferences,
+	})
+}
+
+func (h *HTTPService) UpdateNotificationPreferences(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when updating notification preferences",
+		})
+
+		return
+	}
+
+	var req model.UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorln(c, "failed to bind json", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	preferences, err := h.NotificationPreferenceService.Update(c, userID.(string), req)
+	if err != nil {
+		logger.Errorln(c, "failed to update notification preferences", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrNotificationPreferenceNotAllowed) {
+			status = http.StatusBadRequest
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when updating notification preferences",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    preferences,
+	})
+}
+
+func (h *HTTPService) UpdateQuietHours(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when updating quiet hours",
+		})
+
+		return
+	}
+
+	var req model.UpdateQuietHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorln(c, "failed to bind json", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	preferences, err := h.NotificationPreferenceService.UpdateQuietHours(c, userID.(string), req)
+	if err != nil {
+		logger.Errorln(c, "failed to update quiet hours", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when updating quiet hours",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    preferences,
+	})
+}
+
+func (h *HTTPService) ClearQuietHours(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when clearing quiet hours",
+		})
+
+		return
+	}
+
+	err := h.NotificationPreferenceService.ClearQuietHours(c, userID.(string))
+	if err != nil {
+		logger.Errorln(c, "failed to clear quiet hours", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when clearing quiet hours",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+	})
+}
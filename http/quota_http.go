@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/utils"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+func (h *HTTPService) Quotas(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding quotas",
+		})
+
+		return
+	}
+
+	quotas, err := h.QuotaService.FindAll(c, userID.(string))
+	if err != nil {
+		logger.Errorln(c, "failed to find quotas", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding quotas",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    quotas,
+	})
+}
+
+// setRateLimitHeaders reports the user's swipe quota, nothing is set when swipes are unlimited.
+func (h *HTTPService) setRateLimitHeaders(c *gin.Context, userID string) {
+	plan, err := h.QuotaService.FindPlan(c, userID)
+	if err != nil {
+		logger.Errorln(c, "failed to find plan", err)
+
+		return
+	}
+
+	quota, err := h.QuotaService.Check(c, userID, plan, model.QuotaSwipe)
+	if err != nil {
+		logger.Errorln(c, "failed to check swipe quota", err)
+
+		return
+	}
+
+	if quota.Limit == nil {
+		return
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(*quota.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.FormatInt(*quota.Remaining, 10))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(quota.ResetAt.Unix(), 10))
+}
@@ -1,10 +1,12 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/service"
 	"github.com/marvelalexius/jones/utils"
 	"github.com/marvelalexius/jones/utils/logger"
 )
@@ -20,7 +22,19 @@ func (h *HTTPService) FindAllUsers(c *gin.Context) {
 		return
 	}
 
-	users, count, err := h.UserService.FindAll(c, userID.(string))
+	var req model.PaginationRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Errorln(c, "failed to bind query", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	users, count, err := h.UserService.FindAll(c, userID.(string), req)
 	if err != nil {
 		logger.Errorln(c, "failed to find all users", err)
 		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
@@ -31,12 +45,11 @@ func (h *HTTPService) FindAllUsers(c *gin.Context) {
 		return
 	}
 
+	req.Normalize()
 	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
 		Message: "success",
 		Data:    users,
-		Meta: map[string]interface{}{
-			"total": count,
-		},
+		Meta:    req.ToMeta(count),
 	})
 }
 
@@ -65,10 +78,106 @@ func (h *HTTPService) React(c *gin.Context) {
 
 	req.UserID = userID.(string)
 	reaction, err := h.ReactionService.Swipe(c, req)
+	h.setRateLimitHeaders(c, req.UserID)
 	if err != nil {
 		logger.Errorln(c, "failed to swipe", err)
+
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrSwipeLimitReached):
+			status = http.StatusTooManyRequests
+		case errors.Is(err, service.ErrCommentNotAllowed), errors.Is(err, service.ErrInvalidLikeTarget), errors.Is(err, service.ErrCannotSwipeSelf):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrSwipeTargetNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrSwipeTargetNotEligible), errors.Is(err, service.ErrInvalidDeckToken):
+			status = http.StatusForbidden
+		case errors.Is(err, service.ErrCommentRejected):
+			status = http.StatusUnprocessableEntity
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when swiping",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    reaction,
+	})
+}
+
+func (h *HTTPService) BatchReact(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
 		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
 			Message: "something went wrong when swiping",
+		})
+
+		return
+	}
+
+	var req model.BatchReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorln(c, "failed to bind json", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	results, err := h.ReactionService.BatchSwipe(c, userID.(string), req.Reactions)
+	h.setRateLimitHeaders(c, userID.(string))
+	if err != nil {
+		logger.Errorln(c, "failed to batch swipe", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when swiping",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    map[string]interface{}{"results": results},
+	})
+}
+
+func (h *HTTPService) Rewind(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when rewinding",
+		})
+
+		return
+	}
+
+	reaction, err := h.ReactionService.Rewind(c, userID.(string))
+	if err != nil {
+		logger.Errorln(c, "failed to rewind", err)
+
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrRewindNotAllowed):
+			status = http.StatusForbidden
+		case errors.Is(err, service.ErrNothingToRewind):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrRewindLimitReached):
+			status = http.StatusTooManyRequests
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when rewinding",
 			Errors:  err.Error(),
 		})
 
@@ -81,6 +190,143 @@ func (h *HTTPService) React(c *gin.Context) {
 	})
 }
 
+func (h *HTTPService) StartOver(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when starting over",
+		})
+
+		return
+	}
+
+	err := h.ReactionService.StartOver(c, userID.(string))
+	if err != nil {
+		logger.Errorln(c, "failed to start over", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrStartOverNotAllowed) {
+			status = http.StatusForbidden
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when starting over",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+	})
+}
+
+func (h *HTTPService) FindSentReactions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when finding sent reactions",
+		})
+
+		return
+	}
+
+	var req model.SentReactionRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Errorln(c, "failed to bind query", err)
+		ve := utils.ValidationResponse(err)
+		utils.ErrorResponse(c, http.StatusBadRequest, utils.ErrorRes{
+			Message: "something went wrong when validating the requests",
+			Errors:  ve,
+		})
+
+		return
+	}
+
+	reactions, total, err := h.ReactionService.FindSent(c, userID.(string), req)
+	if err != nil {
+		logger.Errorln(c, "failed to find sent reactions", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidDateRange) {
+			status = http.StatusBadRequest
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when finding sent reactions",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	req.Normalize()
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    reactions,
+		Meta:    req.ToMeta(total),
+	})
+}
+
+func (h *HTTPService) SummarizeLikes(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when summarizing likes",
+		})
+
+		return
+	}
+
+	summary, err := h.ReactionService.SummarizeLikes(c, userID.(string))
+	if err != nil {
+		logger.Errorln(c, "failed to summarize likes", err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when summarizing likes",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, utils.SuccessRes{
+		Message: "success",
+		Data:    summary,
+	})
+}
+
+func (h *HTTPService) ServeLikeTeaser(c *gin.Context) {
+	image, err := h.ReactionService.OpenLikeTeaser(c, c.Param("id"), c.Request.URL.Query())
+	if err != nil {
+		logger.Errorln(c, "failed to open teaser", err)
+
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidTeaserURL):
+			status = http.StatusForbidden
+		case errors.Is(err, service.ErrTeaserNotFound):
+			status = http.StatusNotFound
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
+			Message: "something went wrong when opening teaser",
+			Errors:  err.Error(),
+		})
+
+		return
+	}
+	defer image.Body.Close()
+
+	c.DataFromReader(http.StatusOK, image.Size, image.ContentType, image.Body, map[string]string{
+		"Cache-Control":          "private, max-age=300",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
 func (h *HTTPService) SeeLikes(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -95,7 +341,13 @@ func (h *HTTPService) SeeLikes(c *gin.Context) {
 	likes, err := h.ReactionService.SeeLikes(c, userID.(string))
 	if err != nil {
 		logger.Errorln(c, "failed to see likes", err)
-		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrSeeLikesNotAllowed) {
+			status = http.StatusForbidden
+		}
+
+		utils.ErrorResponse(c, status, utils.ErrorRes{
 			Message: "something went wrong when seeing likes",
 			Errors:  err.Error(),
 		})
@@ -173,6 +173,23 @@ func (h *HTTPService) HandleCallback(c *gin.Context) {
 		if err != nil {
 			logger.Errorln(c.Request.Context(), err)
 		}
+	case "checkout.session.completed", "checkout.session.async_payment_succeeded":
+		var session stripe.CheckoutSession
+
+		if err := json.Unmarshal(event.Data.Raw, &session); err != nil {
+			logger.Errorln(c, "failed to unmarshal checkout session", err)
+			utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+				Message: "something went wrong when unmarshaling checkout session",
+				Errors:  err,
+			})
+
+			return
+		}
+
+		err = h.CreditService.HandleCheckoutSessionCompleted(c, &session)
+		if err != nil {
+			logger.Errorln(c.Request.Context(), err)
+		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/marvelalexius/jones/http/middleware"
+	"github.com/marvelalexius/jones/pkg/realtime"
+	"github.com/marvelalexius/jones/utils"
+	"github.com/marvelalexius/jones/utils/logger"
+	"golang.org/x/net/websocket"
+)
+
+// wsPingInterval keeps idle connections alive through proxies and finds devices that went away.
+const wsPingInterval = 30 * time.Second
+
+// wsTypingInterval is how often a connection's typing indicator for a match is passed on. Clients send one on
+// every keystroke and each looks the match up.
+const wsTypingInterval = 3 * time.Second
+
+// wsFrame is what clients send over the socket, typing indicators and read receipts for a match.
+type wsFrame struct {
+	Type    string `json:"type"`
+	MatchID string `json:"match_id"`
+}
+
+func (h *HTTPService) WebSocket(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		logger.Errorln(c, "failed to get user id from context")
+		utils.ErrorResponse(c, http.StatusInternalServerError, utils.ErrorRes{
+			Message: "something went wrong when connecting",
+		})
+
+		return
+	}
+
+	server := websocket.Server{
+		// any page can open a socket from a browser, only trusted origins get one. Mobile clients send no Origin
+		Handshake: func(_ *websocket.Config, req *http.Request) error {
+			if !middleware.AllowedOrigin(h.Conf.App.AllowedOrigins, req) {
+				return errors.New("origin not allowed")
+			}
+
+			return nil
+		},
+		Handler: func(conn *websocket.Conn) {
+			h.serveWebSocket(conn, userID.(string))
+		},
+	}
+
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *HTTPService) serveWebSocket(conn *websocket.Conn, userID string) {
+	defer conn.Close()
+
+	client := realtime.NewClient(userID)
+	h.Hub.Register(client)
+	defer h.Hub.Unregister(client)
+
+	go h.writeWebSocket(conn, client)
+
+	ctx := context.Background()
+	typedAt := map[string]time.Time{}
+	for {
+		var frame wsFrame
+		err := websocket.JSON.Receive(conn, &frame)
+		if err != nil {
+			return
+		}
+
+		switch frame.Type {
+		case realtime.EventTyping:
+			now := time.Now()
+			if now.Sub(typedAt[frame.MatchID]) < wsTypingInterval {
+				continue
+			}
+
+			err = h.MessageService.Typing(ctx, userID, frame.MatchID)
+			if err == nil {
+				typedAt[frame.MatchID] = now
+			}
+		case realtime.EventRead:
+			err = h.MessageService.MarkRead(ctx, userID, frame.MatchID)
+		default:
+			continue
+		}
+
+		if err != nil {
+			logger.Errorln(ctx, "failed to handle websocket frame", err)
+		}
+	}
+}
+
+// writeWebSocket drains the client's events until the hub drops it or the connection breaks.
+func (h *HTTPService) writeWebSocket(conn *websocket.Conn, client *realtime.Client) {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event, ok := <-client.Send:
+			if !ok {
+				conn.Close()
+
+				return
+			}
+
+			if err := websocket.JSON.Send(conn, event); err != nil {
+				conn.Close()
+
+				return
+			}
+		case <-ticker.C:
+			if err := websocket.JSON.Send(conn, realtime.Event{Type: realtime.EventPing}); err != nil {
+				conn.Close()
+
+				return
+			}
+		}
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"os"
+	// embed the timezone database, daily limits are computed in each user's timezone
+	_ "time/tzdata"
 
 	"github.com/marvelalexius/jones/cmd"
 	"github.com/sirupsen/logrus"
@@ -0,0 +1,20 @@
+-- migrate:up
+  CREATE TABLE IF NOT EXISTS top_picks (
+    id VARCHAR(26) NOT NULL,
+    user_id VARCHAR(26) NOT NULL,
+    picked_user_id VARCHAR(26) NOT NULL,
+    score INT NOT NULL DEFAULT 0,
+    expired_at TIMESTAMP NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT top_picks_id_pkey PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (picked_user_id) REFERENCES users(id)
+  );
+
+  CREATE INDEX IF NOT EXISTS top_picks_user_id_expired_at_idx ON top_picks (user_id, expired_at);
+
+-- migrate:down
+  DROP TABLE IF EXISTS top_picks;
\ No newline at end of file
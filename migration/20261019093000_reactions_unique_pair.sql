@@ -0,0 +1,13 @@
+-- migrate:up
+  DELETE FROM reactions r
+  USING reactions d
+  WHERE r.user_id = d.user_id
+    AND r.matched_user_id = d.matched_user_id
+    AND r.deleted_at IS NULL
+    AND d.deleted_at IS NULL
+    AND r.id > d.id;
+
+  CREATE UNIQUE INDEX IF NOT EXISTS reactions_user_id_matched_user_id_key ON reactions (user_id, matched_user_id) WHERE deleted_at IS NULL;
+
+-- migrate:down
+  DROP INDEX IF EXISTS reactions_user_id_matched_user_id_key;
\ No newline at end of file
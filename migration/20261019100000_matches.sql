@@ -0,0 +1,27 @@
+-- migrate:up
+  CREATE TABLE IF NOT EXISTS matches (
+    id VARCHAR(26) NOT NULL,
+    user_id VARCHAR(26) NOT NULL,
+    matched_user_id VARCHAR(26) NOT NULL,
+    status VARCHAR(35) NOT NULL,
+    matched_at TIMESTAMP NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT matches_id_pkey PRIMARY KEY (id),
+    CONSTRAINT matches_user_id_matched_user_id_key UNIQUE (user_id, matched_user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (matched_user_id) REFERENCES users(id)
+  );
+
+  CREATE INDEX IF NOT EXISTS matches_matched_user_id_idx ON matches (matched_user_id);
+
+  INSERT INTO matches (id, user_id, matched_user_id, status, matched_at)
+  SELECT id, user_id, matched_user_id, 'ACTIVE', matched_at
+  FROM reactions
+  WHERE matched_at IS NOT NULL AND user_id < matched_user_id AND deleted_at IS NULL
+  ON CONFLICT DO NOTHING;
+
+-- migrate:down
+  DROP TABLE IF EXISTS matches;
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- migrate:up
+  ALTER TABLE matches
+    ADD COLUMN IF NOT EXISTS unmatched_at TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS unmatched_by VARCHAR(26) NULL,
+    ADD COLUMN IF NOT EXISTS unmatch_reason VARCHAR(35) NULL,
+    ADD COLUMN IF NOT EXISTS unmatch_detail TEXT NULL;
+
+-- migrate:down
+  ALTER TABLE matches
+    DROP COLUMN IF EXISTS unmatched_at,
+    DROP COLUMN IF EXISTS unmatched_by,
+    DROP COLUMN IF EXISTS unmatch_reason,
+    DROP COLUMN IF EXISTS unmatch_detail;
\ No newline at end of file
@@ -0,0 +1,28 @@
+-- migrate:up
+  ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS super_like_quota INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS super_like_period VARCHAR(10) NOT NULL DEFAULT 'WEEKLY';
+
+  CREATE TABLE IF NOT EXISTS credits (
+    id VARCHAR(26) NOT NULL,
+    user_id VARCHAR(26) NOT NULL,
+    type VARCHAR(35) NOT NULL,
+    amount INT NOT NULL,
+    reason VARCHAR(35) NOT NULL,
+    reference_id VARCHAR(255),
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT credits_id_pkey PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+  );
+
+  CREATE INDEX IF NOT EXISTS credits_user_id_type_idx ON credits (user_id, type);
+  CREATE UNIQUE INDEX IF NOT EXISTS credits_purchase_reference_id_key ON credits (reference_id) WHERE reason = 'PURCHASE';
+
+-- migrate:down
+  DROP TABLE IF EXISTS credits;
+
+  ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS super_like_quota,
+    DROP COLUMN IF EXISTS super_like_period;
\ No newline at end of file
@@ -0,0 +1,11 @@
+-- migrate:up
+  ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS reference_id VARCHAR(26) NOT NULL DEFAULT '';
+
+  CREATE INDEX IF NOT EXISTS notifications_reference_id_idx ON notifications (reference_id);
+
+-- migrate:down
+  DROP INDEX IF EXISTS notifications_reference_id_idx;
+
+  ALTER TABLE notifications
+    DROP COLUMN IF EXISTS reference_id;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- migrate:up
+  ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS daily_swipe_limit INT NULL,
+    ADD COLUMN IF NOT EXISTS daily_rewind_limit INT NULL;
+
+-- migrate:down
+  ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS daily_swipe_limit,
+    DROP COLUMN IF EXISTS daily_rewind_limit;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- migrate:up
+  ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- migrate:down
+  ALTER TABLE users
+    DROP COLUMN IF EXISTS timezone;
\ No newline at end of file
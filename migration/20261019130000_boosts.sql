@@ -0,0 +1,26 @@
+-- migrate:up
+  ALTER TABLE subscription_plans
+    ADD COLUMN IF NOT EXISTS weekly_boost_quota INT NOT NULL DEFAULT 0;
+
+  CREATE TABLE IF NOT EXISTS boosts (
+    id VARCHAR(26) NOT NULL,
+    user_id VARCHAR(26) NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    likes BIGINT NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP,
+
+    CONSTRAINT boosts_id_pkey PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+  );
+
+  CREATE INDEX IF NOT EXISTS boosts_user_id_ends_at_idx ON boosts (user_id, ends_at);
+
+-- migrate:down
+  DROP TABLE IF EXISTS boosts;
+
+  ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS weekly_boost_quota;
\ No newline at end of file
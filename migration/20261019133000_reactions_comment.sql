@@ -0,0 +1,11 @@
+-- migrate:up
+  ALTER TABLE reactions
+    ADD COLUMN IF NOT EXISTS comment VARCHAR(150) NULL,
+    ADD COLUMN IF NOT EXISTS target_type VARCHAR(35) NULL,
+    ADD COLUMN IF NOT EXISTS target_id VARCHAR(26) NULL;
+
+-- migrate:down
+  ALTER TABLE reactions
+    DROP COLUMN IF EXISTS target_id,
+    DROP COLUMN IF EXISTS target_type,
+    DROP COLUMN IF EXISTS comment;
\ No newline at end of file
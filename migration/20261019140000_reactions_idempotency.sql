@@ -0,0 +1,13 @@
+-- migrate:up
+  ALTER TABLE reactions
+    ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(64) NULL,
+    ADD COLUMN IF NOT EXISTS swiped_at TIMESTAMP NULL;
+
+  CREATE UNIQUE INDEX IF NOT EXISTS reactions_user_id_idempotency_key_key ON reactions (user_id, idempotency_key) WHERE idempotency_key IS NOT NULL;
+
+-- migrate:down
+  DROP INDEX IF EXISTS reactions_user_id_idempotency_key_key;
+
+  ALTER TABLE reactions
+    DROP COLUMN IF EXISTS swiped_at,
+    DROP COLUMN IF EXISTS idempotency_key;
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- migrate:up
+  ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS deck_reset_at TIMESTAMP NULL;
+
+  ALTER TABLE reactions
+    ADD COLUMN IF NOT EXISTS replaced_by VARCHAR(26) NULL;
+
+-- migrate:down
+  ALTER TABLE reactions
+    DROP COLUMN IF EXISTS replaced_by;
+
+  ALTER TABLE users
+    DROP COLUMN IF EXISTS deck_reset_at;
\ No newline at end of file
@@ -0,0 +1,43 @@
+-- migrate:up
+  CREATE TABLE IF NOT EXISTS conversations (
+    id VARCHAR(26) NOT NULL,
+    match_id VARCHAR(26) NOT NULL,
+    user_id VARCHAR(26) NOT NULL,
+    matched_user_id VARCHAR(26) NOT NULL,
+    last_message_at TIMESTAMP NULL,
+    user_last_read_at TIMESTAMP NULL,
+    matched_user_last_read_at TIMESTAMP NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP,
+
+    CONSTRAINT conversations_id_pkey PRIMARY KEY (id),
+    CONSTRAINT conversations_match_id_key UNIQUE (match_id),
+    FOREIGN KEY (match_id) REFERENCES matches(id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (matched_user_id) REFERENCES users(id)
+  );
+
+  CREATE INDEX IF NOT EXISTS conversations_user_id_idx ON conversations (user_id);
+  CREATE INDEX IF NOT EXISTS conversations_matched_user_id_idx ON conversations (matched_user_id);
+
+  CREATE TABLE IF NOT EXISTS messages (
+    id VARCHAR(26) NOT NULL,
+    conversation_id VARCHAR(26) NOT NULL,
+    sender_id VARCHAR(26) NOT NULL,
+    body VARCHAR(1000) NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+
+    CONSTRAINT messages_id_pkey PRIMARY KEY (id),
+    FOREIGN KEY (conversation_id) REFERENCES conversations(id),
+    FOREIGN KEY (sender_id) REFERENCES users(id)
+  );
+
+  CREATE INDEX IF NOT EXISTS messages_conversation_id_created_at_idx ON messages (conversation_id, created_at);
+
+-- migrate:down
+  DROP TABLE IF EXISTS messages;
+  DROP TABLE IF EXISTS conversations;
\ No newline at end of file
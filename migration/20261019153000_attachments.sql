@@ -0,0 +1,22 @@
+-- migrate:up
+  CREATE TABLE IF NOT EXISTS attachments (
+    id VARCHAR(26) NOT NULL,
+    message_id VARCHAR(26) NOT NULL,
+    match_id VARCHAR(26) NOT NULL,
+    kind VARCHAR(10) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    duration_ms INTEGER NULL,
+    storage_key VARCHAR(255) NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT attachments_id_pkey PRIMARY KEY (id),
+    FOREIGN KEY (message_id) REFERENCES messages(id),
+    FOREIGN KEY (match_id) REFERENCES matches(id)
+  );
+
+  CREATE INDEX IF NOT EXISTS attachments_message_id_idx ON attachments (message_id);
+
+-- migrate:down
+  DROP TABLE IF EXISTS attachments;
\ No newline at end of file
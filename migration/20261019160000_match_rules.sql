@@ -0,0 +1,29 @@
+-- migrate:up
+  ALTER TABLE matches
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS first_move_by VARCHAR(26) NULL REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS first_message_at TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS extended_at TIMESTAMP NULL;
+
+  -- like comments copied into a conversation don't count as its first message
+  UPDATE matches m
+  SET first_message_at = first.created_at
+  FROM (
+    SELECT c.match_id, MIN(msg.created_at) AS created_at
+    FROM messages msg
+    JOIN conversations c ON c.id = msg.conversation_id
+    WHERE msg.created_at >= c.created_at
+    GROUP BY c.match_id
+  ) first
+  WHERE first.match_id = m.id;
+
+  CREATE INDEX IF NOT EXISTS matches_expiring_idx ON matches (expires_at) WHERE status = 'ACTIVE' AND first_message_at IS NULL;
+
+-- migrate:down
+  DROP INDEX IF EXISTS matches_expiring_idx;
+
+  ALTER TABLE matches
+    DROP COLUMN IF EXISTS extended_at,
+    DROP COLUMN IF EXISTS first_message_at,
+    DROP COLUMN IF EXISTS first_move_by,
+    DROP COLUMN IF EXISTS expires_at;
\ No newline at end of file
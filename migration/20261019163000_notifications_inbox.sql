@@ -0,0 +1,15 @@
+-- migrate:up
+  ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS type VARCHAR(32) NOT NULL DEFAULT '';
+
+  UPDATE notifications
+    SET type = COALESCE(content->>'type', '')
+    WHERE type = '' AND content IS NOT NULL;
+
+  CREATE INDEX IF NOT EXISTS notifications_inbox_idx ON notifications (user_id, type, id) WHERE deleted_at IS NULL;
+
+-- migrate:down
+  DROP INDEX IF EXISTS notifications_inbox_idx;
+
+  ALTER TABLE notifications
+    DROP COLUMN IF EXISTS type;
\ No newline at end of file
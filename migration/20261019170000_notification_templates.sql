@@ -0,0 +1,13 @@
+-- migrate:up
+  ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS language VARCHAR(8) NOT NULL DEFAULT 'en';
+
+  UPDATE notifications SET type = 'MATCH' WHERE type = 'LIKE';
+  UPDATE notifications SET type = 'LIKE_RECEIVED' WHERE type = 'SUPER_LIKE';
+
+-- migrate:down
+  UPDATE notifications SET type = 'SUPER_LIKE' WHERE type = 'LIKE_RECEIVED';
+  UPDATE notifications SET type = 'LIKE' WHERE type = 'MATCH';
+
+  ALTER TABLE users
+    DROP COLUMN IF EXISTS language;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- migrate:up
+  CREATE TABLE IF NOT EXISTS devices (
+    id VARCHAR(26) NOT NULL,
+    user_id VARCHAR(26) NOT NULL,
+    platform VARCHAR(16) NOT NULL,
+    token VARCHAR(512) NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP,
+
+    CONSTRAINT devices_id_pkey PRIMARY KEY (id),
+    CONSTRAINT devices_token_key UNIQUE (token),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+  );
+
+  CREATE INDEX IF NOT EXISTS devices_user_id_idx ON devices (user_id);
+
+-- migrate:down
+  DROP TABLE IF EXISTS devices;
\ No newline at end of file
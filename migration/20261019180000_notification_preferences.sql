@@ -0,0 +1,44 @@
+-- migrate:up
+  ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS quiet_hours_start VARCHAR(5) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS quiet_hours_end VARCHAR(5) NOT NULL DEFAULT '';
+
+  CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id VARCHAR(26) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, type, channel),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+  );
+
+  CREATE TABLE IF NOT EXISTS deferred_notifications (
+    id VARCHAR(26) NOT NULL,
+    notification_id VARCHAR(26) NOT NULL,
+    user_id VARCHAR(26) NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    type VARCHAR(32) NOT NULL,
+    reference_id VARCHAR(26) NOT NULL DEFAULT '',
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    content TEXT NOT NULL,
+    deliver_at TIMESTAMP NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT deferred_notifications_id_pkey PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+  );
+
+  CREATE INDEX IF NOT EXISTS deferred_notifications_deliver_at_idx ON deferred_notifications (deliver_at, id);
+
+-- migrate:down
+  DROP TABLE IF EXISTS deferred_notifications;
+  DROP TABLE IF EXISTS notification_preferences;
+
+  ALTER TABLE users
+    DROP COLUMN IF EXISTS quiet_hours_start,
+    DROP COLUMN IF EXISTS quiet_hours_end;
\ No newline at end of file
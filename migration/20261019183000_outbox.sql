@@ -0,0 +1,35 @@
+-- migrate:up
+  CREATE TABLE IF NOT EXISTS outbox (
+    id VARCHAR(26) NOT NULL,
+    topic VARCHAR(32) NOT NULL,
+    reference_id VARCHAR(26) NOT NULL DEFAULT '',
+    payload TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    available_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT outbox_id_pkey PRIMARY KEY (id)
+  );
+
+  CREATE INDEX IF NOT EXISTS outbox_available_at_idx ON outbox (available_at, id);
+  CREATE INDEX IF NOT EXISTS outbox_reference_id_idx ON outbox (reference_id) WHERE reference_id <> '';
+
+  CREATE TABLE IF NOT EXISTS outbox_dead_letters (
+    id VARCHAR(26) NOT NULL,
+    topic VARCHAR(32) NOT NULL,
+    reference_id VARCHAR(26) NOT NULL DEFAULT '',
+    payload TEXT NOT NULL,
+    attempts INT NOT NULL,
+    last_error TEXT NOT NULL,
+
+    created_at TIMESTAMP NOT NULL,
+    failed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT outbox_dead_letters_id_pkey PRIMARY KEY (id)
+  );
+
+-- migrate:down
+  DROP TABLE IF EXISTS outbox_dead_letters;
+  DROP TABLE IF EXISTS outbox;
\ No newline at end of file
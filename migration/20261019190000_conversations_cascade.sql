@@ -0,0 +1,29 @@
+-- migrate:up
+  ALTER TABLE conversations
+    DROP CONSTRAINT IF EXISTS conversations_match_id_fkey,
+    ADD CONSTRAINT conversations_match_id_fkey FOREIGN KEY (match_id) REFERENCES matches(id) ON DELETE CASCADE;
+
+  ALTER TABLE messages
+    DROP CONSTRAINT IF EXISTS messages_conversation_id_fkey,
+    ADD CONSTRAINT messages_conversation_id_fkey FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE;
+
+  ALTER TABLE attachments
+    DROP CONSTRAINT IF EXISTS attachments_message_id_fkey,
+    ADD CONSTRAINT attachments_message_id_fkey FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE,
+    DROP CONSTRAINT IF EXISTS attachments_match_id_fkey,
+    ADD CONSTRAINT attachments_match_id_fkey FOREIGN KEY (match_id) REFERENCES matches(id) ON DELETE CASCADE;
+
+-- migrate:down
+  ALTER TABLE attachments
+    DROP CONSTRAINT IF EXISTS attachments_match_id_fkey,
+    ADD CONSTRAINT attachments_match_id_fkey FOREIGN KEY (match_id) REFERENCES matches(id),
+    DROP CONSTRAINT IF EXISTS attachments_message_id_fkey,
+    ADD CONSTRAINT attachments_message_id_fkey FOREIGN KEY (message_id) REFERENCES messages(id);
+
+  ALTER TABLE messages
+    DROP CONSTRAINT IF EXISTS messages_conversation_id_fkey,
+    ADD CONSTRAINT messages_conversation_id_fkey FOREIGN KEY (conversation_id) REFERENCES conversations(id);
+
+  ALTER TABLE conversations
+    DROP CONSTRAINT IF EXISTS conversations_match_id_fkey,
+    ADD CONSTRAINT conversations_match_id_fkey FOREIGN KEY (match_id) REFERENCES matches(id);
\ No newline at end of file
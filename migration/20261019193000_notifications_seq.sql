@@ -0,0 +1,11 @@
+-- migrate:up
+  ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS seq BIGSERIAL;
+
+  CREATE INDEX IF NOT EXISTS notifications_user_id_seq_idx ON notifications (user_id, seq) WHERE deleted_at IS NULL;
+
+-- migrate:down
+  DROP INDEX IF EXISTS notifications_user_id_seq_idx;
+
+  ALTER TABLE notifications
+    DROP COLUMN IF EXISTS seq;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- migrate:up
+  UPDATE notifications
+    SET content = json_build_object('type', type, 'title', 'Subscription update', 'message', content->>'message', 'data', json_build_object())
+    WHERE type = 'SUBSCRIPTION' AND content IS NOT NULL;
+
+-- migrate:down
+  UPDATE notifications
+    SET content = json_build_object('type', type, 'user_id', user_id, 'message', content->>'message')
+    WHERE type = 'SUBSCRIPTION' AND content IS NOT NULL;
\ No newline at end of file
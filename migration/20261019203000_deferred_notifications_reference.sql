@@ -0,0 +1,5 @@
+-- migrate:up
+  CREATE INDEX IF NOT EXISTS deferred_notifications_reference_id_idx ON deferred_notifications (reference_id) WHERE reference_id <> '';
+
+-- migrate:down
+  DROP INDEX IF EXISTS deferred_notifications_reference_id_idx;
\ No newline at end of file
@@ -13,6 +13,104 @@ SET default_tablespace = '';
 
 SET default_table_access_method = heap;
 
+--
+-- Name: attachments; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.attachments (
+    id character varying(26) NOT NULL,
+    message_id character varying(26) NOT NULL,
+    match_id character varying(26) NOT NULL,
+    kind character varying(10) NOT NULL,
+    content_type character varying(100) NOT NULL,
+    size bigint NOT NULL,
+    duration_ms integer,
+    storage_key character varying(255) NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: boosts; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.boosts (
+    id character varying(26) NOT NULL,
+    user_id character varying(26) NOT NULL,
+    started_at timestamp without time zone NOT NULL,
+    ends_at timestamp without time zone NOT NULL,
+    views bigint DEFAULT 0 NOT NULL,
+    likes bigint DEFAULT 0 NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone
+);
+
+
+--
+-- Name: conversations; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.conversations (
+    id character varying(26) NOT NULL,
+    match_id character varying(26) NOT NULL,
+    user_id character varying(26) NOT NULL,
+    matched_user_id character varying(26) NOT NULL,
+    last_message_at timestamp without time zone,
+    user_last_read_at timestamp without time zone,
+    matched_user_last_read_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone
+);
+
+
+--
+-- Name: credits; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.credits (
+    id character varying(26) NOT NULL,
+    user_id character varying(26) NOT NULL,
+    type character varying(35) NOT NULL,
+    amount integer NOT NULL,
+    reason character varying(35) NOT NULL,
+    reference_id character varying(255),
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: deferred_notifications; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.deferred_notifications (
+    id character varying(26) NOT NULL,
+    notification_id character varying(26) NOT NULL,
+    user_id character varying(26) NOT NULL,
+    channel character varying(16) NOT NULL,
+    type character varying(32) NOT NULL,
+    reference_id character varying(26) DEFAULT ''::character varying NOT NULL,
+    title text NOT NULL,
+    body text NOT NULL,
+    content text NOT NULL,
+    deliver_at timestamp without time zone NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: devices; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.devices (
+    id character varying(26) NOT NULL,
+    user_id character varying(26) NOT NULL,
+    platform character varying(16) NOT NULL,
+    token character varying(512) NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone
+);
+
+
 --
 -- Name: images; Type: TABLE; Schema: public; Owner: -
 --
@@ -42,6 +140,57 @@ ALTER TABLE public.images ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
 );
 
 
+--
+-- Name: matches; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.matches (
+    id character varying(26) NOT NULL,
+    user_id character varying(26) NOT NULL,
+    matched_user_id character varying(26) NOT NULL,
+    status character varying(35) NOT NULL,
+    matched_at timestamp without time zone NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    unmatched_at timestamp without time zone,
+    unmatched_by character varying(26),
+    unmatch_reason character varying(35),
+    unmatch_detail text,
+    expires_at timestamp without time zone,
+    first_move_by character varying(26),
+    first_message_at timestamp without time zone,
+    extended_at timestamp without time zone
+);
+
+
+--
+-- Name: messages; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.messages (
+    id character varying(26) NOT NULL,
+    conversation_id character varying(26) NOT NULL,
+    sender_id character varying(26) NOT NULL,
+    body character varying(1000) NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    deleted_at timestamp without time zone
+);
+
+
+--
+-- Name: notification_preferences; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.notification_preferences (
+    user_id character varying(26) NOT NULL,
+    type character varying(32) NOT NULL,
+    channel character varying(16) NOT NULL,
+    enabled boolean NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
 --
 -- Name: notifications; Type: TABLE; Schema: public; Owner: -
 --
@@ -53,7 +202,61 @@ CREATE TABLE public.notifications (
     is_read boolean,
     created_at timestamp without time zone DEFAULT now() NOT NULL,
     updated_at timestamp without time zone DEFAULT now() NOT NULL,
-    deleted_at timestamp without time zone
+    deleted_at timestamp without time zone,
+    reference_id character varying(26) DEFAULT ''::character varying NOT NULL,
+    type character varying(32) DEFAULT ''::character varying NOT NULL,
+    seq bigint NOT NULL
+);
+
+
+--
+-- Name: notifications_seq_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.notifications_seq_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: notifications_seq_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.notifications_seq_seq OWNED BY public.notifications.seq;
+
+
+--
+-- Name: outbox; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.outbox (
+    id character varying(26) NOT NULL,
+    topic character varying(32) NOT NULL,
+    reference_id character varying(26) DEFAULT ''::character varying NOT NULL,
+    payload text NOT NULL,
+    attempts integer DEFAULT 0 NOT NULL,
+    last_error text DEFAULT ''::text NOT NULL,
+    available_at timestamp without time zone DEFAULT now() NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: outbox_dead_letters; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.outbox_dead_letters (
+    id character varying(26) NOT NULL,
+    topic character varying(32) NOT NULL,
+    reference_id character varying(26) DEFAULT ''::character varying NOT NULL,
+    payload text NOT NULL,
+    attempts integer NOT NULL,
+    last_error text NOT NULL,
+    created_at timestamp without time zone NOT NULL,
+    failed_at timestamp without time zone DEFAULT now() NOT NULL
 );
 
 
@@ -69,7 +272,13 @@ CREATE TABLE public.reactions (
     matched_at timestamp without time zone,
     created_at timestamp without time zone DEFAULT now() NOT NULL,
     updated_at timestamp without time zone DEFAULT now() NOT NULL,
-    deleted_at timestamp without time zone
+    deleted_at timestamp without time zone,
+    comment character varying(150),
+    target_type character varying(35),
+    target_id character varying(26),
+    idempotency_key character varying(64),
+    swiped_at timestamp without time zone,
+    replaced_by character varying(26)
 );
 
 
@@ -94,7 +303,12 @@ CREATE TABLE public.subscription_plans (
     stripe_product_id character varying(255) NOT NULL,
     created_at timestamp without time zone DEFAULT now() NOT NULL,
     updated_at timestamp without time zone DEFAULT now() NOT NULL,
-    deleted_at timestamp without time zone
+    deleted_at timestamp without time zone,
+    super_like_quota integer DEFAULT 0 NOT NULL,
+    super_like_period character varying(10) DEFAULT 'WEEKLY'::character varying NOT NULL,
+    daily_swipe_limit integer,
+    daily_rewind_limit integer,
+    weekly_boost_quota integer DEFAULT 0 NOT NULL
 );
 
 
@@ -130,6 +344,21 @@ CREATE TABLE public.subscriptions (
 );
 
 
+--
+-- Name: top_picks; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.top_picks (
+    id character varying(26) NOT NULL,
+    user_id character varying(26) NOT NULL,
+    picked_user_id character varying(26) NOT NULL,
+    score integer DEFAULT 0 NOT NULL,
+    expired_at timestamp without time zone NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+
 --
 -- Name: users; Type: TABLE; Schema: public; Owner: -
 --
@@ -145,10 +374,86 @@ CREATE TABLE public.users (
     age integer,
     stripe_customer_id character varying(255),
     created_at timestamp without time zone DEFAULT now() NOT NULL,
-    updated_at timestamp without time zone
+    updated_at timestamp without time zone,
+    timezone character varying(64) DEFAULT 'UTC'::character varying NOT NULL,
+    deck_reset_at timestamp without time zone,
+    language character varying(8) DEFAULT 'en'::character varying NOT NULL,
+    quiet_hours_start character varying(5) DEFAULT ''::character varying NOT NULL,
+    quiet_hours_end character varying(5) DEFAULT ''::character varying NOT NULL
 );
 
 
+--
+-- Name: notifications seq; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notifications ALTER COLUMN seq SET DEFAULT nextval('public.notifications_seq_seq'::regclass);
+
+
+--
+-- Name: attachments attachments_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.attachments
+    ADD CONSTRAINT attachments_id_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: boosts boosts_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.boosts
+    ADD CONSTRAINT boosts_id_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: conversations conversations_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversations
+    ADD CONSTRAINT conversations_id_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: conversations conversations_match_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversations
+    ADD CONSTRAINT conversations_match_id_key UNIQUE (match_id);
+
+
+--
+-- Name: credits credits_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.credits
+    ADD CONSTRAINT credits_id_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: deferred_notifications deferred_notifications_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.deferred_notifications
+    ADD CONSTRAINT deferred_notifications_id_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: devices devices_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.devices
+    ADD CONSTRAINT devices_id_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: devices devices_token_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.devices
+    ADD CONSTRAINT devices_token_key UNIQUE (token);
+
+
 --
 -- Name: images images_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -157,6 +462,38 @@ ALTER TABLE ONLY public.images
     ADD CONSTRAINT images_id_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: matches matches_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.matches
+    ADD CONSTRAINT matches_id_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: matches matches_user_id_matched_user_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.matches
+    ADD CONSTRAINT matches_user_id_matched_user_id_key UNIQUE (user_id, matched_user_id);
+
+
+--
+-- Name: messages messages_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.messages
+    ADD CONSTRAINT messages_id_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: notification_preferences notification_preferences_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notification_preferences
+    ADD CONSTRAINT notification_preferences_pkey PRIMARY KEY (user_id, type, channel);
+
+
 --
 -- Name: notifications notifications_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -165,6 +502,22 @@ ALTER TABLE ONLY public.notifications
     ADD CONSTRAINT notifications_id_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: outbox_dead_letters outbox_dead_letters_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.outbox_dead_letters
+    ADD CONSTRAINT outbox_dead_letters_id_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: outbox outbox_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.outbox
+    ADD CONSTRAINT outbox_id_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: reactions reactions_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -197,6 +550,14 @@ ALTER TABLE ONLY public.subscriptions
     ADD CONSTRAINT subscriptions_id_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: top_picks top_picks_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.top_picks
+    ADD CONSTRAINT top_picks_id_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: users users_id_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -205,6 +566,218 @@ ALTER TABLE ONLY public.users
     ADD CONSTRAINT users_id_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: attachments_message_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX attachments_message_id_idx ON public.attachments USING btree (message_id);
+
+
+--
+-- Name: boosts_user_id_ends_at_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX boosts_user_id_ends_at_idx ON public.boosts USING btree (user_id, ends_at);
+
+
+--
+-- Name: conversations_matched_user_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX conversations_matched_user_id_idx ON public.conversations USING btree (matched_user_id);
+
+
+--
+-- Name: conversations_user_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX conversations_user_id_idx ON public.conversations USING btree (user_id);
+
+
+--
+-- Name: credits_purchase_reference_id_key; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX credits_purchase_reference_id_key ON public.credits USING btree (reference_id) WHERE ((reason)::text = 'PURCHASE'::text);
+
+
+--
+-- Name: credits_user_id_type_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX credits_user_id_type_idx ON public.credits USING btree (user_id, type);
+
+
+--
+-- Name: deferred_notifications_deliver_at_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX deferred_notifications_deliver_at_idx ON public.deferred_notifications USING btree (deliver_at, id);
+
+
+--
+-- Name: deferred_notifications_reference_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX deferred_notifications_reference_id_idx ON public.deferred_notifications USING btree (reference_id) WHERE ((reference_id)::text <> ''::text);
+
+
+--
+-- Name: devices_user_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX devices_user_id_idx ON public.devices USING btree (user_id);
+
+
+--
+-- Name: matches_expiring_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX matches_expiring_idx ON public.matches USING btree (expires_at) WHERE (((status)::text = 'ACTIVE'::text) AND (first_message_at IS NULL));
+
+
+--
+-- Name: matches_matched_user_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX matches_matched_user_id_idx ON public.matches USING btree (matched_user_id);
+
+
+--
+-- Name: messages_conversation_id_created_at_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX messages_conversation_id_created_at_idx ON public.messages USING btree (conversation_id, created_at);
+
+
+--
+-- Name: notifications_inbox_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX notifications_inbox_idx ON public.notifications USING btree (user_id, type, id) WHERE (deleted_at IS NULL);
+
+
+--
+-- Name: notifications_reference_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX notifications_reference_id_idx ON public.notifications USING btree (reference_id);
+
+
+--
+-- Name: notifications_user_id_seq_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX notifications_user_id_seq_idx ON public.notifications USING btree (user_id, seq) WHERE (deleted_at IS NULL);
+
+
+--
+-- Name: outbox_available_at_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX outbox_available_at_idx ON public.outbox USING btree (available_at, id);
+
+
+--
+-- Name: outbox_reference_id_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX outbox_reference_id_idx ON public.outbox USING btree (reference_id) WHERE ((reference_id)::text <> ''::text);
+
+
+--
+-- Name: reactions_user_id_idempotency_key_key; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX reactions_user_id_idempotency_key_key ON public.reactions USING btree (user_id, idempotency_key) WHERE (idempotency_key IS NOT NULL);
+
+
+--
+-- Name: reactions_user_id_matched_user_id_key; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX reactions_user_id_matched_user_id_key ON public.reactions USING btree (user_id, matched_user_id) WHERE (deleted_at IS NULL);
+
+
+--
+-- Name: top_picks_user_id_expired_at_idx; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX top_picks_user_id_expired_at_idx ON public.top_picks USING btree (user_id, expired_at);
+
+
+--
+-- Name: attachments attachments_match_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.attachments
+    ADD CONSTRAINT attachments_match_id_fkey FOREIGN KEY (match_id) REFERENCES public.matches(id) ON DELETE CASCADE;
+
+
+--
+-- Name: attachments attachments_message_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.attachments
+    ADD CONSTRAINT attachments_message_id_fkey FOREIGN KEY (message_id) REFERENCES public.messages(id) ON DELETE CASCADE;
+
+
+--
+-- Name: boosts boosts_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.boosts
+    ADD CONSTRAINT boosts_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id);
+
+
+--
+-- Name: conversations conversations_match_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversations
+    ADD CONSTRAINT conversations_match_id_fkey FOREIGN KEY (match_id) REFERENCES public.matches(id) ON DELETE CASCADE;
+
+
+--
+-- Name: conversations conversations_matched_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversations
+    ADD CONSTRAINT conversations_matched_user_id_fkey FOREIGN KEY (matched_user_id) REFERENCES public.users(id);
+
+
+--
+-- Name: conversations conversations_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.conversations
+    ADD CONSTRAINT conversations_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id);
+
+
+--
+-- Name: credits credits_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.credits
+    ADD CONSTRAINT credits_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id);
+
+
+--
+-- Name: deferred_notifications deferred_notifications_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.deferred_notifications
+    ADD CONSTRAINT deferred_notifications_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id);
+
+
+--
+-- Name: devices devices_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.devices
+    ADD CONSTRAINT devices_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id);
+
+
 --
 -- Name: images images_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -213,6 +786,54 @@ ALTER TABLE ONLY public.images
     ADD CONSTRAINT images_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id);
 
 
+--
+-- Name: matches matches_first_move_by_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.matches
+    ADD CONSTRAINT matches_first_move_by_fkey FOREIGN KEY (first_move_by) REFERENCES public.users(id);
+
+
+--
+-- Name: matches matches_matched_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.matches
+    ADD CONSTRAINT matches_matched_user_id_fkey FOREIGN KEY (matched_user_id) REFERENCES public.users(id);
+
+
+--
+-- Name: matches matches_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.matches
+    ADD CONSTRAINT matches_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id);
+
+
+--
+-- Name: messages messages_conversation_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.messages
+    ADD CONSTRAINT messages_conversation_id_fkey FOREIGN KEY (conversation_id) REFERENCES public.conversations(id) ON DELETE CASCADE;
+
+
+--
+-- Name: messages messages_sender_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.messages
+    ADD CONSTRAINT messages_sender_id_fkey FOREIGN KEY (sender_id) REFERENCES public.users(id);
+
+
+--
+-- Name: notification_preferences notification_preferences_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notification_preferences
+    ADD CONSTRAINT notification_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id);
+
+
 --
 -- Name: notifications notifications_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -253,6 +874,22 @@ ALTER TABLE ONLY public.subscriptions
     ADD CONSTRAINT subscriptions_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id);
 
 
+--
+-- Name: top_picks top_picks_picked_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.top_picks
+    ADD CONSTRAINT top_picks_picked_user_id_fkey FOREIGN KEY (picked_user_id) REFERENCES public.users(id);
+
+
+--
+-- Name: top_picks top_picks_user_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.top_picks
+    ADD CONSTRAINT top_picks_user_id_fkey FOREIGN KEY (user_id) REFERENCES public.users(id);
+
+
 --
 -- PostgreSQL database dump complete
 --
@@ -268,4 +905,28 @@ INSERT INTO public.schema_migrations (version) VALUES
     ('20241031160243'),
     ('20241031165831'),
     ('20241101150454'),
-    ('20241101171612');
+    ('20241101171612'),
+    ('20261019090000'),
+    ('20261019093000'),
+    ('20261019100000'),
+    ('20261019103000'),
+    ('20261019110000'),
+    ('20261019113000'),
+    ('20261019120000'),
+    ('20261019123000'),
+    ('20261019130000'),
+    ('20261019133000'),
+    ('20261019140000'),
+    ('20261019143000'),
+    ('20261019150000'),
+    ('20261019153000'),
+    ('20261019160000'),
+    ('20261019163000'),
+    ('20261019170000'),
+    ('20261019173000'),
+    ('20261019180000'),
+    ('20261019183000'),
+    ('20261019190000'),
+    ('20261019193000'),
+    ('20261019200000'),
+    ('20261019203000');
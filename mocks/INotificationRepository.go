@@ -3,8 +3,12 @@
 package mocks
 
 import (
+	context "context"
+
 	model "github.com/marvelalexius/jones/model"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // INotificationRepository is an autogenerated mock type for the INotificationRepository type
@@ -12,17 +16,119 @@ type INotificationRepository struct {
 	mock.Mock
 }
 
+// CountUnread provides a mock function with given fields: ctx, userID
+func (_m *INotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountUnread")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: notif
-func (_m *INotificationRepository) Create(notif model.Notification) error {
+func (_m *INotificationRepository) Create(notif *model.Notification) (bool, error) {
 	ret := _m.Called(notif)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(model.Notification) error); ok {
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.Notification) (bool, error)); ok {
+		return rf(notif)
+	}
+	if rf, ok := ret.Get(0).(func(*model.Notification) bool); ok {
 		r0 = rf(notif)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.Notification) error); ok {
+		r1 = rf(notif)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, userID, id, deletedAt
+func (_m *INotificationRepository) Delete(ctx context.Context, userID string, id string, deletedAt time.Time) (bool, error) {
+	ret := _m.Called(ctx, userID, id, deletedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (bool, error)); ok {
+		return rf(ctx, userID, id, deletedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) bool); ok {
+		r0 = rf(ctx, userID, id, deletedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, id, deletedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteByReferenceID provides a mock function with given fields: ctx, referenceID
+func (_m *INotificationRepository) DeleteByReferenceID(ctx context.Context, referenceID string) error {
+	ret := _m.Called(ctx, referenceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByReferenceID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, referenceID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByReferenceIDs provides a mock function with given fields: ctx, referenceIDs
+func (_m *INotificationRepository) DeleteByReferenceIDs(ctx context.Context, referenceIDs []string) error {
+	ret := _m.Called(ctx, referenceIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteByReferenceIDs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, referenceIDs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -30,6 +136,129 @@ func (_m *INotificationRepository) Create(notif model.Notification) error {
 	return r0
 }
 
+// FindAfter provides a mock function with given fields: ctx, userID, afterSeq, limit
+func (_m *INotificationRepository) FindAfter(ctx context.Context, userID string, afterSeq int64, limit int) ([]model.Notification, error) {
+	ret := _m.Called(ctx, userID, afterSeq, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAfter")
+	}
+
+	var r0 []model.Notification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int) ([]model.Notification, error)); ok {
+		return rf(ctx, userID, afterSeq, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int) []model.Notification); ok {
+		r0 = rf(ctx, userID, afterSeq, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Notification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int) error); ok {
+		r1 = rf(ctx, userID, afterSeq, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindByUserID provides a mock function with given fields: ctx, userID, notificationType, pagination
+func (_m *INotificationRepository) FindByUserID(ctx context.Context, userID string, notificationType string, pagination model.PaginationRequest) ([]model.Notification, int64, error) {
+	ret := _m.Called(ctx, userID, notificationType, pagination)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByUserID")
+	}
+
+	var r0 []model.Notification
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.PaginationRequest) ([]model.Notification, int64, error)); ok {
+		return rf(ctx, userID, notificationType, pagination)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, model.PaginationRequest) []model.Notification); ok {
+		r0 = rf(ctx, userID, notificationType, pagination)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Notification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, model.PaginationRequest) int64); ok {
+		r1 = rf(ctx, userID, notificationType, pagination)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, model.PaginationRequest) error); ok {
+		r2 = rf(ctx, userID, notificationType, pagination)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MarkAllRead provides a mock function with given fields: ctx, userID, readAt
+func (_m *INotificationRepository) MarkAllRead(ctx context.Context, userID string, readAt time.Time) (int64, error) {
+	ret := _m.Called(ctx, userID, readAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkAllRead")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int64, error)); ok {
+		return rf(ctx, userID, readAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int64); ok {
+		r0 = rf(ctx, userID, readAt)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, readAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkRead provides a mock function with given fields: ctx, userID, id, readAt
+func (_m *INotificationRepository) MarkRead(ctx context.Context, userID string, id string, readAt time.Time) (bool, error) {
+	ret := _m.Called(ctx, userID, id, readAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkRead")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (bool, error)); ok {
+		return rf(ctx, userID, id, readAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) bool); ok {
+		r0 = rf(ctx, userID, id, readAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, id, readAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewINotificationRepository creates a new instance of INotificationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewINotificationRepository(t interface {
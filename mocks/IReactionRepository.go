@@ -7,6 +7,8 @@ import (
 
 	model "github.com/marvelalexius/jones/model"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // IReactionRepository is an autogenerated mock type for the IReactionRepository type
@@ -14,6 +16,90 @@ type IReactionRepository struct {
 	mock.Mock
 }
 
+// CountByTypeSince provides a mock function with given fields: ctx, userID, reactionType, since
+func (_m *IReactionRepository) CountByTypeSince(ctx context.Context, userID string, reactionType string, since time.Time) (int64, error) {
+	ret := _m.Called(ctx, userID, reactionType, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountByTypeSince")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (int64, error)); ok {
+		return rf(ctx, userID, reactionType, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) int64); ok {
+		r0 = rf(ctx, userID, reactionType, since)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, reactionType, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountLikes provides a mock function with given fields: ctx, userID
+func (_m *IReactionRepository) CountLikes(ctx context.Context, userID string) (int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountLikes")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountRewoundSince provides a mock function with given fields: ctx, userID, since
+func (_m *IReactionRepository) CountRewoundSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	ret := _m.Called(ctx, userID, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountRewoundSince")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int64, error)); ok {
+		return rf(ctx, userID, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int64); ok {
+		r0 = rf(ctx, userID, since)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, reaction
 func (_m *IReactionRepository) Create(ctx context.Context, reaction model.Reaction) error {
 	ret := _m.Called(ctx, reaction)
@@ -32,6 +118,112 @@ func (_m *IReactionRepository) Create(ctx context.Context, reaction model.Reacti
 	return r0
 }
 
+// Delete provides a mock function with given fields: ctx, id, deletedAt
+func (_m *IReactionRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	ret := _m.Called(ctx, id, deletedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, deletedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindComments provides a mock function with given fields: ctx, userID, matchedUserID
+func (_m *IReactionRepository) FindComments(ctx context.Context, userID string, matchedUserID string) ([]model.Reaction, error) {
+	ret := _m.Called(ctx, userID, matchedUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindComments")
+	}
+
+	var r0 []model.Reaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]model.Reaction, error)); ok {
+		return rf(ctx, userID, matchedUserID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []model.Reaction); ok {
+		r0 = rf(ctx, userID, matchedUserID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Reaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, matchedUserID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindLatest provides a mock function with given fields: ctx, userID
+func (_m *IReactionRepository) FindLatest(ctx context.Context, userID string) (model.Reaction, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLatest")
+	}
+
+	var r0 model.Reaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.Reaction, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.Reaction); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(model.Reaction)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindLatestLikes provides a mock function with given fields: ctx, userID, limit
+func (_m *IReactionRepository) FindLatestLikes(ctx context.Context, userID string, limit int) ([]model.Reaction, error) {
+	ret := _m.Called(ctx, userID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLatestLikes")
+	}
+
+	var r0 []model.Reaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]model.Reaction, error)); ok {
+		return rf(ctx, userID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []model.Reaction); ok {
+		r0 = rf(ctx, userID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Reaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, userID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindLikes provides a mock function with given fields: ctx, userID
 func (_m *IReactionRepository) FindLikes(ctx context.Context, userID string) ([]model.Reaction, error) {
 	ret := _m.Called(ctx, userID)
@@ -90,9 +282,37 @@ func (_m *IReactionRepository) FindMatch(ctx context.Context, userID string, mat
 	return r0, r1
 }
 
-// FindSwipeCount provides a mock function with given fields: ctx, userID
-func (_m *IReactionRepository) FindSwipeCount(ctx context.Context, userID string) (int64, error) {
-	ret := _m.Called(ctx, userID)
+// FindPendingLike provides a mock function with given fields: ctx, userID, id
+func (_m *IReactionRepository) FindPendingLike(ctx context.Context, userID string, id string) (model.Reaction, error) {
+	ret := _m.Called(ctx, userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPendingLike")
+	}
+
+	var r0 model.Reaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (model.Reaction, error)); ok {
+		return rf(ctx, userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) model.Reaction); ok {
+		r0 = rf(ctx, userID, id)
+	} else {
+		r0 = ret.Get(0).(model.Reaction)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindSwipeCount provides a mock function with given fields: ctx, userID, since
+func (_m *IReactionRepository) FindSwipeCount(ctx context.Context, userID string, since time.Time) (int64, error) {
+	ret := _m.Called(ctx, userID, since)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindSwipeCount")
@@ -100,17 +320,17 @@ func (_m *IReactionRepository) FindSwipeCount(ctx context.Context, userID string
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (int64, error)); ok {
+		return rf(ctx, userID, since)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) int64); ok {
+		r0 = rf(ctx, userID, since)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, since)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -118,29 +338,66 @@ func (_m *IReactionRepository) FindSwipeCount(ctx context.Context, userID string
 	return r0, r1
 }
 
-// FindSwiped provides a mock function with given fields: ctx, userID
-func (_m *IReactionRepository) FindSwiped(ctx context.Context, userID string) ([]model.Reaction, error) {
-	ret := _m.Called(ctx, userID)
+// FindSwiped provides a mock function with given fields: ctx, userID, filter, pagination
+func (_m *IReactionRepository) FindSwiped(ctx context.Context, userID string, filter model.SentReactionFilter, pagination model.PaginationRequest) ([]model.Reaction, int64, error) {
+	ret := _m.Called(ctx, userID, filter, pagination)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindSwiped")
 	}
 
 	var r0 []model.Reaction
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]model.Reaction, error)); ok {
-		return rf(ctx, userID)
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.SentReactionFilter, model.PaginationRequest) ([]model.Reaction, int64, error)); ok {
+		return rf(ctx, userID, filter, pagination)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []model.Reaction); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.SentReactionFilter, model.PaginationRequest) []model.Reaction); ok {
+		r0 = rf(ctx, userID, filter, pagination)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Reaction)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.SentReactionFilter, model.PaginationRequest) int64); ok {
+		r1 = rf(ctx, userID, filter, pagination)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, model.SentReactionFilter, model.PaginationRequest) error); ok {
+		r2 = rf(ctx, userID, filter, pagination)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindSwipedUserIDs provides a mock function with given fields: ctx, userID, passCutoff
+func (_m *IReactionRepository) FindSwipedUserIDs(ctx context.Context, userID string, passCutoff time.Time) ([]string, error) {
+	ret := _m.Called(ctx, userID, passCutoff)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSwipedUserIDs")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) ([]string, error)); ok {
+		return rf(ctx, userID, passCutoff)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) []string); ok {
+		r0 = rf(ctx, userID, passCutoff)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, passCutoff)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -176,6 +433,78 @@ func (_m *IReactionRepository) HasSwiped(ctx context.Context, userID string, mat
 	return r0, r1
 }
 
+// LockPair provides a mock function with given fields: ctx, userID, matchedUserID
+func (_m *IReactionRepository) LockPair(ctx context.Context, userID string, matchedUserID string) error {
+	ret := _m.Called(ctx, userID, matchedUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockPair")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, matchedUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LockUser provides a mock function with given fields: ctx, userID
+func (_m *IReactionRepository) LockUser(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Replace provides a mock function with given fields: ctx, id, replacedBy, replacedAt
+func (_m *IReactionRepository) Replace(ctx context.Context, id string, replacedBy string, replacedAt time.Time) error {
+	ret := _m.Called(ctx, id, replacedBy, replacedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Replace")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) error); ok {
+		r0 = rf(ctx, id, replacedBy, replacedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetMatch provides a mock function with given fields: ctx, id
+func (_m *IReactionRepository) ResetMatch(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetMatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, reaction
 func (_m *IReactionRepository) Update(ctx context.Context, reaction *model.Reaction) error {
 	ret := _m.Called(ctx, reaction)
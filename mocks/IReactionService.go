@@ -5,8 +5,12 @@ package mocks
 import (
 	context "context"
 
-	model "github.com/marvelalexius/jones/model"
+	imageproxy "github.com/marvelalexius/jones/pkg/imageproxy"
 	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/marvelalexius/jones/model"
+
+	url "net/url"
 )
 
 // IReactionService is an autogenerated mock type for the IReactionService type
@@ -14,6 +18,131 @@ type IReactionService struct {
 	mock.Mock
 }
 
+// BatchSwipe provides a mock function with given fields: ctx, userID, reactions
+func (_m *IReactionService) BatchSwipe(ctx context.Context, userID string, reactions []model.ReactionRequest) ([]model.BatchReactionResult, error) {
+	ret := _m.Called(ctx, userID, reactions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BatchSwipe")
+	}
+
+	var r0 []model.BatchReactionResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []model.ReactionRequest) ([]model.BatchReactionResult, error)); ok {
+		return rf(ctx, userID, reactions)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []model.ReactionRequest) []model.BatchReactionResult); ok {
+		r0 = rf(ctx, userID, reactions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BatchReactionResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []model.ReactionRequest) error); ok {
+		r1 = rf(ctx, userID, reactions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindSent provides a mock function with given fields: ctx, userID, req
+func (_m *IReactionService) FindSent(ctx context.Context, userID string, req model.SentReactionRequest) ([]model.SentReaction, int64, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindSent")
+	}
+
+	var r0 []model.SentReaction
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.SentReactionRequest) ([]model.SentReaction, int64, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.SentReactionRequest) []model.SentReaction); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.SentReaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.SentReactionRequest) int64); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, model.SentReactionRequest) error); ok {
+		r2 = rf(ctx, userID, req)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// OpenLikeTeaser provides a mock function with given fields: ctx, id, query
+func (_m *IReactionService) OpenLikeTeaser(ctx context.Context, id string, query url.Values) (*imageproxy.Image, error) {
+	ret := _m.Called(ctx, id, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenLikeTeaser")
+	}
+
+	var r0 *imageproxy.Image
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, url.Values) (*imageproxy.Image, error)); ok {
+		return rf(ctx, id, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, url.Values) *imageproxy.Image); ok {
+		r0 = rf(ctx, id, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*imageproxy.Image)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, url.Values) error); ok {
+		r1 = rf(ctx, id, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Rewind provides a mock function with given fields: ctx, userID
+func (_m *IReactionService) Rewind(ctx context.Context, userID string) (model.Reaction, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rewind")
+	}
+
+	var r0 model.Reaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.Reaction, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.Reaction); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(model.Reaction)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SeeLikes provides a mock function with given fields: ctx, userID
 func (_m *IReactionService) SeeLikes(ctx context.Context, userID string) ([]model.Reaction, error) {
 	ret := _m.Called(ctx, userID)
@@ -44,6 +173,52 @@ func (_m *IReactionService) SeeLikes(ctx context.Context, userID string) ([]mode
 	return r0, r1
 }
 
+// StartOver provides a mock function with given fields: ctx, userID
+func (_m *IReactionService) StartOver(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartOver")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SummarizeLikes provides a mock function with given fields: ctx, userID
+func (_m *IReactionService) SummarizeLikes(ctx context.Context, userID string) (model.LikesSummary, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SummarizeLikes")
+	}
+
+	var r0 model.LikesSummary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.LikesSummary, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.LikesSummary); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(model.LikesSummary)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Swipe provides a mock function with given fields: ctx, reaction
 func (_m *IReactionService) Swipe(ctx context.Context, reaction model.ReactionRequest) (model.Reaction, error) {
 	ret := _m.Called(ctx, reaction)
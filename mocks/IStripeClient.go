@@ -105,6 +105,36 @@ func (_m *IStripeClient) CreateCustomer(ctx context.Context, email string, name
 	return r0, r1
 }
 
+// CreatePaymentCheckoutSession provides a mock function with given fields: ctx, customerID, priceID, quantity, metadata
+func (_m *IStripeClient) CreatePaymentCheckoutSession(ctx context.Context, customerID string, priceID string, quantity int64, metadata map[string]string) (*stripe.CheckoutSession, error) {
+	ret := _m.Called(ctx, customerID, priceID, quantity, metadata)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePaymentCheckoutSession")
+	}
+
+	var r0 *stripe.CheckoutSession
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, map[string]string) (*stripe.CheckoutSession, error)); ok {
+		return rf(ctx, customerID, priceID, quantity, metadata)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, map[string]string) *stripe.CheckoutSession); ok {
+		r0 = rf(ctx, customerID, priceID, quantity, metadata)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*stripe.CheckoutSession)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int64, map[string]string) error); ok {
+		r1 = rf(ctx, customerID, priceID, quantity, metadata)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewIStripeClient creates a new instance of IStripeClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIStripeClient(t interface {
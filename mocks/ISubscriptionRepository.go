@@ -260,6 +260,36 @@ func (_m *ISubscriptionRepository) FindPlanByID(ctx context.Context, id int) (*m
 	return r0, r1
 }
 
+// FindPlanByName provides a mock function with given fields: ctx, name
+func (_m *ISubscriptionRepository) FindPlanByName(ctx context.Context, name string) (*model.SubscriptionPlan, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindPlanByName")
+	}
+
+	var r0 *model.SubscriptionPlan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.SubscriptionPlan, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.SubscriptionPlan); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.SubscriptionPlan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindPlanByProductID provides a mock function with given fields: ctx, id
 func (_m *ISubscriptionRepository) FindPlanByProductID(ctx context.Context, id string) (*model.SubscriptionPlan, error) {
 	ret := _m.Called(ctx, id)
@@ -7,6 +7,8 @@ import (
 
 	model "github.com/marvelalexius/jones/model"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // IUserRepository is an autogenerated mock type for the IUserRepository type
@@ -14,6 +16,41 @@ type IUserRepository struct {
 	mock.Mock
 }
 
+// CountDeck provides a mock function with given fields: ctx, userIds, preference, now
+func (_m *IUserRepository) CountDeck(ctx context.Context, userIds []string, preference string, now time.Time) (int64, int64, error) {
+	ret := _m.Called(ctx, userIds, preference, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountDeck")
+	}
+
+	var r0 int64
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string, time.Time) (int64, int64, error)); ok {
+		return rf(ctx, userIds, preference, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string, time.Time) int64); ok {
+		r0 = rf(ctx, userIds, preference, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, string, time.Time) int64); ok {
+		r1 = rf(ctx, userIds, preference, now)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []string, string, time.Time) error); ok {
+		r2 = rf(ctx, userIds, preference, now)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // Create provides a mock function with given fields: user
 func (_m *IUserRepository) Create(user *model.User) error {
 	ret := _m.Called(user)
@@ -32,41 +69,34 @@ func (_m *IUserRepository) Create(user *model.User) error {
 	return r0
 }
 
-// FindAll provides a mock function with given fields: ctx, userIds, preference
-func (_m *IUserRepository) FindAll(ctx context.Context, userIds []string, preference string) ([]model.User, int64, error) {
-	ret := _m.Called(ctx, userIds, preference)
+// FindBatch provides a mock function with given fields: ctx, afterID, limit
+func (_m *IUserRepository) FindBatch(ctx context.Context, afterID string, limit int) ([]model.User, error) {
+	ret := _m.Called(ctx, afterID, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindAll")
+		panic("no return value specified for FindBatch")
 	}
 
 	var r0 []model.User
-	var r1 int64
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, []string, string) ([]model.User, int64, error)); ok {
-		return rf(ctx, userIds, preference)
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]model.User, error)); ok {
+		return rf(ctx, afterID, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []string, string) []model.User); ok {
-		r0 = rf(ctx, userIds, preference)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []model.User); ok {
+		r0 = rf(ctx, afterID, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []string, string) int64); ok {
-		r1 = rf(ctx, userIds, preference)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
 	} else {
-		r1 = ret.Get(1).(int64)
-	}
-
-	if rf, ok := ret.Get(2).(func(context.Context, []string, string) error); ok {
-		r2 = rf(ctx, userIds, preference)
-	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
 // FindByEmail provides a mock function with given fields: ctx, email
@@ -159,6 +189,94 @@ func (_m *IUserRepository) FindByStripeCustomerID(ctx context.Context, id string
 	return r0, r1
 }
 
+// FindDeck provides a mock function with given fields: ctx, viewerID, userIds, preference, boosted, now, offset, limit
+func (_m *IUserRepository) FindDeck(ctx context.Context, viewerID string, userIds []string, preference string, boosted bool, now time.Time, offset int, limit int) ([]model.User, error) {
+	ret := _m.Called(ctx, viewerID, userIds, preference, boosted, now, offset, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDeck")
+	}
+
+	var r0 []model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, string, bool, time.Time, int, int) ([]model.User, error)); ok {
+		return rf(ctx, viewerID, userIds, preference, boosted, now, offset, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, string, bool, time.Time, int, int) []model.User); ok {
+		r0 = rf(ctx, viewerID, userIds, preference, boosted, now, offset, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, string, bool, time.Time, int, int) error); ok {
+		r1 = rf(ctx, viewerID, userIds, preference, boosted, now, offset, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindTopPickCandidates provides a mock function with given fields: ctx, user, excludeIDs, limit
+func (_m *IUserRepository) FindTopPickCandidates(ctx context.Context, user model.User, excludeIDs []string, limit int) ([]model.User, error) {
+	ret := _m.Called(ctx, user, excludeIDs, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindTopPickCandidates")
+	}
+
+	var r0 []model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.User, []string, int) ([]model.User, error)); ok {
+		return rf(ctx, user, excludeIDs, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.User, []string, int) []model.User); ok {
+		r0 = rf(ctx, user, excludeIDs, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.User, []string, int) error); ok {
+		r1 = rf(ctx, user, excludeIDs, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasImage provides a mock function with given fields: ctx, userID, imageID
+func (_m *IUserRepository) HasImage(ctx context.Context, userID string, imageID int) (bool, error) {
+	ret := _m.Called(ctx, userID, imageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasImage")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (bool, error)); ok {
+		return rf(ctx, userID, imageID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) bool); ok {
+		r0 = rf(ctx, userID, imageID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, userID, imageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: user
 func (_m *IUserRepository) Update(user *model.User) (*model.User, error) {
 	ret := _m.Called(user)
@@ -189,6 +307,24 @@ func (_m *IUserRepository) Update(user *model.User) (*model.User, error) {
 	return r0, r1
 }
 
+// UpdateQuietHours provides a mock function with given fields: ctx, id, start, end, now
+func (_m *IUserRepository) UpdateQuietHours(ctx context.Context, id string, start string, end string, now time.Time) error {
+	ret := _m.Called(ctx, id, start, end, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateQuietHours")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Time) error); ok {
+		r0 = rf(ctx, id, start, end, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewIUserRepository creates a new instance of IUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIUserRepository(t interface {
@@ -14,9 +14,9 @@ type IUserService struct {
 	mock.Mock
 }
 
-// FindAll provides a mock function with given fields: ctx, userID
-func (_m *IUserService) FindAll(ctx context.Context, userID string) ([]model.User, int64, error) {
-	ret := _m.Called(ctx, userID)
+// FindAll provides a mock function with given fields: ctx, userID, pagination
+func (_m *IUserService) FindAll(ctx context.Context, userID string, pagination model.PaginationRequest) ([]model.User, int64, error) {
+	ret := _m.Called(ctx, userID, pagination)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindAll")
@@ -25,25 +25,25 @@ func (_m *IUserService) FindAll(ctx context.Context, userID string) ([]model.Use
 	var r0 []model.User
 	var r1 int64
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]model.User, int64, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.PaginationRequest) ([]model.User, int64, error)); ok {
+		return rf(ctx, userID, pagination)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []model.User); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.PaginationRequest) []model.User); ok {
+		r0 = rf(ctx, userID, pagination)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) int64); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.PaginationRequest) int64); ok {
+		r1 = rf(ctx, userID, pagination)
 	} else {
 		r1 = ret.Get(1).(int64)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
-		r2 = rf(ctx, userID)
+	if rf, ok := ret.Get(2).(func(context.Context, string, model.PaginationRequest) error); ok {
+		r2 = rf(ctx, userID, pagination)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -181,6 +181,66 @@ func (_m *IUserService) Register(ctx context.Context, user *model.RegisterUser)
 	return r0, r1
 }
 
+// UpdateLanguage provides a mock function with given fields: ctx, userID, req
+func (_m *IUserService) UpdateLanguage(ctx context.Context, userID string, req model.UpdateLanguageRequest) (*model.User, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLanguage")
+	}
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.UpdateLanguageRequest) (*model.User, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.UpdateLanguageRequest) *model.User); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.UpdateLanguageRequest) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateTimezone provides a mock function with given fields: ctx, userID, req
+func (_m *IUserService) UpdateTimezone(ctx context.Context, userID string, req model.UpdateTimezoneRequest) (*model.User, error) {
+	ret := _m.Called(ctx, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTimezone")
+	}
+
+	var r0 *model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.UpdateTimezoneRequest) (*model.User, error)); ok {
+		return rf(ctx, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, model.UpdateTimezoneRequest) *model.User); ok {
+		r0 = rf(ctx, userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, model.UpdateTimezoneRequest) error); ok {
+		r1 = rf(ctx, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewIUserService creates a new instance of IUserService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewIUserService(t interface {
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	AttachmentPhoto = "PHOTO"
+	AttachmentVoice = "VOICE"
+
+	MaxPhotoSize int64 = 10 << 20
+	MaxVoiceSize int64 = 5 << 20
+
+	// MaxVoiceDuration is checked against the duration the client reports, MaxVoiceSize bounds what it can't lie about.
+	MaxVoiceDuration = 2 * time.Minute
+
+	// AttachmentURLTTL is how long a signed attachment URL works, clients get fresh ones with every listing.
+	AttachmentURLTTL = 5 * time.Minute
+)
+
+// AttachmentContentTypes maps what an upload sniffs as to the content type it's served with, per kind.
+var AttachmentContentTypes = map[string]map[string]string{
+	AttachmentPhoto: {
+		"image/jpeg": "image/jpeg",
+		"image/png":  "image/png",
+		"image/gif":  "image/gif",
+		"image/webp": "image/webp",
+	},
+	AttachmentVoice: {
+		"audio/mpeg":      "audio/mpeg",
+		"audio/wave":      "audio/wav",
+		"application/ogg": "audio/ogg",
+		"video/webm":      "audio/webm",
+		"video/mp4":       "audio/mp4",
+	},
+}
+
+// MaxAttachmentUploadSize bounds a whole upload request, the multipart headers and other fields come on top of the file.
+const MaxAttachmentUploadSize = max(MaxPhotoSize, MaxVoiceSize) + 1<<20
+
+// MaxAttachmentSizes caps the upload size of each kind of attachment.
+var MaxAttachmentSizes = map[string]int64{
+	AttachmentPhoto: MaxPhotoSize,
+	AttachmentVoice: MaxVoiceSize,
+}
+
+type Attachment struct {
+	ID        string `json:"id"`
+	MessageID string `json:"-"`
+	// MatchID is kept on the attachment so fetching it can check the viewer is still matched
+	MatchID     string    `json:"-"`
+	Kind        string    `json:"kind"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	DurationMs  *int      `json:"duration_ms,omitempty"`
+	StorageKey  string    `json:"-"`
+	CreatedAt   time.Time `gorm:"<-:create" json:"created_at"`
+
+	// URL is signed for whoever the message is shown to
+	URL string `gorm:"-" json:"url"`
+}
+
+type SendAttachmentRequest struct {
+	Kind       string `form:"kind" binding:"required,oneof=PHOTO VOICE"`
+	Caption    string `form:"caption" binding:"max=1000"`
+	DurationMs int    `form:"duration_ms" binding:"required_if=Kind VOICE,gte=0"`
+}
+
+func NewAttachment(matchID, kind, contentType string, size int64, now time.Time) Attachment {
+	id := ulid.Make().String()
+
+	return Attachment{
+		ID:          id,
+		MatchID:     matchID,
+		Kind:        kind,
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  "matches/" + matchID + "/" + id,
+		CreatedAt:   now,
+	}
+}
+
+// Path is where the attachment is served from, its signed URL is issued for this path.
+func (a *Attachment) Path() string {
+	return "/attachments/" + a.ID
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// BoostDuration is how long a boost keeps a profile promoted.
+	BoostDuration = 30 * time.Minute
+	// BoostSlotInterval spreads boosted profiles out so at most one in every BoostSlotInterval deck slots is boosted.
+	BoostSlotInterval = 3
+)
+
+type Boost struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndsAt    time.Time  `json:"ends_at"`
+	Views     int64      `json:"views"`
+	Likes     int64      `json:"likes"`
+	CreatedAt time.Time  `gorm:"<-:create" json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+// BoostResponse is a boost with its results, the counters stop moving once the boost is over.
+type BoostResponse struct {
+	ID        string    `json:"id"`
+	Active    bool      `json:"active"`
+	StartedAt time.Time `json:"started_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Views     int64     `json:"views"`
+	Likes     int64     `json:"likes"`
+}
+
+func NewBoost(userID string, now time.Time) Boost {
+	return Boost{
+		ID:        ulid.Make().String(),
+		UserID:    userID,
+		StartedAt: now,
+		EndsAt:    now.Add(BoostDuration),
+		CreatedAt: now,
+	}
+}
+
+func (b *Boost) IsActive(now time.Time) bool {
+	return !now.Before(b.StartedAt) && now.Before(b.EndsAt)
+}
+
+func (b *Boost) ToBoostResponse(now time.Time) BoostResponse {
+	return BoostResponse{
+		ID:        b.ID,
+		Active:    b.IsActive(now),
+		StartedAt: b.StartedAt,
+		EndsAt:    b.EndsAt,
+		Views:     b.Views,
+		Likes:     b.Likes,
+	}
+}
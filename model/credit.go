@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	CreditSuperLike = "SUPER_LIKE"
+	CreditBoost     = "BOOST"
+
+	CreditReasonPurchase = "PURCHASE"
+	CreditReasonConsume  = "CONSUME"
+	CreditReasonRefund   = "REFUND"
+)
+
+// CreditPackSizes is how many credits a single purchased pack grants, per credit type.
+var CreditPackSizes = map[string]int{
+	CreditSuperLike: 5,
+	CreditBoost:     1,
+}
+
+// Credit is a ledger entry, positive amounts are grants and negative amounts are consumptions.
+// The balance of a credit type is the sum of its entries.
+type Credit struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	Type        string    `json:"type"`
+	Amount      int       `json:"amount"`
+	Reason      string    `json:"reason"`
+	ReferenceID string    `json:"reference_id"`
+	CreatedAt   time.Time `gorm:"<-:create" json:"created_at"`
+}
+
+type CreditPurchaseRequest struct {
+	Type     string `json:"type" binding:"required,oneof=SUPER_LIKE BOOST"`
+	Quantity int    `json:"quantity" binding:"required,min=1,max=10"`
+}
+
+func NewCredit(userID, creditType string, amount int, reason, referenceID string) Credit {
+	return Credit{
+		ID:          ulid.Make().String(),
+		UserID:      userID,
+		Type:        creditType,
+		Amount:      amount,
+		Reason:      reason,
+		ReferenceID: referenceID,
+		CreatedAt:   time.Now(),
+	}
+}
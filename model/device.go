@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Platforms a device can register for push notifications from, android goes through FCM and iOS through APNs.
+const (
+	PlatformAndroid = "ANDROID"
+	PlatformIOS     = "IOS"
+)
+
+// Device is an app install that can receive push notifications. A token belongs to one user at a time.
+type Device struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Platform  string     `json:"platform"`
+	Token     string     `json:"token"`
+	CreatedAt time.Time  `gorm:"<-:create" json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type RegisterDeviceRequest struct {
+	Platform string `json:"platform" binding:"required,oneof=ANDROID IOS"`
+	Token    string `json:"token" binding:"required,max=512"`
+}
+
+func NewDevice(userID string, req RegisterDeviceRequest, now time.Time) Device {
+	return Device{
+		ID:        ulid.Make().String(),
+		UserID:    userID,
+		Platform:  req.Platform,
+		Token:     req.Token,
+		CreatedAt: now,
+		UpdatedAt: &now,
+	}
+}
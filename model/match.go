@@ -0,0 +1,194 @@
+package model
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	MatchStatusActive    = "ACTIVE"
+	MatchStatusUnmatched = "UNMATCHED"
+	MatchStatusExpired   = "EXPIRED"
+
+	FirstMoveAnyone     = "ANYONE"
+	FirstMoveWomenFirst = "WOMEN_FIRST"
+	FirstMoveFirstLiker = "FIRST_LIKER"
+)
+
+type Match struct {
+	ID            string     `json:"id"`
+	UserID        string     `json:"user_id"`
+	MatchedUserID string     `json:"matched_user_id"`
+	Status        string     `json:"status"`
+	MatchedAt     time.Time  `json:"matched_at"`
+	UnmatchedAt   *time.Time `json:"-"`
+	UnmatchedBy   *string    `json:"-"`
+	UnmatchReason *string    `json:"-"`
+	UnmatchDetail *string    `json:"-"`
+	// ExpiresAt is when the match ends unless a message is sent, it's cleared by the first message
+	ExpiresAt *time.Time `json:"expires_at"`
+	// FirstMoveBy is the only participant who can send the first message, anyone can when it's empty
+	FirstMoveBy    *string    `json:"first_move_by"`
+	FirstMessageAt *time.Time `json:"first_message_at"`
+	ExtendedAt     *time.Time `json:"-"`
+	CreatedAt      time.Time  `gorm:"<-:create" json:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at"`
+
+	User        User `gorm:"foreignKey:UserID" json:"-"`
+	MatchedUser User `gorm:"foreignKey:MatchedUserID" json:"-"`
+}
+
+type UnmatchRequest struct {
+	Reason string `json:"reason" binding:"omitempty,oneof=NO_CHEMISTRY NOT_INTERESTED INAPPROPRIATE SPAM FAKE_PROFILE OTHER"`
+	Detail string `json:"detail" binding:"max=500"`
+}
+
+type MatchProfile struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Bio    string `json:"bio"`
+	Gender string `json:"gender"`
+	Age    int    `json:"age"`
+	Photo  *Image `json:"photo"`
+}
+
+type MatchResponse struct {
+	ID        string       `json:"id"`
+	Status    string       `json:"status"`
+	MatchedAt time.Time    `json:"matched_at"`
+	User      MatchProfile `json:"user"`
+
+	// ExpiresAt and FirstMoveBy only apply until the first message is sent
+	ExpiresAt   *time.Time `json:"expires_at"`
+	FirstMoveBy *string    `json:"first_move_by"`
+
+	// Comments left on the likes that led to the match, oldest first
+	Comments []LikeComment `json:"comments,omitempty"`
+}
+
+// MatchEvent tells each participant who they matched with.
+type MatchEvent struct {
+	MatchID string `json:"match_id"`
+	UserID  string `json:"user_id"`
+}
+
+// NewMatch creates an active match between two users. Participants are stored in a stable order
+// so the pair can only ever have a single row.
+func NewMatch(userID, matchedUserID string, matchedAt time.Time) Match {
+	if matchedUserID < userID {
+		userID, matchedUserID = matchedUserID, userID
+	}
+
+	return Match{
+		ID:            ulid.Make().String(),
+		UserID:        userID,
+		MatchedUserID: matchedUserID,
+		Status:        MatchStatusActive,
+		MatchedAt:     matchedAt,
+		CreatedAt:     matchedAt,
+	}
+}
+
+// Unmatch ends the match for both participants, keeping who ended it and why for trust & safety.
+func (m *Match) Unmatch(userID string, req UnmatchRequest, now time.Time) {
+	m.Status = MatchStatusUnmatched
+	m.UnmatchedAt = &now
+	m.UnmatchedBy = &userID
+	m.UpdatedAt = &now
+
+	if req.Reason != "" {
+		m.UnmatchReason = &req.Reason
+	}
+
+	if req.Detail != "" {
+		m.UnmatchDetail = &req.Detail
+	}
+}
+
+// ApplyRules sets who sends the first message and how long the match has for it, a zero window never expires.
+func (m *Match) ApplyRules(firstMoveBy *string, expiresAfter time.Duration) {
+	m.FirstMoveBy = firstMoveBy
+
+	if expiresAfter > 0 {
+		expiresAt := m.MatchedAt.Add(expiresAfter)
+		m.ExpiresAt = &expiresAt
+	}
+}
+
+// IsExpired reports whether the match ran out of time, including when the expiry job hasn't caught up yet.
+func (m *Match) IsExpired(now time.Time) bool {
+	if m.Status == MatchStatusExpired {
+		return true
+	}
+
+	return m.FirstMessageAt == nil && m.ExpiresAt != nil && !now.Before(*m.ExpiresAt)
+}
+
+// CanMessage reports whether the user is allowed to send a message under the match's first move rule.
+func (m *Match) CanMessage(userID string) bool {
+	return m.FirstMessageAt != nil || m.FirstMoveBy == nil || *m.FirstMoveBy == userID
+}
+
+// Extend gives the match another window for its first message, counted from its current expiry.
+func (m *Match) Extend(window time.Duration, now time.Time) {
+	expiresAt := m.ExpiresAt.Add(window)
+	m.ExpiresAt = &expiresAt
+	m.ExtendedAt = &now
+	m.UpdatedAt = &now
+}
+
+func (m *Match) HasParticipant(userID string) bool {
+	return m.UserID == userID || m.MatchedUserID == userID
+}
+
+// OtherUserID returns the participant that isn't the given user.
+func (m *Match) OtherUserID(userID string) string {
+	if m.UserID == userID {
+		return m.MatchedUserID
+	}
+
+	return m.UserID
+}
+
+// ToMatchResponse shapes the match from the point of view of the given user.
+func (m *Match) ToMatchResponse(viewerID string) MatchResponse {
+	other := m.MatchedUser
+	if m.MatchedUserID == viewerID {
+		other = m.User
+	}
+
+	res := MatchResponse{
+		ID:        m.ID,
+		Status:    m.Status,
+		MatchedAt: m.MatchedAt,
+		User:      other.ToMatchProfile(),
+	}
+
+	if m.FirstMessageAt == nil {
+		res.ExpiresAt = m.ExpiresAt
+		res.FirstMoveBy = m.FirstMoveBy
+	}
+
+	return res
+}
+
+// FirstMover returns who sends the first message of a match under the given rule, nil when anyone can.
+// firstLiker is the user whose like was waiting when the other one liked back.
+func FirstMover(rule string, firstLiker, secondLiker *User) *string {
+	switch rule {
+	case FirstMoveWomenFirst:
+		// only decides between a woman and a man, anyone goes first otherwise
+		if firstLiker.Gender == GenderFemale && secondLiker.Gender != GenderFemale {
+			return &firstLiker.ID
+		}
+
+		if secondLiker.Gender == GenderFemale && firstLiker.Gender != GenderFemale {
+			return &secondLiker.ID
+		}
+	case FirstMoveFirstLiker:
+		return &firstLiker.ID
+	}
+
+	return nil
+}
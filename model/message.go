@@ -0,0 +1,114 @@
+package model
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// Conversation is the chat of a match, participants are stored in the same order as the match.
+type Conversation struct {
+	ID                    string     `json:"id"`
+	MatchID               string     `json:"match_id"`
+	UserID                string     `json:"user_id"`
+	MatchedUserID         string     `json:"matched_user_id"`
+	LastMessageAt         *time.Time `json:"last_message_at"`
+	UserLastReadAt        *time.Time `json:"-"`
+	MatchedUserLastReadAt *time.Time `json:"-"`
+	CreatedAt             time.Time  `gorm:"<-:create" json:"created_at"`
+	UpdatedAt             *time.Time `json:"updated_at"`
+
+	User        User `gorm:"foreignKey:UserID" json:"-"`
+	MatchedUser User `gorm:"foreignKey:MatchedUserID" json:"-"`
+}
+
+type Message struct {
+	ID             string     `json:"id"`
+	ConversationID string     `json:"conversation_id"`
+	SenderID       string     `json:"sender_id"`
+	Body           string     `json:"body"`
+	CreatedAt      time.Time  `gorm:"<-:create" json:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at"`
+	DeletedAt      *time.Time `json:"-"`
+
+	Attachments []Attachment `gorm:"foreignKey:MessageID" json:"attachments,omitempty"`
+}
+
+// MessageEvent carries a new message to the devices of both participants.
+type MessageEvent struct {
+	MatchID string  `json:"match_id"`
+	Message Message `json:"message"`
+}
+
+// TypingEvent tells the other participant someone is writing.
+type TypingEvent struct {
+	MatchID string `json:"match_id"`
+	UserID  string `json:"user_id"`
+}
+
+// ReadReceipt tells the other participant their messages were read up to ReadAt.
+type ReadReceipt struct {
+	MatchID string    `json:"match_id"`
+	UserID  string    `json:"user_id"`
+	ReadAt  time.Time `json:"read_at"`
+}
+
+type SendMessageRequest struct {
+	Body string `json:"body" binding:"required,max=1000"`
+}
+
+type ConversationResponse struct {
+	ID            string       `json:"id"`
+	MatchID       string       `json:"match_id"`
+	User          MatchProfile `json:"user"`
+	LastMessage   *Message     `json:"last_message"`
+	LastMessageAt *time.Time   `json:"last_message_at"`
+	UnreadCount   int64        `json:"unread_count"`
+}
+
+// NewConversation opens the chat of the given match.
+func NewConversation(match *Match, now time.Time) Conversation {
+	return Conversation{
+		ID:            ulid.Make().String(),
+		MatchID:       match.ID,
+		UserID:        match.UserID,
+		MatchedUserID: match.MatchedUserID,
+		CreatedAt:     now,
+	}
+}
+
+func NewMessage(conversationID, senderID, body string, sentAt time.Time) Message {
+	return Message{
+		ID:             ulid.Make().String(),
+		ConversationID: conversationID,
+		SenderID:       senderID,
+		Body:           body,
+		CreatedAt:      sentAt,
+	}
+}
+
+// ToConversationResponse shapes the conversation from the point of view of the given user.
+func (c *Conversation) ToConversationResponse(viewerID string, lastMessage *Message, unread int64) ConversationResponse {
+	other := c.MatchedUser
+	if c.MatchedUserID == viewerID {
+		other = c.User
+	}
+
+	return ConversationResponse{
+		ID:            c.ID,
+		MatchID:       c.MatchID,
+		User:          other.ToMatchProfile(),
+		LastMessage:   lastMessage,
+		LastMessageAt: c.LastMessageAt,
+		UnreadCount:   unread,
+	}
+}
+
+// OtherUserID returns the participant that isn't the given user.
+func (c *Conversation) OtherUserID(userID string) string {
+	if c.UserID == userID {
+		return c.MatchedUserID
+	}
+
+	return c.UserID
+}
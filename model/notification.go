@@ -1,33 +1,187 @@
 package model
 
-import "time"
-
-// NotificationRepository :nodoc:
-type NotificationRepository interface {
-	Create(notif Notification) error
-}
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 // Notification :nodoc:
 type Notification struct {
-	ID        string     `json:"id"`
-	UserID    string     `json:"user_id"`
-	Content   string     `json:"content"`
-	IsRead    bool       `json:"is_read"`
+	ID      string `json:"id"`
+	UserID  string `json:"user_id"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	IsRead  bool   `json:"is_read"`
+	// ReferenceID is the reaction that caused the notification, so it can be taken back on rewind
+	ReferenceID string `json:"-"`
+	// Seq orders notifications by when they were stored, streams resume from it. ULIDs made on different
+	// nodes don't sort by that.
+	Seq       int64      `gorm:"autoIncrement" json:"seq"`
 	CreatedAt time.Time  `gorm:"<-:create" json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at"`
 	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
-// MatchMessage :nodoc:
-type MatchMessage struct {
-	Type    string `json:"type"`
-	UserID  string `json:"user_id"`
-	Message string `json:"message"`
+// Notification types, each has a payload below and copy under the same key in every locale.
+const (
+	NotificationTypeMatch                = "MATCH"
+	NotificationTypeLikeReceived         = "LIKE_RECEIVED"
+	NotificationTypeMessage              = "MESSAGE"
+	NotificationTypeMatchExpired         = "MATCH_EXPIRED"
+	NotificationTypeSubscriptionStarted  = "SUBSCRIPTION_STARTED"
+	NotificationTypeSubscriptionRenewed  = "SUBSCRIPTION_RENEWED"
+	NotificationTypeSubscriptionCanceled = "SUBSCRIPTION_CANCELED"
+	NotificationTypePaymentFailed        = "PAYMENT_FAILED"
+
+	// NotificationTypeSubscription is what billing notifications were stored as before they were split by event.
+	// Nothing sends it anymore, it's registered so old rows still filter and decode.
+	NotificationTypeSubscription = "SUBSCRIPTION"
+)
+
+// Channels a notification can be delivered over. In-app is the stored notification itself, so it always goes out.
+const (
+	NotificationChannelInApp = "IN_APP"
+	NotificationChannelPush  = "PUSH"
+	NotificationChannelEmail = "EMAIL"
+)
+
+// NotificationTypes is the registry of notification types.
+var NotificationTypes = map[string]NotificationKind{
+	NotificationTypeMatch: {
+		NewPayload: func() NotificationPayload { return &MatchPayload{} },
+		Channels:   []string{NotificationChannelInApp, NotificationChannelPush},
+	},
+	NotificationTypeLikeReceived: {
+		NewPayload: func() NotificationPayload { return &LikeReceivedPayload{} },
+		Channels:   []string{NotificationChannelInApp, NotificationChannelPush},
+	},
+	NotificationTypeMessage: {
+		NewPayload: func() NotificationPayload { return &MessagePayload{} },
+		Channels:   []string{NotificationChannelInApp, NotificationChannelPush},
+	},
+	NotificationTypeMatchExpired: {
+		NewPayload: func() NotificationPayload { return &MatchExpiredPayload{} },
+		Channels:   []string{NotificationChannelInApp, NotificationChannelPush},
+	},
+	NotificationTypeSubscriptionStarted: {
+		NewPayload:    func() NotificationPayload { return &SubscriptionPayload{Type: NotificationTypeSubscriptionStarted} },
+		Channels:      []string{NotificationChannelInApp, NotificationChannelPush, NotificationChannelEmail},
+		Transactional: true,
+	},
+	NotificationTypeSubscriptionRenewed: {
+		NewPayload:    func() NotificationPayload { return &SubscriptionPayload{Type: NotificationTypeSubscriptionRenewed} },
+		Channels:      []string{NotificationChannelInApp, NotificationChannelEmail},
+		Transactional: true,
+	},
+	NotificationTypeSubscriptionCanceled: {
+		NewPayload:    func() NotificationPayload { return &SubscriptionPayload{Type: NotificationTypeSubscriptionCanceled} },
+		Channels:      []string{NotificationChannelInApp, NotificationChannelPush, NotificationChannelEmail},
+		Transactional: true,
+	},
+	NotificationTypePaymentFailed: {
+		NewPayload:    func() NotificationPayload { return &PaymentFailedPayload{} },
+		Channels:      []string{NotificationChannelInApp, NotificationChannelPush, NotificationChannelEmail},
+		Transactional: true,
+	},
+	NotificationTypeSubscription: {
+		NewPayload:    func() NotificationPayload { return &LegacySubscriptionPayload{} },
+		Transactional: true,
+	},
 }
 
-func NewMatchNotification(target, content string) Notification {
-	return Notification{
-		UserID:  target,
-		Content: content,
+type (
+	NotificationKind struct {
+		// NewPayload decodes stored payloads back into their struct
+		NewPayload func() NotificationPayload
+		// Channels are where notifications of the type are delivered
+		Channels []string
+		// Transactional notifications go out on every channel right away, preferences and quiet hours don't apply
+		Transactional bool
 	}
+
+	// NotificationPayload is the typed data of a notification, it's also what its copy is rendered from.
+	NotificationPayload interface {
+		NotificationType() string
+	}
+
+	// MatchPayload tells a user who they matched with.
+	MatchPayload struct {
+		MatchID string `json:"match_id"`
+		UserID  string `json:"user_id"`
+	}
+
+	// LikeReceivedPayload tells a user someone liked them. Only super likes reveal who.
+	LikeReceivedPayload struct {
+		UserID string `json:"user_id"`
+		Super  bool   `json:"super"`
+	}
+
+	MessagePayload struct {
+		MatchID   string `json:"match_id"`
+		MessageID string `json:"message_id"`
+		UserID    string `json:"user_id"`
+	}
+
+	MatchExpiredPayload struct {
+		MatchID string `json:"match_id"`
+		UserID  string `json:"user_id"`
+	}
+
+	// SubscriptionPayload covers a subscription starting, renewing and being canceled, which only differ in copy.
+	SubscriptionPayload struct {
+		Type           string    `json:"-"`
+		SubscriptionID string    `json:"subscription_id"`
+		ExpiresAt      time.Time `json:"expires_at"`
+	}
+
+	// PaymentFailedPayload has ActiveUntil set when the user still has a subscription to fall back on.
+	PaymentFailedPayload struct {
+		ActiveUntil *time.Time `json:"active_until"`
+	}
+
+	// LegacySubscriptionPayload is the empty payload of old SUBSCRIPTION notifications, their message was
+	// written out when they were sent.
+	LegacySubscriptionPayload struct{}
+
+	// NotificationContent is what's stored in a notification's content, the copy is rendered in the
+	// recipient's language when it's sent.
+	NotificationContent struct {
+		Type    string              `json:"type"`
+		Title   string              `json:"title"`
+		Message string              `json:"message"`
+		Data    NotificationPayload `json:"data"`
+	}
+)
+
+func (MatchPayload) NotificationType() string              { return NotificationTypeMatch }
+func (LikeReceivedPayload) NotificationType() string       { return NotificationTypeLikeReceived }
+func (MessagePayload) NotificationType() string            { return NotificationTypeMessage }
+func (MatchExpiredPayload) NotificationType() string       { return NotificationTypeMatchExpired }
+func (p SubscriptionPayload) NotificationType() string     { return p.Type }
+func (PaymentFailedPayload) NotificationType() string      { return NotificationTypePaymentFailed }
+func (LegacySubscriptionPayload) NotificationType() string { return NotificationTypeSubscription }
+
+// DecodeNotificationPayload turns a stored payload back into the struct registered for its type.
+func DecodeNotificationPayload(notificationType string, data []byte) (NotificationPayload, error) {
+	kind, ok := NotificationTypes[notificationType]
+	if !ok {
+		return nil, fmt.Errorf("unknown notification type %q", notificationType)
+	}
+
+	payload := kind.NewPayload()
+	if err := json.Unmarshal(data, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// NotificationReplayLimit caps how many missed notifications a stream replays per query.
+const NotificationReplayLimit = 100
+
+// NotificationRequest pages through the inbox, optionally narrowed to one type of notification.
+type NotificationRequest struct {
+	PaginationRequest
+	Type string `form:"type" binding:"omitempty,oneof=MATCH LIKE_RECEIVED MESSAGE MATCH_EXPIRED SUBSCRIPTION_STARTED SUBSCRIPTION_RENEWED SUBSCRIPTION_CANCELED PAYMENT_FAILED SUBSCRIPTION"`
 }
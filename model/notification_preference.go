@@ -0,0 +1,154 @@
+package model
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// QuietHoursLayout is how quiet hours are written, a time of day in the user's timezone.
+const QuietHoursLayout = "15:04"
+
+// NotificationPreference turns a channel off or back on for a notification type. Everything is on until the user says otherwise.
+type NotificationPreference struct {
+	UserID    string    `json:"-"`
+	Type      string    `json:"type"`
+	Channel   string    `json:"channel"`
+	Enabled   bool      `json:"enabled"`
+	UpdatedAt time.Time `json:"-"`
+}
+
+// QuietHours is the part of the day push and email are held back, it runs past midnight when it ends before it starts.
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type NotificationPreferences struct {
+	// Preferences has every type and channel the user can turn off, transactional notifications can't be
+	Preferences []NotificationPreference `json:"preferences"`
+	QuietHours  *QuietHours              `json:"quiet_hours"`
+	Timezone    string                   `json:"timezone"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceRequest `json:"preferences" binding:"required,min=1,dive"`
+}
+
+type NotificationPreferenceRequest struct {
+	Type    string `json:"type" binding:"required"`
+	Channel string `json:"channel" binding:"required,oneof=IN_APP PUSH EMAIL"`
+	Enabled *bool  `json:"enabled" binding:"required"`
+}
+
+type UpdateQuietHoursRequest struct {
+	Start string `json:"start" binding:"required,datetime=15:04"`
+	End   string `json:"end" binding:"required,datetime=15:04,nefield=Start"`
+}
+
+// DeferredNotification is a notification held back by the recipient's quiet hours, delivered on its channel once they end.
+type DeferredNotification struct {
+	ID             string    `json:"id"`
+	NotificationID string    `json:"notification_id"`
+	UserID         string    `json:"user_id"`
+	Channel        string    `json:"channel"`
+	Type           string    `json:"type"`
+	ReferenceID    string    `json:"reference_id"`
+	Title          string    `json:"title"`
+	Body           string    `json:"body"`
+	Content        string    `json:"content"`
+	DeliverAt      time.Time `json:"deliver_at"`
+	CreatedAt      time.Time `gorm:"<-:create" json:"created_at"`
+}
+
+// ConfigurableChannel tells whether users can turn the channel off for the notification type. The inbox
+// always gets every notification.
+func ConfigurableChannel(notificationType, channel string) bool {
+	kind, ok := NotificationTypes[notificationType]
+	if !ok || kind.Transactional || channel == NotificationChannelInApp {
+		return false
+	}
+
+	for _, c := range kind.Channels {
+		if c == channel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// QuietHours returns the user's quiet hours, nil when they haven't set any.
+func (u *User) QuietHours() *QuietHours {
+	if u.QuietHoursStart == "" || u.QuietHoursEnd == "" {
+		return nil
+	}
+
+	return &QuietHours{Start: u.QuietHoursStart, End: u.QuietHoursEnd}
+}
+
+// QuietUntil returns when the user's quiet hours end if now falls in them.
+func (u *User) QuietUntil(now time.Time) (time.Time, bool) {
+	quietHours := u.QuietHours()
+	if quietHours == nil {
+		return time.Time{}, false
+	}
+
+	start, err := time.Parse(QuietHoursLayout, quietHours.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := time.Parse(QuietHoursLayout, quietHours.End)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	local := now.In(u.Location())
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+
+	until := time.Date(local.Year(), local.Month(), local.Day(), end.Hour(), end.Minute(), 0, 0, local.Location())
+
+	switch {
+	case startMinute < endMinute:
+		if minute < startMinute || minute >= endMinute {
+			return time.Time{}, false
+		}
+	case minute >= startMinute:
+		// runs past midnight and started today, so it ends tomorrow
+		until = until.AddDate(0, 0, 1)
+	case minute >= endMinute:
+		return time.Time{}, false
+	}
+
+	return until, true
+}
+
+func NewDeferredNotification(notification Notification, channel, title, body string, deliverAt time.Time) DeferredNotification {
+	return DeferredNotification{
+		ID:             ulid.Make().String(),
+		NotificationID: notification.ID,
+		UserID:         notification.UserID,
+		Channel:        channel,
+		Type:           notification.Type,
+		ReferenceID:    notification.ReferenceID,
+		Title:          title,
+		Body:           body,
+		Content:        notification.Content,
+		DeliverAt:      deliverAt,
+		CreatedAt:      notification.CreatedAt,
+	}
+}
+
+// Notification puts back the notification that was held back.
+func (d DeferredNotification) Notification() Notification {
+	return Notification{
+		ID:          d.NotificationID,
+		UserID:      d.UserID,
+		Type:        d.Type,
+		Content:     d.Content,
+		ReferenceID: d.ReferenceID,
+		CreatedAt:   d.CreatedAt,
+	}
+}
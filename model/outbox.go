@@ -0,0 +1,120 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// OutboxTopicNotification carries a notification for the dispatcher.
+	OutboxTopicNotification = "NOTIFICATION"
+	// OutboxTopicDeferredNotification carries a notification held back by quiet hours for the one channel it waited on.
+	OutboxTopicDeferredNotification = "DEFERRED_NOTIFICATION"
+)
+
+const (
+	// OutboxRetryBase is how long a failed message waits for its first retry, the wait doubles with every attempt after that
+	OutboxRetryBase = 5 * time.Second
+	// OutboxRetryCap is the longest a failed message waits between attempts
+	OutboxRetryCap = time.Hour
+	// OutboxLease hides a claimed message from other workers, it's picked up again if its worker dies with it
+	OutboxLease = 5 * time.Minute
+)
+
+// OutboxMessage is written in the same transaction as the change it's about, so it's sent if and only if the change is committed.
+type OutboxMessage struct {
+	ID          string    `json:"id"`
+	Topic       string    `json:"topic"`
+	ReferenceID string    `json:"reference_id"`
+	Payload     string    `json:"payload"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error"`
+	AvailableAt time.Time `json:"available_at"`
+	CreatedAt   time.Time `gorm:"<-:create" json:"created_at"`
+}
+
+// DeadLetter is an outbox message that ran out of attempts, kept for someone to look at.
+type DeadLetter struct {
+	ID          string    `json:"id"`
+	Topic       string    `json:"topic"`
+	ReferenceID string    `json:"reference_id"`
+	Payload     string    `json:"payload"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error"`
+	CreatedAt   time.Time `json:"created_at"`
+	FailedAt    time.Time `json:"failed_at"`
+}
+
+// NotificationMessage is what the outbox keeps of a notification until it's dispatched.
+type NotificationMessage struct {
+	UserID      string          `json:"user_id"`
+	ReferenceID string          `json:"reference_id"`
+	Type        string          `json:"type"`
+	Data        json.RawMessage `json:"data"`
+}
+
+func NewNotificationMessage(userID, referenceID string, payload NotificationPayload, now time.Time) (OutboxMessage, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+
+	b, err := json.Marshal(NotificationMessage{UserID: userID, ReferenceID: referenceID, Type: payload.NotificationType(), Data: data})
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+
+	return OutboxMessage{
+		ID:          ulid.Make().String(),
+		Topic:       OutboxTopicNotification,
+		ReferenceID: referenceID,
+		Payload:     string(b),
+		AvailableAt: now,
+		CreatedAt:   now,
+	}, nil
+}
+
+func NewDeferredNotificationMessage(deferred DeferredNotification, now time.Time) (OutboxMessage, error) {
+	b, err := json.Marshal(deferred)
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+
+	return OutboxMessage{
+		ID:          ulid.Make().String(),
+		Topic:       OutboxTopicDeferredNotification,
+		ReferenceID: deferred.ReferenceID,
+		Payload:     string(b),
+		AvailableAt: now,
+		CreatedAt:   now,
+	}, nil
+}
+
+// RetryAt returns when the message is tried again after failing its latest attempt.
+func (m OutboxMessage) RetryAt(now time.Time) time.Time {
+	backoff := OutboxRetryBase
+	for i := 1; i < m.Attempts && backoff < OutboxRetryCap; i++ {
+		backoff *= 2
+	}
+
+	if backoff > OutboxRetryCap {
+		backoff = OutboxRetryCap
+	}
+
+	return now.Add(backoff)
+}
+
+func NewDeadLetter(m OutboxMessage, now time.Time) DeadLetter {
+	return DeadLetter{
+		ID:          m.ID,
+		Topic:       m.Topic,
+		ReferenceID: m.ReferenceID,
+		Payload:     m.Payload,
+		Attempts:    m.Attempts,
+		LastError:   m.LastError,
+		CreatedAt:   m.CreatedAt,
+		FailedAt:    now,
+	}
+}
@@ -0,0 +1,38 @@
+package model
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+type PaginationRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// Normalize fills in the defaults for missing page and limit values.
+func (p *PaginationRequest) Normalize() {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+
+	if p.Limit < 1 {
+		p.Limit = DefaultPageLimit
+	}
+
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+}
+
+func (p *PaginationRequest) Offset() int {
+	return (p.Page - 1) * p.Limit
+}
+
+func (p *PaginationRequest) ToMeta(total int64) map[string]interface{} {
+	return map[string]interface{}{
+		"total": total,
+		"page":  p.Page,
+		"limit": p.Limit,
+	}
+}
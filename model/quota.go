@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+const (
+	QuotaSwipe     = "SWIPE"
+	QuotaSuperLike = "SUPER_LIKE"
+	QuotaRewind    = "REWIND"
+	QuotaBoost     = "BOOST"
+)
+
+var QuotaTypes = []string{QuotaSwipe, QuotaSuperLike, QuotaRewind, QuotaBoost}
+
+// Quota is how much of a limited action a user has left in the current period, a nil Limit means unlimited.
+type Quota struct {
+	Type      string    `json:"type"`
+	Limit     *int      `json:"limit"`
+	Used      int64     `json:"used"`
+	Remaining *int64    `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+}
+
+func NewQuota(quotaType string, limit *int, used int64, resetAt time.Time) Quota {
+	quota := Quota{
+		Type:    quotaType,
+		Limit:   limit,
+		Used:    used,
+		ResetAt: resetAt,
+	}
+
+	if limit != nil {
+		remaining := int64(*limit) - used
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		quota.Remaining = &remaining
+	}
+
+	return quota
+}
+
+func (q Quota) Exceeded() bool {
+	return q.Limit != nil && q.Used >= int64(*q.Limit)
+}
@@ -1,16 +1,48 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"github.com/oklog/ulid/v2"
 )
 
 const (
-	ReactionLike    = "LIKE"
-	ReactionDislike = "PASS"
+	ReactionLike      = "LIKE"
+	ReactionDislike   = "PASS"
+	ReactionSuperLike = "SUPER_LIKE"
 )
 
+// LikeTargetImage marks a like left on one of the profile's photos.
+const LikeTargetImage = "IMAGE"
+
+const (
+	BatchReactionCreated       = "created"
+	BatchReactionMatched       = "matched"
+	BatchReactionDuplicate     = "duplicate"
+	BatchReactionQuotaExceeded = "quota_exceeded"
+	BatchReactionRejected      = "rejected"
+	BatchReactionFailed        = "failed"
+)
+
+// LikeReactions are the reaction types that can turn into a match.
+var LikeReactions = []string{ReactionLike, ReactionSuperLike}
+
+// LikesTeaserSize is how many blurred thumbnails the likes teaser shows.
+const LikesTeaserSize = 5
+
+// TeaserPath is where the blurred thumbnail of a pending like is served from, it names the like
+// rather than the photo so the signed URL gives nothing away.
+func TeaserPath(reactionID string) string {
+	return "/reactions/likes/teasers/" + reactionID
+}
+
+// DeckTokenTTL is how long a profile served in a deck can still be swiped.
+const DeckTokenTTL = 24 * time.Hour
+
+// RewindWindow is how long after swiping a reaction can still be undone.
+const RewindWindow = 5 * time.Minute
+
 type Reaction struct {
 	ID            string     `json:"id"`
 	UserID        string     `json:"user_id"`
@@ -20,22 +52,188 @@ type Reaction struct {
 	CreatedAt     time.Time  `gorm:"<-:create" json:"created_at"`
 	UpdatedAt     *time.Time `json:"updated_at"`
 	DeletedAt     *time.Time `json:"deleted_at"`
+	Comment       *string    `json:"comment"`
+	TargetType    *string    `json:"target_type"`
+	TargetID      *string    `json:"target_id"`
+
+	// IdempotencyKey and SwipedAt come from clients replaying swipes made offline. SwipedAt is only
+	// informational, quotas are counted on CreatedAt so a replay can't be backdated into an earlier window.
+	IdempotencyKey *string    `json:"idempotency_key"`
+	SwipedAt       *time.Time `json:"swiped_at"`
+
+	// ReplacedBy is set on a pass that was swiped again after resurfacing in the deck
+	ReplacedBy *string `json:"-"`
 
-	// User        User `json:"user"`
-	// MatchedUser User `json:"matched_user"`
+	// DeckToken lets the recipient of a like swipe back on whoever sent it
+	DeckToken string `gorm:"-" json:"deck_token,omitempty"`
+
+	User        User `gorm:"foreignKey:UserID" json:"-"`
+	MatchedUser User `gorm:"foreignKey:MatchedUserID" json:"-"`
 }
 
 type ReactionRequest struct {
 	UserID        string `json:"-"`
 	MatchedUserID string `json:"matched_user_id" binding:"required,ulid"`
-	Type          string `json:"type" binding:"oneof=LIKE PASS"`
+	Type          string `json:"type" binding:"oneof=LIKE PASS SUPER_LIKE"`
+	Comment       string `json:"comment" binding:"max=150"`
+	TargetType    string `json:"target_type" binding:"required_with=TargetID,omitempty,oneof=IMAGE"`
+	TargetID      string `json:"target_id" binding:"required_with=TargetType"`
+
+	IdempotencyKey string     `json:"idempotency_key" binding:"max=64"`
+	SwipedAt       *time.Time `json:"swiped_at"`
+
+	// DeckToken is handed out with every profile served by the deck
+	DeckToken string `json:"deck_token"`
+}
+
+type BatchReactionRequest struct {
+	Reactions []ReactionRequest `json:"reactions" binding:"required,min=1,max=100,dive"`
+}
+
+type BatchReactionResult struct {
+	IdempotencyKey string    `json:"idempotency_key"`
+	MatchedUserID  string    `json:"matched_user_id"`
+	Status         string    `json:"status"`
+	Reaction       *Reaction `json:"reaction,omitempty"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// SentReactionRequest filters the swipe history. Dates are whole days in the user's timezone, both ends included.
+type SentReactionRequest struct {
+	PaginationRequest
+	Type string `form:"type" binding:"omitempty,oneof=LIKE PASS SUPER_LIKE"`
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// SentReactionFilter is a resolved SentReactionRequest, Until is exclusive.
+type SentReactionFilter struct {
+	Type  string
+	Since *time.Time
+	Until *time.Time
+}
+
+type SentReaction struct {
+	ID        string       `json:"id"`
+	Type      string       `json:"type"`
+	Comment   *string      `json:"comment"`
+	CreatedAt time.Time    `json:"created_at"`
+	Matched   bool         `json:"matched"`
+	MatchedAt *time.Time   `json:"matched_at"`
+	User      MatchProfile `json:"user"`
+}
+
+// LikesSummary teases the likes waiting on the user. Thumbnails are blurred so likers stay anonymous
+// unless the plan can see likes.
+type LikesSummary struct {
+	Count       int64    `json:"count"`
+	Thumbnails  []string `json:"thumbnails"`
+	CanSeeLikes bool     `json:"can_see_likes"`
+}
+
+// LikeComment is a message left on a like, it opens the conversation once the pair matches.
+type LikeComment struct {
+	UserID     string    `json:"user_id"`
+	Comment    string    `json:"comment"`
+	TargetType *string   `json:"target_type"`
+	TargetID   *string   `json:"target_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (r *Reaction) IsLike() bool {
+	return r.Type == ReactionLike || r.Type == ReactionSuperLike
+}
+
+// CanResurface reports whether a pass has cooled down and the profile can be swiped again.
+func (r *Reaction) CanResurface(cutoff time.Time) bool {
+	return r.Type == ReactionDislike && r.CreatedAt.Before(cutoff)
+}
+
+// CanRewind reports whether the reaction is still within the rewind window.
+func (r *Reaction) CanRewind(now time.Time) bool {
+	return r.DeletedAt == nil && now.Sub(r.CreatedAt) <= RewindWindow
+}
+
+// HasComment reports whether the swipe carries a comment or points at a part of the profile.
+func (r *ReactionRequest) HasComment() bool {
+	return strings.TrimSpace(r.Comment) != "" || r.TargetType != ""
 }
 
 func (r *ReactionRequest) ToReactionModel() Reaction {
-	return Reaction{
+	reaction := Reaction{
 		ID:            ulid.Make().String(),
 		UserID:        r.UserID,
 		MatchedUserID: r.MatchedUserID,
 		Type:          r.Type,
 	}
+
+	if comment := strings.TrimSpace(r.Comment); comment != "" {
+		reaction.Comment = &comment
+	}
+
+	if r.TargetType != "" {
+		reaction.TargetType = &r.TargetType
+		reaction.TargetID = &r.TargetID
+	}
+
+	if r.IdempotencyKey != "" {
+		reaction.IdempotencyKey = &r.IdempotencyKey
+	}
+
+	reaction.SwipedAt = r.SwipedAt
+
+	return reaction
+}
+
+// ToFilter resolves the requested days to a time range in the given location.
+func (r *SentReactionRequest) ToFilter(loc *time.Location) (SentReactionFilter, error) {
+	filter := SentReactionFilter{Type: r.Type}
+
+	if r.From != "" {
+		since, err := time.ParseInLocation("2006-01-02", r.From, loc)
+		if err != nil {
+			return filter, err
+		}
+
+		filter.Since = &since
+	}
+
+	if r.To != "" {
+		to, err := time.ParseInLocation("2006-01-02", r.To, loc)
+		if err != nil {
+			return filter, err
+		}
+
+		until := to.AddDate(0, 0, 1)
+		filter.Until = &until
+	}
+
+	return filter, nil
+}
+
+func (r *Reaction) ToSentReaction() SentReaction {
+	return SentReaction{
+		ID:        r.ID,
+		Type:      r.Type,
+		Comment:   r.Comment,
+		CreatedAt: r.CreatedAt,
+		Matched:   r.MatchedAt != nil,
+		MatchedAt: r.MatchedAt,
+		User:      r.MatchedUser.ToMatchProfile(),
+	}
+}
+
+func (r *Reaction) ToLikeComment() LikeComment {
+	comment := LikeComment{
+		UserID:     r.UserID,
+		TargetType: r.TargetType,
+		TargetID:   r.TargetID,
+		CreatedAt:  r.CreatedAt,
+	}
+
+	if r.Comment != nil {
+		comment.Comment = *r.Comment
+	}
+
+	return comment
 }
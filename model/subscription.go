@@ -11,34 +11,67 @@ import (
 const (
 	SubscriptionPlanBasic = "BASIC"
 	SubscriptionPlanPro   = "PRO"
+	SubscriptionPlanFree  = "FREE" // limits of users without a subscription, can't be subscribed to
 
 	SubscriptionStatusActive   = "active"
 	SubscriptionStatusExpired  = "expired"
 	SubscriptionStatusCanceled = "canceled"
+
+	FeatureUnlimitedLikes = "unlimited_likes"
+	FeatureSeeLikes       = "see_likes"
+	FeatureTopPicks       = "top_picks"
+	FeatureRewind         = "rewind"
+	FeatureStartOver      = "start_over"
+	FeatureExtendMatch    = "extend_match"
+
+	QuotaPeriodDaily  = "DAILY"
+	QuotaPeriodWeekly = "WEEKLY"
 )
 
 var SubscriptionFeatures = map[string][]string{
 	"BASIC": {
-		"unlimited_likes",
+		FeatureUnlimitedLikes,
+		FeatureStartOver,
+		FeatureExtendMatch,
 	},
 	"PRO": {
-		"unlimited_likes",
-		"see_likes",
+		FeatureUnlimitedLikes,
+		FeatureSeeLikes,
+		FeatureTopPicks,
+		FeatureRewind,
+		FeatureStartOver,
+		FeatureExtendMatch,
 	},
 }
 
+// HasFeature reports whether the given plan unlocks the given feature.
+func HasFeature(planName, feature string) bool {
+	for _, f := range SubscriptionFeatures[planName] {
+		if f == feature {
+			return true
+		}
+	}
+
+	return false
+}
+
 type SubscriptionRequest struct {
 	PlanID int `json:"plan_id" binding:"required,numeric"`
 }
 
 type SubscriptionPlan struct {
-	ID            int             `json:"id"`
-	Name          string          `json:"name"`
-	Price         decimal.Decimal `json:"price"`
-	Features      pq.StringArray  `gorm:"type:text[]" json:"features"`
-	StripePriceID string          `json:"-"`
-	CreatedAt     time.Time       `gorm:"<-:create" json:"created_at"`
-	UpdatedAt     *time.Time      `json:"updated_at"`
+	ID               int             `json:"id"`
+	Name             string          `json:"name"`
+	Price            decimal.Decimal `json:"price"`
+	Features         pq.StringArray  `gorm:"type:text[]" json:"features"`
+	SuperLikeQuota   int             `json:"super_like_quota"`
+	SuperLikePeriod  string          `json:"super_like_period"`
+	DailySwipeLimit  *int            `json:"daily_swipe_limit"`  // nil means unlimited
+	DailyRewindLimit *int            `json:"daily_rewind_limit"` // nil means unlimited
+	WeeklyBoostQuota int             `json:"weekly_boost_quota"`
+	StripePriceID    string          `json:"-"`
+	CreatedAt        time.Time       `gorm:"<-:create" json:"created_at"`
+	UpdatedAt        *time.Time      `json:"updated_at"`
 }
 
 type Subscription struct {
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+const (
+	TopPicksDailySize = 10
+	TopPicksFreeSize  = 3
+)
+
+type TopPick struct {
+	ID           string     `json:"id"`
+	UserID       string     `json:"user_id"`
+	PickedUserID string     `json:"picked_user_id"`
+	Score        int        `json:"score"`
+	ExpiredAt    time.Time  `json:"expired_at"`
+	CreatedAt    time.Time  `gorm:"<-:create" json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+
+	PickedUser User `gorm:"foreignKey:PickedUserID" json:"picked_user"`
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -26,6 +27,26 @@ var PreferenceMale = SupportedPreference["MALE"]
 var PreferenceFemale = SupportedPreference["FEMALE"]
 var PreferenceBoth = SupportedPreference["BOTH"]
 
+// PreferredGenders returns the genders a preference is interested in, nil when it doesn't filter on gender.
+func PreferredGenders(preference string) []string {
+	val, ok := SupportedPreference[preference]
+	if !ok {
+		return nil
+	}
+
+	if val == PreferenceBoth {
+		return []string{GenderMale, GenderFemale}
+	}
+
+	return []string{val}
+}
+
+// DefaultTimezone is used for users who haven't told us their timezone.
+const DefaultTimezone = "UTC"
+
+// DefaultLanguage is used for users who haven't told us their language, and for copy that isn't translated yet.
+const DefaultLanguage = "en"
+
 type RefreshToken struct {
 	RefreshToken string `json:"refresh_token"`
 }
@@ -44,6 +65,16 @@ type RegisterUser struct {
 	Preference  string   `json:"preference" binding:"oneof=MALE FEMALE BOTH"`
 	DateOfBirth string   `json:"date_of_birth" binding:"required" time_format:"2006-01-02"`
 	Images      []string `json:"images" binding:"required,min=1,max=5"`
+	Timezone    string   `json:"timezone" binding:"omitempty,timezone"`
+	Language    string   `json:"language" binding:"omitempty,oneof=en id"`
+}
+
+type UpdateTimezoneRequest struct {
+	Timezone string `json:"timezone" binding:"required,timezone"`
+}
+
+type UpdateLanguageRequest struct {
+	Language string `json:"language" binding:"required,oneof=en id"`
 }
 
 type AuthUser struct {
@@ -62,7 +93,14 @@ type User struct {
 	Preference       string     `json:"preference"`
 	Age              int        `json:"age"`
 	Images           []Image    `json:"images"`
+	ImageCount       int        `gorm:"->" json:"-"`
 	StripeCustomerID string     `json:"-"`
+	Timezone         string     `json:"timezone"`
+	Language         string     `json:"language"`
+	QuietHoursStart  string     `json:"-"`
+	QuietHoursEnd    string     `json:"-"`
+	DeckToken        string     `gorm:"-" json:"deck_token,omitempty"`
+	DeckResetAt      *time.Time `json:"-"`
 	CreatedAt        time.Time  `gorm:"<-:create" json:"created_at"`
 	UpdatedAt        *time.Time `json:"updated_at"`
 }
@@ -104,6 +142,69 @@ func (u *User) NewImageFromRequest(images []string) {
 	}
 }
 
+// CanDiscover applies the discovery rules of the deck to a single profile. Hidden are the people the deck
+// leaves out for the user: everyone they unmatched with or who unmatched them, which is how users report
+// and block each other.
+func (u *User) CanDiscover(other *User, hidden []string) bool {
+	if other.ID == u.ID || slices.Contains(hidden, other.ID) {
+		return false
+	}
+
+	genders := PreferredGenders(u.Preference)
+	if genders == nil {
+		return true
+	}
+
+	for _, gender := range genders {
+		if other.Gender == gender {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PassCutoff returns the time before which the user's passes have cooled down, so those profiles can be
+// shown again. A zero cooldown keeps passes forever unless the user started over.
+func (u *User) PassCutoff(now time.Time, cooldown time.Duration) time.Time {
+	var cutoff time.Time
+	if cooldown > 0 {
+		cutoff = now.Add(-cooldown)
+	}
+
+	if u.DeckResetAt != nil && u.DeckResetAt.After(cutoff) {
+		cutoff = *u.DeckResetAt
+	}
+
+	return cutoff
+}
+
+// PrimaryImage returns the user's primary photo, falling back to the first one.
+func (u *User) PrimaryImage() *Image {
+	for i := range u.Images {
+		if u.Images[i].IsPrimary {
+			return &u.Images[i]
+		}
+	}
+
+	if len(u.Images) > 0 {
+		return &u.Images[0]
+	}
+
+	return nil
+}
+
+func (u *User) ToMatchProfile() MatchProfile {
+	return MatchProfile{
+		ID:     u.ID,
+		Name:   u.Name,
+		Bio:    u.Bio,
+		Gender: u.Gender,
+		Age:    u.Age,
+		Photo:  u.PrimaryImage(),
+	}
+}
+
 func (ru *RegisterUser) ToUserModel() *User {
 	dob, err := time.Parse("2006-01-02", ru.DateOfBirth)
 	if err != nil {
@@ -112,6 +213,16 @@ func (ru *RegisterUser) ToUserModel() *User {
 
 	age := calculateAge(dob)
 
+	timezone := ru.Timezone
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+
+	language := ru.Language
+	if language == "" {
+		language = DefaultLanguage
+	}
+
 	return &User{
 		ID:         ulid.Make().String(),
 		Name:       ru.Name,
@@ -121,9 +232,21 @@ func (ru *RegisterUser) ToUserModel() *User {
 		Gender:     ru.Gender,
 		Preference: ru.Preference,
 		Age:        age,
+		Timezone:   timezone,
+		Language:   language,
 	}
 }
 
+// Location is the user's timezone, daily limits reset at midnight there.
+func (u *User) Location() *time.Location {
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
 func calculateAge(birthDate time.Time) int {
 	today := time.Now()
 	age := today.Year() - birthDate.Year()
@@ -0,0 +1,45 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewLocalStorage(t.TempDir())
+	assert.NoError(t, err)
+
+	assert.NoError(t, storage.Put(ctx, "conversations/match1/file1", strings.NewReader("hello")))
+
+	r, err := storage.Open(ctx, "conversations/match1/file1")
+	assert.NoError(t, err)
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Close())
+	assert.Equal(t, "hello", string(b))
+
+	assert.NoError(t, storage.Delete(ctx, "conversations/match1/file1"))
+	_, err = storage.Open(ctx, "conversations/match1/file1")
+	assert.Equal(t, ErrNotFound, err)
+
+	// deleting twice is fine
+	assert.NoError(t, storage.Delete(ctx, "conversations/match1/file1"))
+}
+
+func TestLocalStorage_InvalidKey(t *testing.T) {
+	ctx := context.Background()
+	storage, err := NewLocalStorage(t.TempDir())
+	assert.NoError(t, err)
+
+	for _, key := range []string{"../escape", "/etc/passwd", "a/../../b", ""} {
+		assert.Equal(t, ErrInvalidKey, storage.Put(ctx, key, strings.NewReader("x")), key)
+
+		_, err = storage.Open(ctx, key)
+		assert.Equal(t, ErrInvalidKey, err, key)
+	}
+}
@@ -0,0 +1,84 @@
+package decktoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalid     = errors.New("invalid deck token")
+	ErrExpired     = errors.New("deck token has expired")
+	ErrEmptySecret = errors.New("deck token secret is empty")
+)
+
+type (
+	// Signer issues tokens for the profiles served in a deck, so a swipe can prove its target was actually
+	// shown to the swiper.
+	Signer struct {
+		secret   []byte
+		ttl      time.Duration
+		required bool
+	}
+
+	ISigner interface {
+		Sign(viewerID, profileID string, now time.Time) string
+		Verify(token, viewerID, profileID string, now time.Time) error
+		// Required reports whether swipes without a deck token are refused.
+		Required() bool
+	}
+)
+
+// NewSigner refuses an empty secret, anyone could mint tokens with it.
+func NewSigner(secret string, ttl time.Duration, required bool) (ISigner, error) {
+	if secret == "" {
+		return nil, ErrEmptySecret
+	}
+
+	return &Signer{secret: []byte(secret), ttl: ttl, required: required}, nil
+}
+
+// Sign returns "<expiry unix>.<signature>", the viewer and profile aren't part of the token since
+// they're always known when it's verified.
+func (s *Signer) Sign(viewerID, profileID string, now time.Time) string {
+	expiresAt := strconv.FormatInt(now.Add(s.ttl).Unix(), 10)
+
+	return expiresAt + "." + s.signature(viewerID, profileID, expiresAt)
+}
+
+func (s *Signer) Verify(token, viewerID, profileID string, now time.Time) error {
+	expiresAt, signature, found := strings.Cut(token, ".")
+	if !found {
+		return ErrInvalid
+	}
+
+	expiry, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil {
+		return ErrInvalid
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.signature(viewerID, profileID, expiresAt))) {
+		return ErrInvalid
+	}
+
+	if now.Unix() > expiry {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+func (s *Signer) Required() bool {
+	return s.required
+}
+
+func (s *Signer) signature(viewerID, profileID, expiresAt string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(viewerID + "|" + profileID + "|" + expiresAt))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,47 @@
+package decktoken
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner_Verify(t *testing.T) {
+	now := time.Now()
+	signer, err := NewSigner("secret", time.Hour, true)
+	assert.NoError(t, err)
+
+	other, err := NewSigner("other", time.Hour, true)
+	assert.NoError(t, err)
+
+	token := signer.Sign("viewer", "profile", now)
+
+	tests := []struct {
+		name      string
+		token     string
+		viewerID  string
+		profileID string
+		now       time.Time
+		expected  error
+	}{
+		{name: "valid", token: token, viewerID: "viewer", profileID: "profile", now: now, expected: nil},
+		{name: "other profile", token: token, viewerID: "viewer", profileID: "someone-else", now: now, expected: ErrInvalid},
+		{name: "other viewer", token: token, viewerID: "someone-else", profileID: "profile", now: now, expected: ErrInvalid},
+		{name: "expired", token: token, viewerID: "viewer", profileID: "profile", now: now.Add(2 * time.Hour), expected: ErrExpired},
+		{name: "tampered expiry", token: "9999999999" + token[len(token)-44:], viewerID: "viewer", profileID: "profile", now: now, expected: ErrInvalid},
+		{name: "malformed", token: "garbage", viewerID: "viewer", profileID: "profile", now: now, expected: ErrInvalid},
+		{name: "signed with another secret", token: other.Sign("viewer", "profile", now), viewerID: "viewer", profileID: "profile", now: now, expected: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, signer.Verify(tt.token, tt.viewerID, tt.profileID, tt.now))
+		})
+	}
+}
+
+func TestNewSigner_EmptySecret(t *testing.T) {
+	_, err := NewSigner("", time.Hour, true)
+	assert.ErrorIs(t, err, ErrEmptySecret)
+}
@@ -0,0 +1,126 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var ErrUnknownMessage = errors.New("unknown message")
+
+type (
+	// Catalog holds the copy for every locale, one JSON file per locale named after it, e.g. en.json.
+	// Each file maps a message key to a title and body written as text/template.
+	Catalog struct {
+		defaultLocale string
+		messages      map[string]map[string]message
+	}
+
+	message struct {
+		title *template.Template
+		body  *template.Template
+	}
+
+	// Message is rendered copy, ready to show.
+	Message struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+)
+
+var funcs = template.FuncMap{
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+}
+
+// Load parses every locale in the directory of fsys, the default locale has to be there.
+func Load(fsys fs.FS, dir, defaultLocale string) (*Catalog, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Catalog{defaultLocale: defaultLocale, messages: map[string]map[string]message{}}
+	for _, file := range files {
+		b, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		var raw map[string]struct {
+			Title string `json:"title"`
+			Body  string `json:"body"`
+		}
+		if err = json.Unmarshal(b, &raw); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		locale := strings.TrimSuffix(path.Base(file), ".json")
+		c.messages[locale] = map[string]message{}
+		for key, m := range raw {
+			title, err := template.New(key).Funcs(funcs).Option("missingkey=error").Parse(m.Title)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", file, key, err)
+			}
+
+			body, err := template.New(key).Funcs(funcs).Option("missingkey=error").Parse(m.Body)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", file, key, err)
+			}
+
+			c.messages[locale][key] = message{title: title, body: body}
+		}
+	}
+
+	if _, ok := c.messages[defaultLocale]; !ok {
+		return nil, fmt.Errorf("missing default locale %q", defaultLocale)
+	}
+
+	return c, nil
+}
+
+// MustLoad is Load for catalogs embedded in the binary, where a broken file is a programming error.
+func MustLoad(fsys fs.FS, dir, defaultLocale string) *Catalog {
+	c, err := Load(fsys, dir, defaultLocale)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// Render fills in the message for the locale, falling back to the default locale when the locale or
+// the message isn't translated.
+func (c *Catalog) Render(locale, key string, data interface{}) (Message, error) {
+	m, ok := c.messages[locale][key]
+	if !ok {
+		m, ok = c.messages[c.defaultLocale][key]
+		if !ok {
+			return Message{}, fmt.Errorf("%w: %s", ErrUnknownMessage, key)
+		}
+	}
+
+	var title, body strings.Builder
+	if err := m.title.Execute(&title, data); err != nil {
+		return Message{}, err
+	}
+
+	if err := m.body.Execute(&body, data); err != nil {
+		return Message{}, err
+	}
+
+	return Message{Title: title.String(), Body: body.String()}, nil
+}
+
+// Has reports whether the locale has its own copy for the key, without falling back.
+func (c *Catalog) Has(locale, key string) bool {
+	_, ok := c.messages[locale][key]
+
+	return ok
+}
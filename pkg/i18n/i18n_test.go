@@ -0,0 +1,71 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCatalog(t *testing.T) *Catalog {
+	fsys := fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{
+			"GREETING": {"title": "Hello", "body": "Hi {{.Name}}{{if .VIP}}, welcome back{{end}}"},
+			"RENEWED": {"title": "Renewed", "body": "Active until {{date .Until}}"}
+		}`)},
+		"locales/id.json": {Data: []byte(`{
+			"GREETING": {"title": "Halo", "body": "Hai {{.Name}}"}
+		}`)},
+	}
+
+	c, err := Load(fsys, "locales", "en")
+	assert.NoError(t, err)
+
+	return c
+}
+
+func TestCatalog_Render(t *testing.T) {
+	c := testCatalog(t)
+	greeting := struct {
+		Name string
+		VIP  bool
+	}{Name: "Ana", VIP: true}
+
+	tests := []struct {
+		name     string
+		locale   string
+		key      string
+		data     interface{}
+		expected Message
+		err      error
+	}{
+		{name: "default locale", locale: "en", key: "GREETING", data: greeting, expected: Message{Title: "Hello", Body: "Hi Ana, welcome back"}},
+		{name: "translated", locale: "id", key: "GREETING", data: greeting, expected: Message{Title: "Halo", Body: "Hai Ana"}},
+		{name: "untranslated message falls back", locale: "id", key: "RENEWED", data: struct{ Until time.Time }{time.Date(2026, 11, 19, 0, 0, 0, 0, time.UTC)}, expected: Message{Title: "Renewed", Body: "Active until 2026-11-19"}},
+		{name: "unknown locale falls back", locale: "fr", key: "GREETING", data: greeting, expected: Message{Title: "Hello", Body: "Hi Ana, welcome back"}},
+		{name: "unknown message", locale: "en", key: "MISSING", err: ErrUnknownMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := c.Render(tt.locale, tt.key, tt.data)
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err))
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, m)
+		})
+	}
+}
+
+func TestLoad_MissingDefaultLocale(t *testing.T) {
+	fsys := fstest.MapFS{"locales/id.json": {Data: []byte(`{}`)}}
+
+	_, err := Load(fsys, "locales", "en")
+	assert.Error(t, err)
+}
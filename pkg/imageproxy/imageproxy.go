@@ -0,0 +1,87 @@
+package imageproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MaxImageSize bounds what's read back from the proxy, teasers are tiny.
+const MaxImageSize = 1 << 20
+
+var (
+	ErrNoTemplate = errors.New("image proxy url template needs a {url} placeholder")
+	ErrTooLarge   = errors.New("proxied image is too large")
+)
+
+type (
+	// Image is a proxied image, the caller closes Body.
+	Image struct {
+		Body        io.ReadCloser
+		ContentType string
+		Size        int64
+	}
+
+	// IProxy fetches transformed copies of images through an image proxy, on the server's side so the
+	// original URL never reaches the client.
+	IProxy interface {
+		Fetch(ctx context.Context, imageURL string) (*Image, error)
+	}
+
+	Proxy struct {
+		template string
+		client   *http.Client
+	}
+)
+
+// NewProxy takes the proxy's URL for a transformed image, {url} is replaced with the escaped original.
+func NewProxy(template string) (IProxy, error) {
+	if !strings.Contains(template, "{url}") {
+		return nil, ErrNoTemplate
+	}
+
+	return &Proxy{template: template, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+func (p *Proxy) Fetch(ctx context.Context, imageURL string) (*Image, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(p.template, "{url}", url.QueryEscape(imageURL)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+
+		return nil, fmt.Errorf("image proxy responded with %d", res.StatusCode)
+	}
+
+	if res.ContentLength > MaxImageSize {
+		res.Body.Close()
+
+		return nil, ErrTooLarge
+	}
+
+	contentType := res.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "image/") {
+		res.Body.Close()
+
+		return nil, fmt.Errorf("image proxy responded with %q", contentType)
+	}
+
+	return &Image{Body: limitedBody{Reader: io.LimitReader(res.Body, MaxImageSize), Closer: res.Body}, ContentType: contentType, Size: res.ContentLength}, nil
+}
+
+type limitedBody struct {
+	io.Reader
+	io.Closer
+}
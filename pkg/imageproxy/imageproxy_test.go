@@ -0,0 +1,71 @@
+package imageproxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProxy(t *testing.T) {
+	_, err := NewProxy("")
+	assert.ErrorIs(t, err, ErrNoTemplate)
+
+	_, err = NewProxy("https://img.test/blur:20")
+	assert.ErrorIs(t, err, ErrNoTemplate)
+
+	_, err = NewProxy("https://img.test/blur:20/plain/{url}")
+	assert.NoError(t, err)
+}
+
+func TestProxy_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("src") {
+		case "https://cdn.test/user2.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte("blurred"))
+		case "https://cdn.test/page.html":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	proxy, err := NewProxy(server.URL + "/blur?src={url}")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		imageURL string
+		expected string
+		wantErr  bool
+	}{
+		{name: "proxied", imageURL: "https://cdn.test/user2.jpg", expected: "blurred"},
+		{name: "not an image", imageURL: "https://cdn.test/page.html", wantErr: true},
+		{name: "missing", imageURL: "https://cdn.test/missing.jpg", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image, err := proxy.Fetch(context.Background(), tt.imageURL)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			defer image.Body.Close()
+
+			body, err := io.ReadAll(image.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(body))
+			assert.Equal(t, "image/jpeg", image.ContentType)
+		})
+	}
+}
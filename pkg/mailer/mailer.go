@@ -0,0 +1,96 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type (
+	// Mail is a plain text email to a single recipient.
+	Mail struct {
+		To      string
+		Subject string
+		Body    string
+	}
+
+	IMailer interface {
+		Send(ctx context.Context, mail Mail) error
+	}
+
+	SMTPMailer struct {
+		addr string
+		from mail.Address
+		auth smtp.Auth
+	}
+
+	// Fake records what it's asked to send instead of sending it, for tests.
+	Fake struct {
+		mu   sync.Mutex
+		sent []Mail
+	}
+)
+
+func NewSMTPMailer(host string, port int, username, password, from string) (IMailer, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{addr: net.JoinHostPort(host, strconv.Itoa(port)), from: *sender, auth: auth}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Mail) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	return smtp.SendMail(m.addr, m.auth, m.from.Address, []string{to.Address}, buildMessage(m.from, *to, msg))
+}
+
+// buildMessage writes the headers and body. The subject is encoded, so it can't smuggle in headers of its own.
+func buildMessage(from, to mail.Address, msg Mail) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from.String() + "\r\n")
+	b.WriteString("To: " + to.String() + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return []byte(b.String())
+}
+
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) Send(ctx context.Context, mail Mail) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sent = append(f.sent, mail)
+
+	return nil
+}
+
+// Sent returns the mails delivered so far.
+func (f *Fake) Sent() []Mail {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Mail(nil), f.sent...)
+}
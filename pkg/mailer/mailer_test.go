@@ -0,0 +1,35 @@
+package mailer
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildMessage(t *testing.T) {
+	from := mail.Address{Name: "Jones", Address: "no-reply@example.com"}
+	to := mail.Address{Address: "ana@example.com"}
+
+	msg := string(buildMessage(from, to, Mail{Subject: "Payment failed\r\nBcc: someone@example.com", Body: "line one\nline two"}))
+	headers, body, _ := strings.Cut(msg, "\r\n\r\n")
+
+	assert.Contains(t, headers, `From: "Jones" <no-reply@example.com>`)
+	assert.Contains(t, headers, "To: <ana@example.com>")
+	assert.NotContains(t, headers, "\r\nBcc:")
+	assert.Equal(t, "line one\r\nline two", body)
+}
+
+func TestNewSMTPMailer_InvalidSender(t *testing.T) {
+	_, err := NewSMTPMailer("localhost", 587, "", "", "not an address")
+	assert.Error(t, err)
+}
+
+func TestFake(t *testing.T) {
+	fake := NewFake()
+
+	assert.NoError(t, fake.Send(context.Background(), Mail{To: "ana@example.com", Subject: "Hello"}))
+	assert.Equal(t, []Mail{{To: "ana@example.com", Subject: "Hello"}}, fake.Sent())
+}
@@ -0,0 +1,50 @@
+package moderation
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// linkPattern catches urls and bare domains, the usual way spam gets off the platform.
+var linkPattern = regexp.MustCompile(`(?i)(https?://|www\.)\S+|\b[a-z0-9-]+\.(com|net|org|io|me|ly|co)\b`)
+
+type (
+	Moderator struct {
+		blocked []*regexp.Regexp
+	}
+
+	IModerator interface {
+		// Allowed reports whether user written text can be shown to other users.
+		Allowed(ctx context.Context, text string) (bool, error)
+	}
+)
+
+func NewModerator(blockedTerms []string) IModerator {
+	m := &Moderator{}
+
+	for _, term := range blockedTerms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		m.blocked = append(m.blocked, regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(term)+`\b`))
+	}
+
+	return m
+}
+
+func (m *Moderator) Allowed(ctx context.Context, text string) (bool, error) {
+	if linkPattern.MatchString(text) {
+		return false, nil
+	}
+
+	for _, term := range m.blocked {
+		if term.MatchString(text) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
@@ -0,0 +1,34 @@
+package moderation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModerator_Allowed(t *testing.T) {
+	moderator := NewModerator([]string{"idiot", " ", "send nudes"})
+
+	tests := []struct {
+		name     string
+		text     string
+		expected bool
+	}{
+		{name: "clean", text: "Love this photo, where was it taken?", expected: true},
+		{name: "blocked term", text: "you IDIOT", expected: false},
+		{name: "blocked phrase", text: "hey send nudes", expected: false},
+		{name: "term inside another word", text: "idiotic hat lol", expected: true},
+		{name: "url", text: "follow me on https://example.com/me", expected: false},
+		{name: "bare domain", text: "check out mysite.io", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed, err := moderator.Allowed(context.Background(), tt.text)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, allowed)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	apnsEndpoint        = "https://api.push.apple.com"
+	apnsSandboxEndpoint = "https://api.sandbox.push.apple.com"
+
+	// apple refuses provider tokens older than an hour and throttles refreshing them more often than every 20 minutes
+	apnsTokenTTL = 50 * time.Minute
+)
+
+// APNsProvider sends to iOS devices through APNs, authenticated with a .p8 auth key.
+type APNsProvider struct {
+	keyID    string
+	teamID   string
+	topic    string
+	key      *ecdsa.PrivateKey
+	endpoint string
+	client   *http.Client
+
+	mu       sync.Mutex
+	jwt      string
+	issuedAt time.Time
+}
+
+// NewAPNsProvider takes the auth key downloaded from the developer account and the app's bundle id as the topic.
+func NewAPNsProvider(authKey []byte, keyID, teamID, topic string, sandbox bool) (IProvider, error) {
+	key, err := jwt.ParseECPrivateKeyFromPEM(authKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apns auth key: %w", err)
+	}
+
+	endpoint := apnsEndpoint
+	if sandbox {
+		endpoint = apnsSandboxEndpoint
+	}
+
+	return &APNsProvider{
+		keyID:    keyID,
+		teamID:   teamID,
+		topic:    topic,
+		key:      key,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (p *APNsProvider) Send(ctx context.Context, msg Message) error {
+	token, err := p.token()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{"title": msg.Title, "body": msg.Body},
+			"sound": "default",
+		},
+	}
+	for k, v := range msg.Data {
+		payload[k] = v
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/3/device/%s", p.endpoint, url.PathEscape(msg.Token)), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("apns-topic", p.topic)
+	req.Header.Set("apns-push-type", "alert")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var apnsErr struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(res.Body).Decode(&apnsErr)
+
+	// 410 is sent once the app is uninstalled
+	if res.StatusCode == http.StatusGone || apnsErr.Reason == "BadDeviceToken" || apnsErr.Reason == "DeviceTokenNotForTopic" {
+		return ErrInvalidToken
+	}
+
+	return fmt.Errorf("apns: %d %s", res.StatusCode, apnsErr.Reason)
+}
+
+func (p *APNsProvider) token() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.jwt != "" && now.Sub(p.issuedAt) < apnsTokenTTL {
+		return p.jwt, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": p.teamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = p.keyID
+
+	signed, err := token.SignedString(p.key)
+	if err != nil {
+		return "", err
+	}
+
+	p.jwt, p.issuedAt = signed, now
+
+	return p.jwt, nil
+}
@@ -0,0 +1,63 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrInvalidToken means the provider will never deliver to the token again, the device should be forgotten.
+var ErrInvalidToken = errors.New("push token is no longer valid")
+
+type (
+	Message struct {
+		Token string
+		Title string
+		Body  string
+		// Data is handed to the app alongside the alert
+		Data map[string]string
+	}
+
+	// IProvider sends a push notification to a single device.
+	IProvider interface {
+		Send(ctx context.Context, msg Message) error
+	}
+
+	// Fake records what it's asked to send instead of sending it, for tests.
+	Fake struct {
+		mu      sync.Mutex
+		sent    []Message
+		invalid map[string]bool
+	}
+)
+
+// NewFake returns a fake provider that rejects the given tokens as no longer valid.
+func NewFake(invalidTokens ...string) *Fake {
+	invalid := map[string]bool{}
+	for _, token := range invalidTokens {
+		invalid[token] = true
+	}
+
+	return &Fake{invalid: invalid}
+}
+
+func (f *Fake) Send(ctx context.Context, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.invalid[msg.Token] {
+		return ErrInvalidToken
+	}
+
+	f.sent = append(f.sent, msg)
+
+	return nil
+}
+
+// Sent returns the messages delivered so far.
+func (f *Fake) Sent() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Message(nil), f.sent...)
+}
@@ -0,0 +1,164 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFCMProvider_Send(t *testing.T) {
+	ctx := context.Background()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.PostForm.Get("grant_type"))
+
+			claims := jwt.MapClaims{}
+			_, err := jwt.ParseWithClaims(r.PostForm.Get("assertion"), claims, func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil })
+			assert.NoError(t, err)
+			assert.Equal(t, "push@project.iam.gserviceaccount.com", claims["iss"])
+
+			w.Write([]byte(`{"access_token":"access","expires_in":3600}`))
+		case "/v1/projects/project/messages:send":
+			assert.Equal(t, "Bearer access", r.Header.Get("Authorization"))
+
+			var body struct {
+				Message struct {
+					Token        string            `json:"token"`
+					Notification map[string]string `json:"notification"`
+					Data         map[string]string `json:"data"`
+				} `json:"message"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "Hello", body.Message.Notification["title"])
+			assert.Equal(t, "match1", body.Message.Data["match_id"])
+
+			switch body.Message.Token {
+			case "uninstalled":
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+			case "broken":
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error":{"status":"INTERNAL","message":"try again"}}`))
+			default:
+				w.Write([]byte(`{"name":"projects/project/messages/1"}`))
+			}
+		}
+	}))
+	defer server.Close()
+
+	credentials, _ := json.Marshal(map[string]string{
+		"project_id":   "project",
+		"client_email": "push@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"token_uri":    server.URL + "/token",
+	})
+	provider, err := NewFCMProvider(credentials)
+	assert.NoError(t, err)
+	provider.(*FCMProvider).endpoint = server.URL
+
+	msg := Message{Title: "Hello", Body: "World", Data: map[string]string{"match_id": "match1"}}
+
+	msg.Token = "device"
+	assert.NoError(t, provider.Send(ctx, msg))
+
+	msg.Token = "uninstalled"
+	assert.Equal(t, ErrInvalidToken, provider.Send(ctx, msg))
+
+	msg.Token = "broken"
+	err = provider.Send(ctx, msg)
+	assert.Error(t, err)
+	assert.NotEqual(t, ErrInvalidToken, err)
+
+	// the access token is reused until it expires
+	assert.Equal(t, 1, tokenRequests)
+}
+
+func TestAPNsProvider_Send(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "com.example.jones", r.Header.Get("apns-topic"))
+
+		token, err := jwt.Parse(strings.TrimPrefix(r.Header.Get("Authorization"), "bearer "), func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil })
+		assert.NoError(t, err)
+		assert.Equal(t, "KEY123", token.Header["kid"])
+
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "match1", body["match_id"])
+
+		switch strings.TrimPrefix(r.URL.Path, "/3/device/") {
+		case "uninstalled":
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{"reason":"Unregistered"}`))
+		case "malformed":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"reason":"BadDeviceToken"}`))
+		case "throttled":
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"reason":"TooManyRequests"}`))
+		}
+	}))
+	defer server.Close()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+	provider, err := NewAPNsProvider(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), "KEY123", "TEAM123", "com.example.jones", true)
+	assert.NoError(t, err)
+	provider.(*APNsProvider).endpoint = server.URL
+
+	tests := []struct {
+		token       string
+		expectedErr error
+		anyErr      bool
+	}{
+		{token: "device"},
+		{token: "uninstalled", expectedErr: ErrInvalidToken},
+		{token: "malformed", expectedErr: ErrInvalidToken},
+		{token: "throttled", anyErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			err := provider.Send(ctx, Message{Token: tt.token, Title: "Hello", Body: "World", Data: map[string]string{"match_id": "match1"}})
+
+			switch {
+			case tt.anyErr:
+				assert.Error(t, err)
+				assert.NotEqual(t, ErrInvalidToken, err)
+			default:
+				assert.Equal(t, tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestFake(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFake("uninstalled")
+
+	assert.NoError(t, fake.Send(ctx, Message{Token: "device", Title: "Hello"}))
+	assert.Equal(t, ErrInvalidToken, fake.Send(ctx, Message{Token: "uninstalled"}))
+	assert.Equal(t, []Message{{Token: "device", Title: "Hello"}}, fake.Sent())
+}
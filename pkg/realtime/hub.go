@@ -0,0 +1,88 @@
+package realtime
+
+import "sync"
+
+// ClientBufferSize is how many events can wait for a slow device before it's disconnected.
+const ClientBufferSize = 64
+
+type (
+	// Hub keeps the connections of this node, a user can be connected from several devices at once.
+	Hub struct {
+		mu      sync.RWMutex
+		clients map[string]map[*Client]struct{}
+	}
+
+	// Client is a single device connection, the transport drains Send until it's closed.
+	Client struct {
+		UserID string
+		Send   chan Event
+	}
+)
+
+func NewHub() *Hub {
+	return &Hub{clients: map[string]map[*Client]struct{}{}}
+}
+
+func NewClient(userID string) *Client {
+	return &Client{UserID: userID, Send: make(chan Event, ClientBufferSize)}
+}
+
+func (h *Hub) Register(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[c.UserID] == nil {
+		h.clients[c.UserID] = map[*Client]struct{}{}
+	}
+
+	h.clients[c.UserID][c] = struct{}{}
+}
+
+// Unregister closes the client's Send channel, it's safe to call more than once.
+func (h *Hub) Unregister(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(c)
+}
+
+// Deliver hands the event to every device of its user. A device that can't keep up is dropped rather than
+// holding back everyone else, it catches up through the REST endpoints when it reconnects.
+func (h *Hub) Deliver(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for c := range h.clients[event.UserID] {
+		select {
+		case c.Send <- event:
+		default:
+			h.remove(c)
+		}
+	}
+}
+
+// Connections returns how many devices the user has connected to this node.
+func (h *Hub) Connections(userID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.clients[userID])
+}
+
+func (h *Hub) remove(c *Client) {
+	devices, ok := h.clients[c.UserID]
+	if !ok {
+		return
+	}
+
+	if _, ok := devices[c]; !ok {
+		return
+	}
+
+	delete(devices, c)
+	close(c.Send)
+
+	if len(devices) == 0 {
+		delete(h.clients, c.UserID)
+	}
+}
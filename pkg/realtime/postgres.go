@@ -0,0 +1,94 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// PostgresChannel is the LISTEN/NOTIFY channel events travel on between nodes.
+const PostgresChannel = "realtime_events"
+
+type (
+	// PostgresBroker fans events out to every node with LISTEN/NOTIFY. Notifications are capped at 8000 bytes
+	// by postgres, events are kept small enough by carrying ids and short bodies only.
+	PostgresBroker struct {
+		MemoryBroker
+
+		db       *gorm.DB
+		listener *pq.Listener
+		done     chan struct{}
+	}
+
+	// envelope keeps the recipient with the event, it isn't part of what clients receive.
+	envelope struct {
+		UserID string `json:"user_id"`
+		Event  Event  `json:"event"`
+	}
+)
+
+func NewPostgresBroker(db *gorm.DB, dsn string) (IBroker, error) {
+	listener := pq.NewListener(dsn, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			logrus.WithField("event", ev).Errorln("realtime listener error", err)
+		}
+	})
+
+	err := listener.Listen(PostgresChannel)
+	if err != nil {
+		listener.Close()
+
+		return nil, err
+	}
+
+	b := &PostgresBroker{db: db, listener: listener, done: make(chan struct{})}
+	go b.listen()
+
+	return b, nil
+}
+
+func (b *PostgresBroker) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(envelope{UserID: event.UserID, Event: event})
+	if err != nil {
+		return err
+	}
+
+	return b.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", PostgresChannel, string(payload)).Error
+}
+
+func (b *PostgresBroker) Close() error {
+	close(b.done)
+
+	return b.listener.Close()
+}
+
+func (b *PostgresBroker) listen() {
+	for {
+		select {
+		case <-b.done:
+			return
+		case notification := <-b.listener.Notify:
+			// nil after the listener reconnects, whatever was sent meanwhile is lost
+			if notification == nil {
+				continue
+			}
+
+			var env envelope
+			err := json.Unmarshal([]byte(notification.Extra), &env)
+			if err != nil {
+				logrus.Errorln("failed to decode realtime event", err)
+
+				continue
+			}
+
+			env.Event.UserID = env.UserID
+			b.dispatch(env.Event)
+		case <-time.After(90 * time.Second):
+			go b.listener.Ping()
+		}
+	}
+}
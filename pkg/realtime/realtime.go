@@ -0,0 +1,82 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+const (
+	EventMessage = "message"
+	EventMatch   = "match"
+	EventTyping  = "typing"
+	EventRead    = "read"
+	EventPing    = "ping"
+	// EventNotification carries a stored notification, its ID is what streams resume from.
+	EventNotification = "notification"
+)
+
+type (
+	// Event is pushed to every device the user is connected with.
+	Event struct {
+		Type    string          `json:"type"`
+		UserID  string          `json:"-"`
+		Payload json.RawMessage `json:"payload,omitempty"`
+	}
+
+	IPublisher interface {
+		Publish(ctx context.Context, event Event) error
+	}
+
+	// IBroker fans events out to every node, each node hands them to its own connections.
+	IBroker interface {
+		IPublisher
+		Subscribe(handler func(Event))
+		Close() error
+	}
+
+	// MemoryBroker delivers events within a single node.
+	MemoryBroker struct {
+		mu       sync.RWMutex
+		handlers []func(Event)
+	}
+)
+
+func NewEvent(eventType, userID string, payload interface{}) (Event, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{Type: eventType, UserID: userID, Payload: b}, nil
+}
+
+func NewMemoryBroker() IBroker {
+	return &MemoryBroker{}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, event Event) error {
+	b.dispatch(event)
+
+	return nil
+}
+
+func (b *MemoryBroker) Subscribe(handler func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *MemoryBroker) Close() error {
+	return nil
+}
+
+func (b *MemoryBroker) dispatch(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,59 @@
+package realtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_Deliver(t *testing.T) {
+	hub := NewHub()
+	phone := NewClient("user1")
+	laptop := NewClient("user1")
+	other := NewClient("user2")
+	hub.Register(phone)
+	hub.Register(laptop)
+	hub.Register(other)
+
+	event, err := NewEvent(EventTyping, "user1", map[string]string{"match_id": "match1"})
+	assert.NoError(t, err)
+
+	hub.Deliver(event)
+
+	assert.Equal(t, event, <-phone.Send)
+	assert.Equal(t, event, <-laptop.Send)
+	assert.Empty(t, other.Send)
+}
+
+func TestHub_SlowClientIsDropped(t *testing.T) {
+	hub := NewHub()
+	slow := NewClient("user1")
+	hub.Register(slow)
+
+	for i := 0; i < ClientBufferSize+1; i++ {
+		hub.Deliver(Event{Type: EventPing, UserID: "user1"})
+	}
+
+	assert.Equal(t, 0, hub.Connections("user1"))
+
+	// the buffered events can still be drained before the channel reports closed
+	for range slow.Send {
+	}
+
+	// unregistering after being dropped doesn't close twice
+	hub.Unregister(slow)
+}
+
+func TestMemoryBroker_Publish(t *testing.T) {
+	broker := NewMemoryBroker()
+	hub := NewHub()
+	broker.Subscribe(hub.Deliver)
+
+	client := NewClient("user1")
+	hub.Register(client)
+
+	event := Event{Type: EventMatch, UserID: "user1"}
+	assert.NoError(t, broker.Publish(context.Background(), event))
+	assert.Equal(t, event, <-client.Send)
+}
@@ -0,0 +1,79 @@
+package signedurl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalid = errors.New("invalid signed url")
+	ErrExpired = errors.New("signed url has expired")
+
+	ErrEmptySecret = errors.New("signed url secret is empty")
+)
+
+type (
+	// Signer issues short-lived URLs for a single viewer, for files that are loaded where the client
+	// can't attach its access token, like an img tag or an audio player.
+	Signer struct {
+		secret  []byte
+		baseURL string
+		ttl     time.Duration
+	}
+
+	ISigner interface {
+		Sign(path, viewerID string, now time.Time) string
+		// Verify checks the query of a signed URL for the given path and returns who it was issued to.
+		Verify(path string, query url.Values, now time.Time) (viewerID string, err error)
+	}
+)
+
+func NewSigner(secret, baseURL string, ttl time.Duration) (ISigner, error) {
+	if secret == "" {
+		return nil, ErrEmptySecret
+	}
+
+	return &Signer{secret: []byte(secret), baseURL: baseURL, ttl: ttl}, nil
+}
+
+func (s *Signer) Sign(path, viewerID string, now time.Time) string {
+	expiresAt := strconv.FormatInt(now.Add(s.ttl).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("user", viewerID)
+	query.Set("expires", expiresAt)
+	query.Set("signature", s.signature(path, viewerID, expiresAt))
+
+	return s.baseURL + path + "?" + query.Encode()
+}
+
+func (s *Signer) Verify(path string, query url.Values, now time.Time) (string, error) {
+	viewerID, expiresAt := query.Get("user"), query.Get("expires")
+
+	expiry, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil || viewerID == "" {
+		return "", ErrInvalid
+	}
+
+	if !hmac.Equal([]byte(query.Get("signature")), []byte(s.signature(path, viewerID, expiresAt))) {
+		return "", ErrInvalid
+	}
+
+	if now.Unix() > expiry {
+		return "", ErrExpired
+	}
+
+	return viewerID, nil
+}
+
+func (s *Signer) signature(path, viewerID, expiresAt string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(path + "|" + viewerID + "|" + expiresAt))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,64 @@
+package signedurl
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner_Verify(t *testing.T) {
+	now := time.Now()
+	signer, err := NewSigner("secret", "https://api.example.com", 5*time.Minute)
+	assert.NoError(t, err)
+
+	signed := signer.Sign("/attachments/file1", "viewer", now)
+	assert.True(t, strings.HasPrefix(signed, "https://api.example.com/attachments/file1?"))
+
+	parsed, err := url.Parse(signed)
+	assert.NoError(t, err)
+	query := parsed.Query()
+
+	withUser := func(user string) url.Values {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Set("user", user)
+
+		return q
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		query    url.Values
+		now      time.Time
+		expected error
+	}{
+		{name: "valid", path: "/attachments/file1", query: query, now: now, expected: nil},
+		{name: "other file", path: "/attachments/file2", query: query, now: now, expected: ErrInvalid},
+		{name: "handed to someone else", path: "/attachments/file1", query: withUser("someone-else"), now: now, expected: ErrInvalid},
+		{name: "expired", path: "/attachments/file1", query: query, now: now.Add(10 * time.Minute), expected: ErrExpired},
+		{name: "missing signature", path: "/attachments/file1", query: url.Values{"user": {"viewer"}, "expires": query["expires"]}, now: now, expected: ErrInvalid},
+		{name: "malformed", path: "/attachments/file1", query: url.Values{}, now: now, expected: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viewerID, err := signer.Verify(tt.path, tt.query, tt.now)
+			assert.Equal(t, tt.expected, err)
+
+			if tt.expected == nil {
+				assert.Equal(t, "viewer", viewerID)
+			}
+		})
+	}
+}
+
+func TestNewSigner_EmptySecret(t *testing.T) {
+	_, err := NewSigner("", "https://api.example.com", 5*time.Minute)
+	assert.ErrorIs(t, err, ErrEmptySecret)
+}
@@ -20,6 +20,7 @@ type (
 		// CreatePaymentMethod(ctx context.Context, customerID string, cardNumber string, cardCVC string, cardExpMonth string, cardExpYear string) (string, error)
 		CreateCheckoutSession(ctx context.Context, customerID string, planID string) (*stripe.CheckoutSession, error)
 		CreateBillingPortalSession(ctx context.Context, customerID string) (*stripe.BillingPortalSession, error)
+		CreatePaymentCheckoutSession(ctx context.Context, customerID string, priceID string, quantity int64, metadata map[string]string) (*stripe.CheckoutSession, error)
 	}
 )
 
@@ -72,6 +73,31 @@ func (c *StripeClient) CreateCheckoutSession(ctx context.Context, customerID str
 	return session, err
 }
 
+func (c *StripeClient) CreatePaymentCheckoutSession(ctx context.Context, customerID string, priceID string, quantity int64, metadata map[string]string) (*stripe.CheckoutSession, error) {
+	params := &stripe.CheckoutSessionParams{
+		Customer:   stripe.String(customerID),
+		SuccessURL: stripe.String("https://example.com/success"),
+		CancelURL:  stripe.String("https://example.com/cancel"),
+		Mode:       stripe.String(string(stripe.CheckoutSessionModePayment)),
+		LineItems: []*stripe.CheckoutSessionLineItemParams{
+			{
+				Price:    stripe.String(priceID),
+				Quantity: stripe.Int64(quantity),
+			},
+		},
+		Metadata: metadata,
+	}
+
+	session, err := c.Client.CheckoutSessions.New(params)
+	if err != nil {
+		logger.Errorln(ctx, "failed to create payment checkout session", err)
+
+		return nil, err
+	}
+
+	return session, err
+}
+
 func (c *StripeClient) CreateBillingPortalSession(ctx context.Context, customerID string) (*stripe.BillingPortalSession, error) {
 	params := &stripe.BillingPortalSessionParams{
 		Customer: stripe.String(customerID),
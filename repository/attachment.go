@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/marvelalexius/jones/model"
+	"gorm.io/gorm"
+)
+
+type (
+	AttachmentRepository struct {
+		db *gorm.DB
+	}
+
+	IAttachmentRepository interface {
+		Create(ctx context.Context, attachment model.Attachment) error
+		FindByID(ctx context.Context, id string) (*model.Attachment, error)
+	}
+)
+
+func NewAttachmentRepository(db *gorm.DB) IAttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) Create(ctx context.Context, attachment model.Attachment) error {
+	return conn(ctx, r.db).Table("attachments").Create(&attachment).Error
+}
+
+// FindByID finds an attachment whose message wasn't deleted.
+func (r *AttachmentRepository) FindByID(ctx context.Context, id string) (*model.Attachment, error) {
+	var attachment model.Attachment
+
+	err := conn(ctx, r.db).Table("attachments").
+		Select("attachments.*").
+		Joins("JOIN messages ON messages.id = attachments.message_id AND messages.deleted_at IS NULL").
+		Where("attachments.id = ?", id).
+		First(&attachment).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
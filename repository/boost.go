@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/utils/logger"
+	"gorm.io/gorm"
+)
+
+type (
+	BoostRepository struct {
+		db *gorm.DB
+	}
+
+	IBoostRepository interface {
+		Create(ctx context.Context, boost model.Boost) error
+		FindActiveByUserID(ctx context.Context, userID string, now time.Time) (model.Boost, error)
+		FindByUserID(ctx context.Context, userID string, pagination model.PaginationRequest) (boosts []model.Boost, total int64, err error)
+		CountSince(ctx context.Context, userID string, since time.Time) (int64, error)
+		IncrementViews(ctx context.Context, userIDs []string, now time.Time) error
+		IncrementLikes(ctx context.Context, userID string, now time.Time) error
+		DecrementLikes(ctx context.Context, userID string, likedAt time.Time) error
+	}
+)
+
+func NewBoostRepository(db *gorm.DB) IBoostRepository {
+	return &BoostRepository{db: db}
+}
+
+func (r *BoostRepository) Create(ctx context.Context, boost model.Boost) error {
+	return conn(ctx, r.db).Table("boosts").Create(&boost).Error
+}
+
+func (r *BoostRepository) FindActiveByUserID(ctx context.Context, userID string, now time.Time) (boost model.Boost, err error) {
+	err = conn(ctx, r.db).Table("boosts").
+		Where("user_id = ?", userID).
+		Where("started_at <= ? AND ends_at > ?", now, now).
+		First(&boost).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		logger.Errorln(ctx, "failed to find active boost", err)
+
+		return boost, err
+	}
+
+	return boost, nil
+}
+
+func (r *BoostRepository) FindByUserID(ctx context.Context, userID string, pagination model.PaginationRequest) (boosts []model.Boost, total int64, err error) {
+	q := conn(ctx, r.db).Table("boosts").Where("user_id = ?", userID)
+
+	err = q.Count(&total).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to count boosts", err)
+
+		return boosts, total, err
+	}
+
+	err = q.Order("started_at DESC").Offset(pagination.Offset()).Limit(pagination.Limit).Find(&boosts).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find boosts", err)
+
+		return boosts, total, err
+	}
+
+	return boosts, total, nil
+}
+
+func (r *BoostRepository) CountSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	var count int64
+
+	err := conn(ctx, r.db).Table("boosts").Where("user_id = ?", userID).Where("started_at >= ?", since).Count(&count).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to count boosts", err)
+
+		return count, err
+	}
+
+	return count, nil
+}
+
+func (r *BoostRepository) IncrementViews(ctx context.Context, userIDs []string, now time.Time) error {
+	return conn(ctx, r.db).Table("boosts").
+		Where("user_id IN ?", userIDs).
+		Where("started_at <= ? AND ends_at > ?", now, now).
+		Updates(map[string]interface{}{"views": gorm.Expr("views + 1"), "updated_at": now}).Error
+}
+
+func (r *BoostRepository) IncrementLikes(ctx context.Context, userID string, now time.Time) error {
+	return conn(ctx, r.db).Table("boosts").
+		Where("user_id = ?", userID).
+		Where("started_at <= ? AND ends_at > ?", now, now).
+		Updates(map[string]interface{}{"likes": gorm.Expr("likes + 1"), "updated_at": now}).Error
+}
+
+// DecrementLikes takes back a like from the boost that was active when it was given.
+func (r *BoostRepository) DecrementLikes(ctx context.Context, userID string, likedAt time.Time) error {
+	return conn(ctx, r.db).Table("boosts").
+		Where("user_id = ?", userID).
+		Where("started_at <= ? AND ends_at > ?", likedAt, likedAt).
+		Where("likes > 0").
+		Updates(map[string]interface{}{"likes": gorm.Expr("likes - 1"), "updated_at": time.Now()}).Error
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/utils/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	ConversationRepository struct {
+		db *gorm.DB
+	}
+
+	IConversationRepository interface {
+		Create(ctx context.Context, conversation model.Conversation) (bool, error)
+		FindByMatchID(ctx context.Context, matchID string) (*model.Conversation, error)
+		FindByUserID(ctx context.Context, userID string, pagination model.PaginationRequest) (conversations []model.Conversation, total int64, err error)
+		Touch(ctx context.Context, id string, lastMessageAt time.Time) error
+		MarkRead(ctx context.Context, conversation *model.Conversation, userID string, readAt time.Time) (bool, error)
+		DeleteByMatchID(ctx context.Context, matchID string) error
+	}
+)
+
+func NewConversationRepository(db *gorm.DB) IConversationRepository {
+	return &ConversationRepository{db: db}
+}
+
+// Create opens the conversation unless the match already has one, it reports whether a row was inserted.
+func (r *ConversationRepository) Create(ctx context.Context, conversation model.Conversation) (bool, error) {
+	res := conn(ctx, r.db).Table("conversations").Clauses(clause.OnConflict{DoNothing: true}).Create(&conversation)
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+func (r *ConversationRepository) FindByMatchID(ctx context.Context, matchID string) (*model.Conversation, error) {
+	var conversation model.Conversation
+
+	err := conn(ctx, r.db).Table("conversations").Where("match_id = ?", matchID).First(&conversation).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &conversation, nil
+}
+
+// FindByUserID returns the conversations of the user's active matches, most recently active first.
+func (r *ConversationRepository) FindByUserID(ctx context.Context, userID string, pagination model.PaginationRequest) (conversations []model.Conversation, total int64, err error) {
+	q := conn(ctx, r.db).Table("conversations").
+		Joins("JOIN matches m ON m.id = conversations.match_id").
+		Where("(conversations.user_id = ? OR conversations.matched_user_id = ?)", userID, userID).
+		Where("m.status = ?", model.MatchStatusActive)
+
+	err = q.Count(&total).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to count conversations", err)
+
+		return conversations, total, err
+	}
+
+	err = q.Select("conversations.*").
+		Order("conversations.last_message_at DESC NULLS LAST, conversations.created_at DESC").
+		Offset(pagination.Offset()).
+		Limit(pagination.Limit).
+		Preload("User.Images", "is_primary = ?", true).
+		Preload("MatchedUser.Images", "is_primary = ?", true).
+		Find(&conversations).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find conversations", err)
+
+		return conversations, total, err
+	}
+
+	return conversations, total, nil
+}
+
+func (r *ConversationRepository) Touch(ctx context.Context, id string, lastMessageAt time.Time) error {
+	return conn(ctx, r.db).Table("conversations").Where("id = ?", id).Updates(map[string]interface{}{"last_message_at": lastMessageAt, "updated_at": time.Now()}).Error
+}
+
+// MarkRead moves the user's read marker forward, it never goes back. It reports false when there was nothing
+// new to read.
+func (r *ConversationRepository) MarkRead(ctx context.Context, conversation *model.Conversation, userID string, readAt time.Time) (bool, error) {
+	column := "matched_user_last_read_at"
+	if conversation.UserID == userID {
+		column = "user_last_read_at"
+	}
+
+	res := conn(ctx, r.db).Table("conversations").
+		Where("id = ?", conversation.ID).
+		Where("("+column+" IS NULL OR "+column+" < last_message_at)").
+		Where("("+column+" IS NULL OR "+column+" < ?)", readAt).
+		Update(column, readAt)
+
+	return res.RowsAffected > 0, res.Error
+}
+
+// DeleteByMatchID removes the match's conversation, its messages and their attachments are removed with it.
+func (r *ConversationRepository) DeleteByMatchID(ctx context.Context, matchID string) error {
+	return conn(ctx, r.db).Table("conversations").Where("match_id = ?", matchID).Delete(&model.Conversation{}).Error
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/utils/logger"
+	"gorm.io/gorm"
+)
+
+type (
+	CreditRepository struct {
+		db *gorm.DB
+	}
+
+	ICreditRepository interface {
+		Create(ctx context.Context, credit model.Credit) error
+		Balance(ctx context.Context, userID, creditType string) (int, error)
+		Balances(ctx context.Context, userID string) (map[string]int, error)
+		FindByReferenceID(ctx context.Context, userID, referenceID string) ([]model.Credit, error)
+		LockUser(ctx context.Context, userID string) error
+	}
+)
+
+func NewCreditRepository(db *gorm.DB) ICreditRepository {
+	return &CreditRepository{db: db}
+}
+
+func (r *CreditRepository) Create(ctx context.Context, credit model.Credit) error {
+	return conn(ctx, r.db).Table("credits").Create(&credit).Error
+}
+
+func (r *CreditRepository) Balance(ctx context.Context, userID, creditType string) (int, error) {
+	var balance int
+
+	err := conn(ctx, r.db).Table("credits").Select("COALESCE(SUM(amount), 0)").Where("user_id = ?", userID).Where("type = ?", creditType).Scan(&balance).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find credit balance", err)
+
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+func (r *CreditRepository) Balances(ctx context.Context, userID string) (map[string]int, error) {
+	var rows []struct {
+		Type    string
+		Balance int
+	}
+
+	err := conn(ctx, r.db).Table("credits").Select("type, COALESCE(SUM(amount), 0) AS balance").Where("user_id = ?", userID).Group("type").Scan(&rows).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find credit balances", err)
+
+		return nil, err
+	}
+
+	balances := map[string]int{}
+	for _, row := range rows {
+		balances[row.Type] = row.Balance
+	}
+
+	return balances, nil
+}
+
+func (r *CreditRepository) FindByReferenceID(ctx context.Context, userID, referenceID string) ([]model.Credit, error) {
+	var credits []model.Credit
+
+	err := conn(ctx, r.db).Table("credits").Where("user_id = ?", userID).Where("reference_id = ?", referenceID).Order("created_at").Find(&credits).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return credits, nil
+}
+
+// LockUser serializes credit consumption of a user until the surrounding transaction ends,
+// so concurrent requests can't spend the same credit twice.
+func (r *CreditRepository) LockUser(ctx context.Context, userID string) error {
+	return conn(ctx, r.db).Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "credits:"+userID).Error
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"gorm.io/gorm"
+)
+
+type (
+	DeferredNotificationRepository struct {
+		db *gorm.DB
+	}
+
+	IDeferredNotificationRepository interface {
+		Create(ctx context.Context, deferred model.DeferredNotification) error
+		FindDue(ctx context.Context, afterID string, now time.Time, limit int) ([]model.DeferredNotification, error)
+		Delete(ctx context.Context, id string) (bool, error)
+		DeleteByReferenceID(ctx context.Context, referenceID string) error
+		DeleteByReferenceIDs(ctx context.Context, referenceIDs []string) error
+	}
+)
+
+func NewDeferredNotificationRepository(db *gorm.DB) IDeferredNotificationRepository {
+	return &DeferredNotificationRepository{db: db}
+}
+
+func (r *DeferredNotificationRepository) Create(ctx context.Context, deferred model.DeferredNotification) error {
+	return conn(ctx, r.db).Table("deferred_notifications").Create(&deferred).Error
+}
+
+// FindDue returns the notifications whose quiet hours are over, in batches.
+func (r *DeferredNotificationRepository) FindDue(ctx context.Context, afterID string, now time.Time, limit int) ([]model.DeferredNotification, error) {
+	var deferred []model.DeferredNotification
+
+	err := conn(ctx, r.db).Table("deferred_notifications").
+		Where("deliver_at <= ?", now).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&deferred).Error
+
+	return deferred, err
+}
+
+// Delete claims a deferred notification for delivery, reporting false when someone else already has.
+func (r *DeferredNotificationRepository) Delete(ctx context.Context, id string) (bool, error) {
+	res := conn(ctx, r.db).Table("deferred_notifications").Where("id = ?", id).Delete(&model.DeferredNotification{})
+
+	return res.RowsAffected > 0, res.Error
+}
+
+// DeleteByReferenceID drops what quiet hours are holding back about something that's been undone.
+func (r *DeferredNotificationRepository) DeleteByReferenceID(ctx context.Context, referenceID string) error {
+	return conn(ctx, r.db).Table("deferred_notifications").Where("reference_id = ?", referenceID).Delete(&model.DeferredNotification{}).Error
+}
+
+// DeleteByReferenceIDs is DeleteByReferenceID for several references at once.
+func (r *DeferredNotificationRepository) DeleteByReferenceIDs(ctx context.Context, referenceIDs []string) error {
+	return conn(ctx, r.db).Table("deferred_notifications").Where("reference_id IN ?", referenceIDs).Delete(&model.DeferredNotification{}).Error
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/marvelalexius/jones/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	DeviceRepository struct {
+		db *gorm.DB
+	}
+
+	IDeviceRepository interface {
+		Upsert(ctx context.Context, device *model.Device) error
+		FindByUserID(ctx context.Context, userID string) ([]model.Device, error)
+		Delete(ctx context.Context, userID, token string) (bool, error)
+		DeleteByToken(ctx context.Context, token string) error
+	}
+)
+
+func NewDeviceRepository(db *gorm.DB) IDeviceRepository {
+	return &DeviceRepository{db: db}
+}
+
+// Upsert registers the token, moving it over when it was registered by someone else who signed in on the same device.
+// The device is filled in with the stored row, which keeps its ID when the token was already known.
+func (r *DeviceRepository) Upsert(ctx context.Context, device *model.Device) error {
+	return conn(ctx, r.db).Table("devices").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "updated_at"}),
+	}, clause.Returning{}).Create(device).Error
+}
+
+func (r *DeviceRepository) FindByUserID(ctx context.Context, userID string) ([]model.Device, error) {
+	var devices []model.Device
+	err := conn(ctx, r.db).Table("devices").Where("user_id = ?", userID).Find(&devices).Error
+
+	return devices, err
+}
+
+// Delete unregisters one of the user's devices, reporting false when the user has no such token.
+func (r *DeviceRepository) Delete(ctx context.Context, userID, token string) (bool, error) {
+	res := conn(ctx, r.db).Table("devices").Where("user_id = ? AND token = ?", userID, token).Delete(&model.Device{})
+
+	return res.RowsAffected > 0, res.Error
+}
+
+// DeleteByToken forgets a token the push provider no longer accepts.
+func (r *DeviceRepository) DeleteByToken(ctx context.Context, token string) error {
+	return conn(ctx, r.db).Table("devices").Where("token = ?", token).Delete(&model.Device{}).Error
+}
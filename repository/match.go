@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/utils/logger"
+	"gorm.io/gorm"
+)
+
+type (
+	MatchRepository struct {
+		db *gorm.DB
+	}
+
+	IMatchRepository interface {
+		Create(ctx context.Context, match model.Match) error
+		FindByID(ctx context.Context, id string) (*model.Match, error)
+		FindByUserID(ctx context.Context, userID string, pagination model.PaginationRequest) (matches []model.Match, total int64, err error)
+		FindUnmatchedUserIDs(ctx context.Context, userID string) ([]string, error)
+		Update(ctx context.Context, match *model.Match) error
+		FindByPair(ctx context.Context, userID, matchedUserID string) (*model.Match, error)
+		Delete(ctx context.Context, id string) error
+		MarkFirstMessage(ctx context.Context, id string, sentAt time.Time) (bool, error)
+		FindExpiring(ctx context.Context, afterID string, now time.Time, limit int) ([]model.Match, error)
+		Expire(ctx context.Context, id string, now time.Time) (bool, error)
+		Extend(ctx context.Context, match *model.Match, now time.Time) (bool, error)
+	}
+)
+
+func NewMatchRepository(db *gorm.DB) IMatchRepository {
+	return &MatchRepository{db: db}
+}
+
+func (r *MatchRepository) Create(ctx context.Context, match model.Match) error {
+	return conn(ctx, r.db).Table("matches").Create(&match).Error
+}
+
+func (r *MatchRepository) FindByID(ctx context.Context, id string) (*model.Match, error) {
+	var match model.Match
+
+	err := conn(ctx, r.db).Table("matches").
+		Where("id = ?", id).
+		Preload("User.Images", "is_primary = ?", true).
+		Preload("MatchedUser.Images", "is_primary = ?", true).
+		First(&match).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &match, nil
+}
+
+func (r *MatchRepository) FindByUserID(ctx context.Context, userID string, pagination model.PaginationRequest) (matches []model.Match, total int64, err error) {
+	q := conn(ctx, r.db).Table("matches").
+		Where("(user_id = ? OR matched_user_id = ?)", userID, userID).
+		Where("status = ?", model.MatchStatusActive)
+
+	err = q.Count(&total).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to count matches", err)
+
+		return matches, total, err
+	}
+
+	err = q.Order("matched_at desc").
+		Offset(pagination.Offset()).
+		Limit(pagination.Limit).
+		Preload("User.Images", "is_primary = ?", true).
+		Preload("MatchedUser.Images", "is_primary = ?", true).
+		Find(&matches).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find matches", err)
+
+		return matches, total, err
+	}
+
+	return matches, total, nil
+}
+
+// FindUnmatchedUserIDs returns everyone the user has unmatched with, or who unmatched them.
+func (r *MatchRepository) FindUnmatchedUserIDs(ctx context.Context, userID string) ([]string, error) {
+	var userIDs []string
+
+	err := conn(ctx, r.db).Table("matches").
+		Select("CASE WHEN user_id = ? THEN matched_user_id ELSE user_id END", userID).
+		Where("(user_id = ? OR matched_user_id = ?)", userID, userID).
+		Where("status = ?", model.MatchStatusUnmatched).
+		Scan(&userIDs).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find unmatched users", err)
+
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+func (r *MatchRepository) Update(ctx context.Context, match *model.Match) error {
+	return conn(ctx, r.db).Table("matches").Where("id = ?", match.ID).Updates(&match).Error
+}
+
+func (r *MatchRepository) FindByPair(ctx context.Context, userID, matchedUserID string) (*model.Match, error) {
+	if matchedUserID < userID {
+		userID, matchedUserID = matchedUserID, userID
+	}
+
+	var match model.Match
+
+	err := conn(ctx, r.db).Table("matches").Where("user_id = ?", userID).Where("matched_user_id = ?", matchedUserID).First(&match).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &match, nil
+}
+
+func (r *MatchRepository) Delete(ctx context.Context, id string) error {
+	return conn(ctx, r.db).Table("matches").Where("id = ?", id).Delete(&model.Match{}).Error
+}
+
+// MarkFirstMessage records the match's first message and stops its expiry. It reports false when the
+// match isn't active anymore, an expired match can't be revived by a late message.
+func (r *MatchRepository) MarkFirstMessage(ctx context.Context, id string, sentAt time.Time) (bool, error) {
+	res := conn(ctx, r.db).Table("matches").
+		Where("id = ? AND status = ?", id, model.MatchStatusActive).
+		Where("first_message_at IS NULL").
+		Updates(map[string]interface{}{"first_message_at": sentAt, "expires_at": nil, "updated_at": sentAt})
+
+	return res.RowsAffected > 0, res.Error
+}
+
+// FindExpiring returns the active matches whose window for a first message has passed, in batches.
+func (r *MatchRepository) FindExpiring(ctx context.Context, afterID string, now time.Time, limit int) ([]model.Match, error) {
+	var matches []model.Match
+
+	err := conn(ctx, r.db).Table("matches").
+		Where("status = ? AND first_message_at IS NULL", model.MatchStatusActive).
+		Where("expires_at <= ?", now).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&matches).Error
+
+	return matches, err
+}
+
+// Expire ends the match unless a message made it in since it was found.
+func (r *MatchRepository) Expire(ctx context.Context, id string, now time.Time) (bool, error) {
+	res := conn(ctx, r.db).Table("matches").
+		Where("id = ? AND status = ? AND first_message_at IS NULL", id, model.MatchStatusActive).
+		Where("expires_at <= ?", now).
+		Updates(map[string]interface{}{"status": model.MatchStatusExpired, "updated_at": now})
+
+	return res.RowsAffected > 0, res.Error
+}
+
+// Extend saves the match's longer window unless it was extended, written to or expired since it was found.
+func (r *MatchRepository) Extend(ctx context.Context, match *model.Match, now time.Time) (bool, error) {
+	res := conn(ctx, r.db).Table("matches").
+		Where("id = ? AND status = ? AND first_message_at IS NULL", match.ID, model.MatchStatusActive).
+		Where("extended_at IS NULL AND expires_at > ?", now).
+		Updates(map[string]interface{}{"expires_at": match.ExpiresAt, "extended_at": match.ExtendedAt, "updated_at": match.UpdatedAt})
+
+	return res.RowsAffected > 0, res.Error
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/utils/logger"
+	"gorm.io/gorm"
+)
+
+type (
+	MessageRepository struct {
+		db *gorm.DB
+	}
+
+	IMessageRepository interface {
+		Create(ctx context.Context, message model.Message) error
+		FindByID(ctx context.Context, id string) (*model.Message, error)
+		FindByConversationID(ctx context.Context, conversationID string, pagination model.PaginationRequest) (messages []model.Message, total int64, err error)
+		FindLatest(ctx context.Context, conversationIDs []string) (map[string]model.Message, error)
+		CountUnread(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error)
+		Delete(ctx context.Context, id string, deletedAt time.Time) error
+		FindIDsByMatchID(ctx context.Context, matchID string) ([]string, error)
+	}
+)
+
+func NewMessageRepository(db *gorm.DB) IMessageRepository {
+	return &MessageRepository{db: db}
+}
+
+func (r *MessageRepository) Create(ctx context.Context, message model.Message) error {
+	return conn(ctx, r.db).Table("messages").Create(&message).Error
+}
+
+func (r *MessageRepository) FindByID(ctx context.Context, id string) (*model.Message, error) {
+	var message model.Message
+
+	err := conn(ctx, r.db).Table("messages").Where("id = ?", id).Where("deleted_at IS NULL").First(&message).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// FindByConversationID returns the conversation history, newest first.
+func (r *MessageRepository) FindByConversationID(ctx context.Context, conversationID string, pagination model.PaginationRequest) (messages []model.Message, total int64, err error) {
+	q := conn(ctx, r.db).Table("messages").Where("conversation_id = ?", conversationID).Where("deleted_at IS NULL")
+
+	err = q.Count(&total).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to count messages", err)
+
+		return messages, total, err
+	}
+
+	err = q.Preload("Attachments").Order("created_at DESC, id DESC").Offset(pagination.Offset()).Limit(pagination.Limit).Find(&messages).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find messages", err)
+
+		return messages, total, err
+	}
+
+	return messages, total, nil
+}
+
+// FindLatest returns the last message of each conversation, keyed by conversation.
+func (r *MessageRepository) FindLatest(ctx context.Context, conversationIDs []string) (map[string]model.Message, error) {
+	var messages []model.Message
+
+	err := conn(ctx, r.db).Table("messages").
+		Select("DISTINCT ON (conversation_id) *").
+		Preload("Attachments").
+		Where("conversation_id IN ?", conversationIDs).
+		Where("deleted_at IS NULL").
+		Order("conversation_id, created_at DESC, id DESC").
+		Find(&messages).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find latest messages", err)
+
+		return nil, err
+	}
+
+	latest := make(map[string]model.Message, len(messages))
+	for _, message := range messages {
+		latest[message.ConversationID] = message
+	}
+
+	return latest, nil
+}
+
+// CountUnread counts the messages the other participant sent after the user's read marker, keyed by conversation.
+func (r *MessageRepository) CountUnread(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error) {
+	var rows []struct {
+		ConversationID string
+		Unread         int64
+	}
+
+	err := conn(ctx, r.db).Table("messages").
+		Select("messages.conversation_id, COUNT(*) AS unread").
+		Joins("JOIN conversations c ON c.id = messages.conversation_id").
+		Where("messages.conversation_id IN ?", conversationIDs).
+		Where("messages.sender_id <> ?", userID).
+		Where("messages.deleted_at IS NULL").
+		Where("messages.created_at > COALESCE(CASE WHEN c.user_id = ? THEN c.user_last_read_at ELSE c.matched_user_last_read_at END, '-infinity')", userID).
+		Group("messages.conversation_id").
+		Scan(&rows).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to count unread messages", err)
+
+		return nil, err
+	}
+
+	unread := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		unread[row.ConversationID] = row.Unread
+	}
+
+	return unread, nil
+}
+
+func (r *MessageRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	return conn(ctx, r.db).Table("messages").Where("id = ?", id).Updates(map[string]interface{}{"deleted_at": deletedAt, "updated_at": deletedAt}).Error
+}
+
+// FindIDsByMatchID returns the IDs of every message sent in the match, deleted ones included.
+func (r *MessageRepository) FindIDsByMatchID(ctx context.Context, matchID string) ([]string, error) {
+	var ids []string
+
+	err := conn(ctx, r.db).Table("messages").
+		Joins("JOIN conversations ON conversations.id = messages.conversation_id").
+		Where("conversations.match_id = ?", matchID).
+		Pluck("messages.id", &ids).Error
+
+	return ids, err
+}
@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/marvelalexius/jones/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type (
@@ -11,7 +15,15 @@ type (
 	}
 
 	INotificationRepository interface {
-		Create(notif model.Notification) error
+		Create(notif *model.Notification) (bool, error)
+		DeleteByReferenceID(ctx context.Context, referenceID string) error
+		DeleteByReferenceIDs(ctx context.Context, referenceIDs []string) error
+		FindAfter(ctx context.Context, userID string, afterSeq int64, limit int) ([]model.Notification, error)
+		FindByUserID(ctx context.Context, userID, notificationType string, pagination model.PaginationRequest) (notifications []model.Notification, total int64, err error)
+		CountUnread(ctx context.Context, userID string) (int64, error)
+		MarkRead(ctx context.Context, userID, id string, readAt time.Time) (bool, error)
+		MarkAllRead(ctx context.Context, userID string, readAt time.Time) (int64, error)
+		Delete(ctx context.Context, userID, id string, deletedAt time.Time) (bool, error)
 	}
 )
 
@@ -19,6 +31,86 @@ func NewNotificationRepository(db *gorm.DB) INotificationRepository {
 	return &NotificationRepository{db: db}
 }
 
-func (r *NotificationRepository) Create(notif model.Notification) error {
-	return r.db.Table("notifications").Create(&notif).Error
+// Create stores the notification and fills in the sequence the database gave it. It reports false when a
+// notification with the same ID is already stored.
+func (r *NotificationRepository) Create(notif *model.Notification) (bool, error) {
+	res := r.db.Table("notifications").Clauses(clause.OnConflict{DoNothing: true}).Create(notif)
+
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *NotificationRepository) DeleteByReferenceID(ctx context.Context, referenceID string) error {
+	return conn(ctx, r.db).Table("notifications").Where("reference_id = ?", referenceID).Delete(&model.Notification{}).Error
+}
+
+// DeleteByReferenceIDs is DeleteByReferenceID for several references at once.
+func (r *NotificationRepository) DeleteByReferenceIDs(ctx context.Context, referenceIDs []string) error {
+	return conn(ctx, r.db).Table("notifications").Where("reference_id IN ?", referenceIDs).Delete(&model.Notification{}).Error
+}
+
+// FindAfter returns the user's notifications stored after the given sequence, oldest first.
+func (r *NotificationRepository) FindAfter(ctx context.Context, userID string, afterSeq int64, limit int) ([]model.Notification, error) {
+	var notifications []model.Notification
+	err := conn(ctx, r.db).Table("notifications").
+		Where("user_id = ? AND seq > ? AND deleted_at IS NULL", userID, afterSeq).
+		Order("seq").
+		Limit(limit).
+		Find(&notifications).Error
+
+	return notifications, err
+}
+
+// FindByUserID returns the user's inbox, newest first. An empty type returns every type.
+func (r *NotificationRepository) FindByUserID(ctx context.Context, userID, notificationType string, pagination model.PaginationRequest) (notifications []model.Notification, total int64, err error) {
+	q := conn(ctx, r.db).Table("notifications").Where("user_id = ? AND deleted_at IS NULL", userID)
+
+	if notificationType != "" {
+		q = q.Where("type = ?", notificationType)
+	}
+
+	err = q.Count(&total).Error
+	if err != nil {
+		return notifications, total, err
+	}
+
+	err = q.Order("id DESC").Offset(pagination.Offset()).Limit(pagination.Limit).Find(&notifications).Error
+
+	return notifications, total, err
+}
+
+// CountUnread counts the user's unread notifications, rows from before is_read was always set count as unread.
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	var total int64
+	err := conn(ctx, r.db).Table("notifications").
+		Where("user_id = ? AND deleted_at IS NULL AND is_read IS NOT TRUE", userID).
+		Count(&total).Error
+
+	return total, err
+}
+
+// MarkRead reports false when the user has no such notification. Marking a read notification again is fine.
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id string, readAt time.Time) (bool, error) {
+	res := conn(ctx, r.db).Table("notifications").
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", id, userID).
+		Updates(map[string]interface{}{"is_read": true, "updated_at": readAt})
+
+	return res.RowsAffected > 0, res.Error
+}
+
+// MarkAllRead returns how many notifications were unread.
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID string, readAt time.Time) (int64, error) {
+	res := conn(ctx, r.db).Table("notifications").
+		Where("user_id = ? AND deleted_at IS NULL AND is_read IS NOT TRUE", userID).
+		Updates(map[string]interface{}{"is_read": true, "updated_at": readAt})
+
+	return res.RowsAffected, res.Error
+}
+
+// Delete hides a notification from the inbox and the stream replay, reporting false when the user has no such notification.
+func (r *NotificationRepository) Delete(ctx context.Context, userID, id string, deletedAt time.Time) (bool, error) {
+	res := conn(ctx, r.db).Table("notifications").
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", id, userID).
+		Updates(map[string]interface{}{"deleted_at": deletedAt, "updated_at": deletedAt})
+
+	return res.RowsAffected > 0, res.Error
 }
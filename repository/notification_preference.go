@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/marvelalexius/jones/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	NotificationPreferenceRepository struct {
+		db *gorm.DB
+	}
+
+	INotificationPreferenceRepository interface {
+		FindByUserID(ctx context.Context, userID string) ([]model.NotificationPreference, error)
+		Upsert(ctx context.Context, preferences []model.NotificationPreference) error
+	}
+)
+
+func NewNotificationPreferenceRepository(db *gorm.DB) INotificationPreferenceRepository {
+	return &NotificationPreferenceRepository{db: db}
+}
+
+// FindByUserID returns the preferences the user changed, the rest are still on.
+func (r *NotificationPreferenceRepository) FindByUserID(ctx context.Context, userID string) ([]model.NotificationPreference, error) {
+	var preferences []model.NotificationPreference
+	err := conn(ctx, r.db).Table("notification_preferences").Where("user_id = ?", userID).Find(&preferences).Error
+
+	return preferences, err
+}
+
+func (r *NotificationPreferenceRepository) Upsert(ctx context.Context, preferences []model.NotificationPreference) error {
+	return conn(ctx, r.db).Table("notification_preferences").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}, {Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&preferences).Error
+}
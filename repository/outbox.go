@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type (
+	OutboxRepository struct {
+		db *gorm.DB
+	}
+
+	IOutboxRepository interface {
+		Create(ctx context.Context, message model.OutboxMessage) error
+		Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]model.OutboxMessage, error)
+		Renew(ctx context.Context, id string, leasedUntil, leaseUntil time.Time) (bool, error)
+		Delete(ctx context.Context, id string) error
+		DeleteByReferenceID(ctx context.Context, referenceID string) error
+		DeleteByReferenceIDs(ctx context.Context, referenceIDs []string) error
+		Retry(ctx context.Context, id string, attempts int, lastError string, availableAt time.Time) error
+		CreateDeadLetter(ctx context.Context, deadLetter model.DeadLetter) error
+	}
+)
+
+func NewOutboxRepository(db *gorm.DB) IOutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Create writes the message with the transaction bound to ctx, so it's only sent once that commits.
+func (r *OutboxRepository) Create(ctx context.Context, message model.OutboxMessage) error {
+	return conn(ctx, r.db).Table("outbox").Create(&message).Error
+}
+
+// Claim leases the messages that are due to the caller. Concurrent workers skip each other's rows, and a message
+// whose worker died comes back once the lease runs out.
+func (r *OutboxRepository) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]model.OutboxMessage, error) {
+	var messages []model.OutboxMessage
+
+	due := conn(ctx, r.db).Table("outbox").Select("id").
+		Where("available_at <= ?", now).
+		Order("available_at, id").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	err := conn(ctx, r.db).Table("outbox").Model(&messages).
+		Clauses(clause.Returning{}).
+		Where("id IN (?)", due).
+		Update("available_at", leaseUntil).Error
+
+	return messages, err
+}
+
+// Renew extends the lease on a claimed message as long as it still holds the lease the caller took, leasedUntil.
+// It reports false when another worker claimed the message after the lease ran out, or already sent it.
+func (r *OutboxRepository) Renew(ctx context.Context, id string, leasedUntil, leaseUntil time.Time) (bool, error) {
+	res := conn(ctx, r.db).Table("outbox").Where("id = ?", id).Where("available_at = ?", leasedUntil).Update("available_at", leaseUntil)
+
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *OutboxRepository) Delete(ctx context.Context, id string) error {
+	return conn(ctx, r.db).Table("outbox").Where("id = ?", id).Delete(&model.OutboxMessage{}).Error
+}
+
+// DeleteByReferenceID drops what's still waiting to be sent about something that's been undone.
+func (r *OutboxRepository) DeleteByReferenceID(ctx context.Context, referenceID string) error {
+	return conn(ctx, r.db).Table("outbox").Where("reference_id = ?", referenceID).Delete(&model.OutboxMessage{}).Error
+}
+
+// DeleteByReferenceIDs is DeleteByReferenceID for several references at once.
+func (r *OutboxRepository) DeleteByReferenceIDs(ctx context.Context, referenceIDs []string) error {
+	return conn(ctx, r.db).Table("outbox").Where("reference_id IN ?", referenceIDs).Delete(&model.OutboxMessage{}).Error
+}
+
+func (r *OutboxRepository) Retry(ctx context.Context, id string, attempts int, lastError string, availableAt time.Time) error {
+	return conn(ctx, r.db).Table("outbox").Where("id = ?", id).
+		Updates(map[string]interface{}{"attempts": attempts, "last_error": lastError, "available_at": availableAt}).Error
+}
+
+func (r *OutboxRepository) CreateDeadLetter(ctx context.Context, deadLetter model.DeadLetter) error {
+	return conn(ctx, r.db).Table("outbox_dead_letters").Create(&deadLetter).Error
+}
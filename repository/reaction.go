@@ -2,12 +2,12 @@ package repository
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	"github.com/marvelalexius/jones/model"
 	"github.com/marvelalexius/jones/utils/logger"
-	"github.com/marvelalexius/jones/utils/str"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type (
@@ -17,12 +17,25 @@ type (
 
 	IReactionRepository interface {
 		FindLikes(ctx context.Context, userID string) (reactions []model.Reaction, err error)
+		CountLikes(ctx context.Context, userID string) (int64, error)
+		FindLatestLikes(ctx context.Context, userID string, limit int) (reactions []model.Reaction, err error)
+		FindPendingLike(ctx context.Context, userID, id string) (model.Reaction, error)
 		FindMatch(ctx context.Context, userID, matchedUserID string) (model.Reaction, error)
 		HasSwiped(ctx context.Context, userID, matchedUserID string) (reactions model.Reaction, err error)
-		FindSwiped(ctx context.Context, userID string) (reactions []model.Reaction, err error)
-		FindSwipeCount(ctx context.Context, userID string) (int64, error)
+		FindSwiped(ctx context.Context, userID string, filter model.SentReactionFilter, pagination model.PaginationRequest) (reactions []model.Reaction, total int64, err error)
+		FindSwipedUserIDs(ctx context.Context, userID string, passCutoff time.Time) ([]string, error)
+		FindSwipeCount(ctx context.Context, userID string, since time.Time) (int64, error)
+		CountByTypeSince(ctx context.Context, userID, reactionType string, since time.Time) (int64, error)
 		Create(ctx context.Context, reaction model.Reaction) error
 		Update(ctx context.Context, reaction *model.Reaction) error
+		LockPair(ctx context.Context, userID, matchedUserID string) error
+		LockUser(ctx context.Context, userID string) error
+		FindLatest(ctx context.Context, userID string) (model.Reaction, error)
+		CountRewoundSince(ctx context.Context, userID string, since time.Time) (int64, error)
+		ResetMatch(ctx context.Context, id string) error
+		Delete(ctx context.Context, id string, deletedAt time.Time) error
+		Replace(ctx context.Context, id, replacedBy string, replacedAt time.Time) error
+		FindComments(ctx context.Context, userID, matchedUserID string) ([]model.Reaction, error)
 	}
 )
 
@@ -30,19 +43,65 @@ func NewReactionRepository(db *gorm.DB) IReactionRepository {
 	return &ReactionRepository{db: db}
 }
 
-func (r *ReactionRepository) FindSwiped(ctx context.Context, userID string) (reactions []model.Reaction, err error) {
-	err = r.db.Table("reactions").Where("user_id = ?", userID).Find(&reactions).Error
+// FindSwiped returns the swipes the user made, newest first. Rewound swipes and passes that were swiped again are left out.
+func (r *ReactionRepository) FindSwiped(ctx context.Context, userID string, filter model.SentReactionFilter, pagination model.PaginationRequest) (reactions []model.Reaction, total int64, err error) {
+	q := conn(ctx, r.db).Table("reactions").Where("user_id = ?", userID).Where("deleted_at IS NULL")
+
+	if filter.Type != "" {
+		q = q.Where("type = ?", filter.Type)
+	}
+
+	if filter.Since != nil {
+		q = q.Where("created_at >= ?", *filter.Since)
+	}
+
+	if filter.Until != nil {
+		q = q.Where("created_at < ?", *filter.Until)
+	}
+
+	err = q.Count(&total).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to count swiped", err)
+
+		return reactions, total, err
+	}
+
+	err = q.Order("created_at DESC").
+		Offset(pagination.Offset()).
+		Limit(pagination.Limit).
+		Preload("MatchedUser.Images", "is_primary = ?", true).
+		Find(&reactions).Error
 	if err != nil {
 		logger.Errorln(ctx, "failed to find swiped", err)
 
-		return reactions, err
+		return reactions, total, err
 	}
 
-	return reactions, nil
+	return reactions, total, nil
+}
+
+// FindSwipedUserIDs returns everyone who should stay out of the user's deck: every like, and the passes made
+// since the cutoff. Older passes have cooled down and can be shown again.
+func (r *ReactionRepository) FindSwipedUserIDs(ctx context.Context, userID string, passCutoff time.Time) ([]string, error) {
+	var userIDs []string
+
+	err := conn(ctx, r.db).Table("reactions").
+		Select("matched_user_id").
+		Where("user_id = ?", userID).
+		Where("deleted_at IS NULL").
+		Where("(type IN ? OR created_at >= ?)", model.LikeReactions, passCutoff).
+		Scan(&userIDs).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find swiped users", err)
+
+		return nil, err
+	}
+
+	return userIDs, nil
 }
 
 func (r *ReactionRepository) FindMatch(ctx context.Context, userID, matchedUserID string) (reactions model.Reaction, err error) {
-	err = r.db.Table("reactions").Where("user_id = ?", userID).Where("matched_user_id = ?", matchedUserID).Where("type = ?", model.ReactionLike).First(&reactions).Error
+	err = conn(ctx, r.db).Table("reactions").Where("user_id = ?", userID).Where("matched_user_id = ?", matchedUserID).Where("type IN ?", model.LikeReactions).Where("deleted_at IS NULL").First(&reactions).Error
 	if err != nil && err != gorm.ErrRecordNotFound {
 		logger.Errorln(ctx, "failed to find match", err)
 
@@ -52,8 +111,19 @@ func (r *ReactionRepository) FindMatch(ctx context.Context, userID, matchedUserI
 	return reactions, nil
 }
 
+// pendingLikes selects the likes the user hasn't answered yet, super likes first.
+func (r *ReactionRepository) pendingLikes(ctx context.Context, userID string) *gorm.DB {
+	return conn(ctx, r.db).Table("reactions").
+		Where("matched_user_id = ?", userID).
+		Where("type IN ?", model.LikeReactions).
+		Where("deleted_at IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM matches m WHERE (m.user_id = reactions.user_id AND m.matched_user_id = reactions.matched_user_id) OR (m.user_id = reactions.matched_user_id AND m.matched_user_id = reactions.user_id))")
+}
+
 func (r *ReactionRepository) FindLikes(ctx context.Context, userID string) (reactions []model.Reaction, err error) {
-	err = r.db.Table("reactions").Where("matched_user_id = ?", userID).Where("matched_at is null").Where("type = ?", model.ReactionLike).Find(&reactions).Error
+	err = r.pendingLikes(ctx, userID).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "CASE WHEN type = ? THEN 0 ELSE 1 END, created_at DESC", Vars: []interface{}{model.ReactionSuperLike}, WithoutParentheses: true}}).
+		Find(&reactions).Error
 	if err != nil && err != gorm.ErrRecordNotFound {
 		logger.Errorln(ctx, "failed to find match", err)
 
@@ -63,8 +133,50 @@ func (r *ReactionRepository) FindLikes(ctx context.Context, userID string) (reac
 	return reactions, nil
 }
 
+func (r *ReactionRepository) CountLikes(ctx context.Context, userID string) (int64, error) {
+	var count int64
+
+	err := r.pendingLikes(ctx, userID).Count(&count).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to count likes", err)
+
+		return count, err
+	}
+
+	return count, nil
+}
+
+// FindLatestLikes returns the most recent pending likes with the liker's primary photo.
+func (r *ReactionRepository) FindLatestLikes(ctx context.Context, userID string, limit int) (reactions []model.Reaction, err error) {
+	err = r.pendingLikes(ctx, userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Preload("User.Images", "is_primary = ?", true).
+		Find(&reactions).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find latest likes", err)
+
+		return reactions, err
+	}
+
+	return reactions, nil
+}
+
+// FindPendingLike finds one of the user's pending likes with the liker's primary photo.
+func (r *ReactionRepository) FindPendingLike(ctx context.Context, userID, id string) (reaction model.Reaction, err error) {
+	err = r.pendingLikes(ctx, userID).
+		Where("id = ?", id).
+		Preload("User.Images", "is_primary = ?", true).
+		First(&reaction).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		logger.Errorln(ctx, "failed to find pending like", err)
+	}
+
+	return reaction, err
+}
+
 func (r *ReactionRepository) HasSwiped(ctx context.Context, userID, matchedUserID string) (reactions model.Reaction, err error) {
-	err = r.db.Table("reactions").Where("user_id = ?", userID).Where("matched_user_id = ?", matchedUserID).Where("type = ?", model.ReactionLike).First(&reactions).Error
+	err = conn(ctx, r.db).Table("reactions").Where("user_id = ?", userID).Where("matched_user_id = ?", matchedUserID).Where("deleted_at IS NULL").First(&reactions).Error
 	if err != nil && err != gorm.ErrRecordNotFound {
 		logger.Errorln(ctx, "failed to find match", err)
 
@@ -74,12 +186,11 @@ func (r *ReactionRepository) HasSwiped(ctx context.Context, userID, matchedUserI
 	return reactions, nil
 }
 
-func (r *ReactionRepository) FindSwipeCount(ctx context.Context, userID string) (int64, error) {
+// FindSwipeCount counts the swipes made since the given time, rewound ones included so undoing a swipe doesn't give it back.
+func (r *ReactionRepository) FindSwipeCount(ctx context.Context, userID string, since time.Time) (int64, error) {
 	var count int64
 
-	nowEarliest, nowLatest := str.GetTodayTimeRange()
-	fmt.Println(nowEarliest, nowLatest)
-	err := r.db.Table("reactions").Where("user_id = ?", userID).Where("created_at between ? and ?", nowEarliest, nowLatest).Count(&count).Error
+	err := conn(ctx, r.db).Table("reactions").Where("user_id = ?", userID).Where("created_at >= ?", since).Count(&count).Error
 	if err != nil {
 		logger.Errorln(ctx, "failed to find swipe count", err)
 
@@ -89,10 +200,96 @@ func (r *ReactionRepository) FindSwipeCount(ctx context.Context, userID string)
 	return count, nil
 }
 
+func (r *ReactionRepository) CountByTypeSince(ctx context.Context, userID, reactionType string, since time.Time) (int64, error) {
+	var count int64
+
+	err := conn(ctx, r.db).Table("reactions").Where("user_id = ?", userID).Where("type = ?", reactionType).Where("created_at >= ?", since).Count(&count).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to count reactions", err)
+
+		return count, err
+	}
+
+	return count, nil
+}
+
 func (r *ReactionRepository) Create(ctx context.Context, reaction model.Reaction) error {
-	return r.db.Table("reactions").Create(&reaction).Error
+	return conn(ctx, r.db).Table("reactions").Create(&reaction).Error
 }
 
 func (r *ReactionRepository) Update(ctx context.Context, reaction *model.Reaction) error {
-	return r.db.Table("reactions").Where("id = ?", reaction.ID).Updates(&reaction).Error
+	return conn(ctx, r.db).Table("reactions").Where("id = ?", reaction.ID).Updates(&reaction).Error
+}
+
+// LockPair serializes swipes between two users until the surrounding transaction ends,
+// so two users liking each other at the same time can't both miss the match.
+func (r *ReactionRepository) LockPair(ctx context.Context, userID, matchedUserID string) error {
+	key := userID + ":" + matchedUserID
+	if matchedUserID < userID {
+		key = matchedUserID + ":" + userID
+	}
+
+	return conn(ctx, r.db).Exec("SELECT pg_advisory_xact_lock(hashtext(?))", key).Error
+}
+
+// LockUser serializes swipes of a user until the surrounding transaction ends,
+// so concurrent swipes can't all be let through on the last one of the daily limit.
+func (r *ReactionRepository) LockUser(ctx context.Context, userID string) error {
+	return conn(ctx, r.db).Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "reactions:"+userID).Error
+}
+
+func (r *ReactionRepository) FindLatest(ctx context.Context, userID string) (reaction model.Reaction, err error) {
+	err = conn(ctx, r.db).Table("reactions").Where("user_id = ?", userID).Where("deleted_at IS NULL").Order("created_at DESC").First(&reaction).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		logger.Errorln(ctx, "failed to find latest reaction", err)
+
+		return reaction, err
+	}
+
+	return reaction, nil
+}
+
+func (r *ReactionRepository) CountRewoundSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	var count int64
+
+	err := conn(ctx, r.db).Table("reactions").Where("user_id = ?", userID).Where("deleted_at >= ?", since).Where("replaced_by IS NULL").Count(&count).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to count rewound reactions", err)
+
+		return count, err
+	}
+
+	return count, nil
+}
+
+// ResetMatch clears matched_at, Update can't since it skips zero values.
+func (r *ReactionRepository) ResetMatch(ctx context.Context, id string) error {
+	return conn(ctx, r.db).Table("reactions").Where("id = ?", id).Updates(map[string]interface{}{"matched_at": nil, "updated_at": time.Now()}).Error
+}
+
+func (r *ReactionRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	return conn(ctx, r.db).Table("reactions").Where("id = ?", id).Update("deleted_at", deletedAt).Error
+}
+
+// Replace retires a resurfaced pass in favour of the new swipe on the same profile.
+func (r *ReactionRepository) Replace(ctx context.Context, id, replacedBy string, replacedAt time.Time) error {
+	return conn(ctx, r.db).Table("reactions").Where("id = ?", id).Updates(map[string]interface{}{"deleted_at": replacedAt, "replaced_by": replacedBy}).Error
+}
+
+// FindComments returns the likes with a comment or a target that the pair left each other, oldest first.
+func (r *ReactionRepository) FindComments(ctx context.Context, userID, matchedUserID string) (reactions []model.Reaction, err error) {
+	err = conn(ctx, r.db).Table("reactions").
+		Where("((user_id = ? AND matched_user_id = ?) OR (user_id = ? AND matched_user_id = ?))", userID, matchedUserID, matchedUserID, userID).
+		Where("type IN ?", model.LikeReactions).
+		Where("(comment IS NOT NULL OR target_type IS NOT NULL)").
+		Where("deleted_at IS NULL").
+		Order("created_at ASC").
+		Find(&reactions).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find comments", err)
+
+		return reactions, err
+	}
+
+	return reactions, nil
 }
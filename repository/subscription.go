@@ -25,6 +25,7 @@ type (
 		FindAllPlan(ctx context.Context) ([]model.SubscriptionPlan, error)
 		FindPlanByProductID(ctx context.Context, id string) (*model.SubscriptionPlan, error)
 		FindPlanByID(ctx context.Context, id int) (*model.SubscriptionPlan, error)
+		FindPlanByName(ctx context.Context, name string) (*model.SubscriptionPlan, error)
 		FindAll(ctx context.Context) ([]model.Subscription, error)
 		FindByUserID(ctx context.Context, userID string) (*model.Subscription, error)
 		BulkCreatePlan(subsPlan []model.SubscriptionPlan) error
@@ -73,6 +74,16 @@ func (r *SubscriptionRepository) FindPlanByProductID(ctx context.Context, id str
 	return &subscriptionPlan, nil
 }
 
+func (r *SubscriptionRepository) FindPlanByName(ctx context.Context, name string) (*model.SubscriptionPlan, error) {
+	var subscriptionPlan model.SubscriptionPlan
+
+	if err := r.db.Where("name = ?", name).First(&subscriptionPlan).Error; err != nil {
+		return nil, err
+	}
+
+	return &subscriptionPlan, nil
+}
+
 func (r *SubscriptionRepository) FindPlanByID(ctx context.Context, id int) (*model.SubscriptionPlan, error) {
 	var subscriptionPlan model.SubscriptionPlan
 
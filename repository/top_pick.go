@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/utils/logger"
+	"gorm.io/gorm"
+)
+
+type (
+	TopPickRepository struct {
+		db *gorm.DB
+	}
+
+	ITopPickRepository interface {
+		FindActiveByUserID(ctx context.Context, userID string, now time.Time) ([]model.TopPick, error)
+		HasActive(ctx context.Context, userID string, now time.Time) (bool, error)
+		BulkCreate(ctx context.Context, picks []model.TopPick) error
+		DeleteExpired(ctx context.Context, before time.Time) error
+	}
+)
+
+func NewTopPickRepository(db *gorm.DB) ITopPickRepository {
+	return &TopPickRepository{db: db}
+}
+
+// FindActiveByUserID returns today's picks of the user, leaving out those they've swiped or unmatched with since.
+func (r *TopPickRepository) FindActiveByUserID(ctx context.Context, userID string, now time.Time) (picks []model.TopPick, err error) {
+	err = r.db.Table("top_picks").
+		Where("user_id = ?", userID).
+		Where("expired_at > ?", now).
+		Where("NOT EXISTS (SELECT 1 FROM reactions r WHERE r.user_id = top_picks.user_id AND r.matched_user_id = top_picks.picked_user_id AND r.deleted_at IS NULL)").
+		Where("NOT EXISTS (SELECT 1 FROM matches m WHERE m.status = ? AND ((m.user_id = top_picks.user_id AND m.matched_user_id = top_picks.picked_user_id) OR (m.user_id = top_picks.picked_user_id AND m.matched_user_id = top_picks.user_id)))", model.MatchStatusUnmatched).
+		Order("score desc").
+		Preload("PickedUser.Images").
+		Find(&picks).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find top picks", err)
+
+		return picks, err
+	}
+
+	return picks, nil
+}
+
+// HasActive reports whether the user got today's picks already, even if they went through all of them.
+func (r *TopPickRepository) HasActive(ctx context.Context, userID string, now time.Time) (bool, error) {
+	var count int64
+
+	err := r.db.Table("top_picks").Where("user_id = ?", userID).Where("expired_at > ?", now).Count(&count).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to check active top picks", err)
+
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *TopPickRepository) BulkCreate(ctx context.Context, picks []model.TopPick) error {
+	return r.db.Table("top_picks").Create(&picks).Error
+}
+
+func (r *TopPickRepository) DeleteExpired(ctx context.Context, before time.Time) error {
+	return r.db.Table("top_picks").Where("expired_at <= ?", before).Delete(&model.TopPick{}).Error
+}
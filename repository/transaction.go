@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type (
+	txKey struct{}
+
+	Transactor struct {
+		db *gorm.DB
+	}
+
+	ITransactor interface {
+		WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	}
+)
+
+func NewTransactor(db *gorm.DB) ITransactor {
+	return &Transactor{db: db}
+}
+
+// WithTransaction runs fn inside a database transaction. Repositories called with the ctx given to fn
+// share the same transaction, which is committed when fn returns nil and rolled back otherwise.
+// Nested calls join the outer transaction.
+func (t *Transactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction bound to ctx, or db when there is none.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx
+	}
+
+	return db
+}
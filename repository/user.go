@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/marvelalexius/jones/model"
 	"github.com/marvelalexius/jones/utils/logger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type (
@@ -14,12 +16,17 @@ type (
 	}
 
 	IUserRepository interface {
-		FindAll(ctx context.Context, userIds []string, preference string) (users []model.User, total int64, err error)
+		CountDeck(ctx context.Context, userIds []string, preference string, now time.Time) (total, boosted int64, err error)
+		FindDeck(ctx context.Context, viewerID string, userIds []string, preference string, boosted bool, now time.Time, offset, limit int) ([]model.User, error)
 		FindByID(ctx context.Context, id string) (*model.User, error)
 		FindByEmail(ctx context.Context, email string) (*model.User, error)
 		FindByStripeCustomerID(ctx context.Context, id string) (*model.User, error)
+		FindBatch(ctx context.Context, afterID string, limit int) ([]model.User, error)
+		FindTopPickCandidates(ctx context.Context, user model.User, excludeIDs []string, limit int) ([]model.User, error)
+		HasImage(ctx context.Context, userID string, imageID int) (bool, error)
 		Create(user *model.User) error
 		Update(user *model.User) (*model.User, error)
+		UpdateQuietHours(ctx context.Context, id, start, end string, now time.Time) error
 	}
 )
 
@@ -27,34 +34,63 @@ func NewUserRepository(db *gorm.DB) IUserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) FindAll(ctx context.Context, userIds []string, preference string) (users []model.User, total int64, err error) {
+// activeBoost matches users whose boost is running at the given time, passed twice.
+const activeBoost = "EXISTS (SELECT 1 FROM boosts b WHERE b.user_id = users.id AND b.started_at <= ? AND b.ends_at > ?)"
+
+// deck is everyone a viewer with the given preference may see, leaving out the given users.
+func (r *UserRepository) deck(userIds []string, preference string) *gorm.DB {
 	q := r.db.Table("users").Not("id in (?)", userIds)
 
-	if val, ok := model.SupportedPreference[preference]; ok {
-		if val == "BOTH" {
-			q = q.Where("(gender = ? OR gender = ?)", model.GenderMale, model.GenderFemale)
-		} else {
-			q = q.Where("gender = ?", val)
-		}
+	if genders := model.PreferredGenders(preference); genders != nil {
+		q = q.Where("gender IN ?", genders)
 	}
 
-	err = q.Count(&total).Error
+	return q
+}
+
+// CountDeck counts everyone in the deck and how many of them are boosted right now.
+func (r *UserRepository) CountDeck(ctx context.Context, userIds []string, preference string, now time.Time) (total, boosted int64, err error) {
+	err = r.deck(userIds, preference).
+		Select("COUNT(*), COUNT(*) FILTER (WHERE "+activeBoost+")", now, now).
+		Row().Scan(&total, &boosted)
 	if err != nil {
 		logger.Errorln(ctx, "failed to count users", err)
 
-		return users, total, err
+		return 0, 0, err
 	}
 
-	q = q.Order("created_at desc")
+	return total, boosted, nil
+}
 
-	err = q.Preload("Images").Find(&users).Error
+// FindDeck returns a slice of either the boosted users of the deck, longest running boost first, or everyone
+// else in deck order. Only primary images are loaded, that's all a deck card shows.
+func (r *UserRepository) FindDeck(ctx context.Context, viewerID string, userIds []string, preference string, boosted bool, now time.Time, offset, limit int) ([]model.User, error) {
+	var users []model.User
+
+	q := r.deck(userIds, preference)
+	if boosted {
+		q = q.Where(activeBoost, now, now).Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "(SELECT MIN(b.started_at) FROM boosts b WHERE b.user_id = users.id AND b.started_at <= ? AND b.ends_at > ?), id",
+			Vars:               []interface{}{now, now},
+			WithoutParentheses: true,
+		}})
+	} else {
+		// people who super liked the viewer are surfaced at the top of the deck, passes that cooled down go last
+		q = q.Where("NOT "+activeBoost, now, now).Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "EXISTS (SELECT 1 FROM reactions r WHERE r.user_id = users.id AND r.matched_user_id = ? AND r.type = ? AND r.deleted_at IS NULL) DESC, EXISTS (SELECT 1 FROM reactions p WHERE p.user_id = ? AND p.matched_user_id = users.id AND p.deleted_at IS NULL) ASC, created_at DESC, id",
+			Vars:               []interface{}{viewerID, model.ReactionSuperLike, viewerID},
+			WithoutParentheses: true,
+		}})
+	}
+
+	err := q.Preload("Images", "is_primary = ?", true).Offset(offset).Limit(limit).Find(&users).Error
 	if err != nil {
 		logger.Errorln(ctx, "failed to find users", err)
 
-		return users, total, err
+		return nil, err
 	}
 
-	return users, total, nil
+	return users, nil
 }
 
 func (r *UserRepository) FindByID(ctx context.Context, id string) (*model.User, error) {
@@ -87,6 +123,59 @@ func (r *UserRepository) FindByStripeCustomerID(ctx context.Context, id string)
 	return &user, nil
 }
 
+func (r *UserRepository) FindBatch(ctx context.Context, afterID string, limit int) ([]model.User, error) {
+	var users []model.User
+
+	if err := r.db.Model(&model.User{}).Where("id > ?", afterID).Order("id asc").Limit(limit).Find(&users).Error; err != nil {
+		logger.Errorln(ctx, "failed to find users batch", err)
+
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// FindTopPickCandidates returns up to limit profiles the user and they would like to see each other, closest in age
+// first. Images aren't loaded, only their count which is all a pick is scored on.
+func (r *UserRepository) FindTopPickCandidates(ctx context.Context, user model.User, excludeIDs []string, limit int) ([]model.User, error) {
+	var users []model.User
+
+	q := r.db.Table("users").
+		Select("users.*, (SELECT COUNT(*) FROM images WHERE images.user_id = users.id AND images.deleted_at IS NULL) AS image_count").
+		Not("id in (?)", excludeIDs).
+		Where("preference IN ?", []string{model.PreferenceBoth, user.Gender})
+
+	if genders := model.PreferredGenders(user.Preference); genders != nil {
+		q = q.Where("gender IN ?", genders)
+	}
+
+	err := q.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:                "ABS(age - ?), created_at DESC",
+		Vars:               []interface{}{user.Age},
+		WithoutParentheses: true,
+	}}).Limit(limit).Find(&users).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find top pick candidates", err)
+
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) HasImage(ctx context.Context, userID string, imageID int) (bool, error) {
+	var count int64
+
+	err := r.db.Table("images").Where("id = ?", imageID).Where("user_id = ?", userID).Where("deleted_at IS NULL").Count(&count).Error
+	if err != nil {
+		logger.Errorln(ctx, "failed to find image", err)
+
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
 func (r *UserRepository) Create(user *model.User) error {
 	if err := r.db.Create(&user).Error; err != nil {
 		return err
@@ -102,3 +191,9 @@ func (r *UserRepository) Update(user *model.User) (*model.User, error) {
 
 	return user, nil
 }
+
+// UpdateQuietHours sets the user's quiet hours, empty ones clear them which Update can't do.
+func (r *UserRepository) UpdateQuietHours(ctx context.Context, id, start, end string, now time.Time) error {
+	return conn(ctx, r.db).Model(&model.User{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"quiet_hours_start": start, "quiet_hours_end": end, "updated_at": now}).Error
+}
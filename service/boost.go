@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+type (
+	BoostService struct {
+		Transactor   repository.ITransactor
+		BoostRepo    repository.IBoostRepository
+		CreditRepo   repository.ICreditRepository
+		QuotaService IQuotaService
+	}
+
+	IBoostService interface {
+		Activate(ctx context.Context, userID string) (model.BoostResponse, error)
+		FindAll(ctx context.Context, userID string, pagination model.PaginationRequest) ([]model.BoostResponse, int64, error)
+	}
+)
+
+func NewBoostService(transactor repository.ITransactor, boostRepo repository.IBoostRepository, creditRepo repository.ICreditRepository, quotaService IQuotaService) IBoostService {
+	return &BoostService{Transactor: transactor, BoostRepo: boostRepo, CreditRepo: creditRepo, QuotaService: quotaService}
+}
+
+// Activate starts a boost, paid from the plan's weekly boosts first and from boost credits after that.
+func (s *BoostService) Activate(ctx context.Context, userID string) (model.BoostResponse, error) {
+	plan, err := s.QuotaService.FindPlan(ctx, userID)
+	if err != nil {
+		return model.BoostResponse{}, err
+	}
+
+	now := time.Now()
+	boost := model.NewBoost(userID, now)
+
+	err = s.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		// serializes activations so the quota and credits can't be spent twice
+		err := s.CreditRepo.LockUser(ctx, userID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to lock credits", err)
+
+			return errors.New("failed to activate boost")
+		}
+
+		active, err := s.BoostRepo.FindActiveByUserID(ctx, userID, now)
+		if err != nil {
+			logger.Errorln(ctx, "failed to find active boost", err)
+
+			return errors.New("failed to find active boost")
+		}
+
+		if active.ID != "" {
+			return ErrBoostActive
+		}
+
+		quota, err := s.QuotaService.Check(ctx, userID, plan, model.QuotaBoost)
+		if err != nil {
+			return err
+		}
+
+		if quota.Exceeded() {
+			balance, err := s.CreditRepo.Balance(ctx, userID, model.CreditBoost)
+			if err != nil {
+				logger.Errorln(ctx, "failed to find boost balance", err)
+
+				return errors.New("failed to check boost credits")
+			}
+
+			if balance < 1 {
+				return ErrNoBoostsLeft
+			}
+
+			err = s.CreditRepo.Create(ctx, model.NewCredit(userID, model.CreditBoost, -1, model.CreditReasonConsume, boost.ID))
+			if err != nil {
+				logger.Errorln(ctx, "failed to consume boost credit", err)
+
+				return errors.New("failed to use boost credit")
+			}
+		}
+
+		err = s.BoostRepo.Create(ctx, boost)
+		if err != nil {
+			logger.Errorln(ctx, "failed to create boost", err)
+
+			return errors.New("failed to activate boost")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.BoostResponse{}, err
+	}
+
+	return boost.ToBoostResponse(now), nil
+}
+
+func (s *BoostService) FindAll(ctx context.Context, userID string, pagination model.PaginationRequest) ([]model.BoostResponse, int64, error) {
+	pagination.Normalize()
+
+	boosts, total, err := s.BoostRepo.FindByUserID(ctx, userID, pagination)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find boosts", err)
+
+		return nil, 0, errors.New("failed to find boosts")
+	}
+
+	now := time.Now()
+	responses := make([]model.BoostResponse, 0, len(boosts))
+	for _, boost := range boosts {
+		responses = append(responses, boost.ToBoostResponse(now))
+	}
+
+	return responses, total, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marvelalexius/jones/mocks"
+	"github.com/marvelalexius/jones/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBoostService_Activate(t *testing.T) {
+	plan := &model.SubscriptionPlan{ID: 2, Name: model.SubscriptionPlanPro, WeeklyBoostQuota: 1}
+	limit := 1
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IBoostRepository, *mocks.ICreditRepository, *mocks.IQuotaService)
+		expectedError error
+	}{
+		{
+			name: "Success - Within Weekly Quota",
+			setupMocks: func(br *mocks.IBoostRepository, cr *mocks.ICreditRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(plan, nil)
+				cr.On("LockUser", mock.Anything, "user1").Return(nil)
+				br.On("FindActiveByUserID", mock.Anything, "user1", mock.Anything).Return(model.Boost{}, nil)
+				qs.On("Check", mock.Anything, "user1", plan, model.QuotaBoost).Return(model.NewQuota(model.QuotaBoost, &limit, 0, time.Now()), nil)
+				br.On("Create", mock.Anything, mock.MatchedBy(func(b model.Boost) bool {
+					return b.UserID == "user1" && b.EndsAt.Sub(b.StartedAt) == model.BoostDuration
+				})).Return(nil)
+			},
+		},
+		{
+			name: "Success - Paid With Credit",
+			setupMocks: func(br *mocks.IBoostRepository, cr *mocks.ICreditRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(plan, nil)
+				cr.On("LockUser", mock.Anything, "user1").Return(nil)
+				br.On("FindActiveByUserID", mock.Anything, "user1", mock.Anything).Return(model.Boost{}, nil)
+				qs.On("Check", mock.Anything, "user1", plan, model.QuotaBoost).Return(model.NewQuota(model.QuotaBoost, &limit, 1, time.Now()), nil)
+				cr.On("Balance", mock.Anything, "user1", model.CreditBoost).Return(2, nil)
+				cr.On("Create", mock.Anything, mock.MatchedBy(func(c model.Credit) bool {
+					return c.Type == model.CreditBoost && c.Amount == -1 && c.Reason == model.CreditReasonConsume
+				})).Return(nil)
+				br.On("Create", mock.Anything, mock.AnythingOfType("model.Boost")).Return(nil)
+			},
+		},
+		{
+			name: "Error - Boost Already Active",
+			setupMocks: func(br *mocks.IBoostRepository, cr *mocks.ICreditRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(plan, nil)
+				cr.On("LockUser", mock.Anything, "user1").Return(nil)
+				br.On("FindActiveByUserID", mock.Anything, "user1", mock.Anything).Return(model.Boost{ID: "boost1", UserID: "user1"}, nil)
+			},
+			expectedError: ErrBoostActive,
+		},
+		{
+			name: "Error - No Boosts Left",
+			setupMocks: func(br *mocks.IBoostRepository, cr *mocks.ICreditRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(plan, nil)
+				cr.On("LockUser", mock.Anything, "user1").Return(nil)
+				br.On("FindActiveByUserID", mock.Anything, "user1", mock.Anything).Return(model.Boost{}, nil)
+				qs.On("Check", mock.Anything, "user1", plan, model.QuotaBoost).Return(model.NewQuota(model.QuotaBoost, &limit, 1, time.Now()), nil)
+				cr.On("Balance", mock.Anything, "user1", model.CreditBoost).Return(0, nil)
+			},
+			expectedError: ErrNoBoostsLeft,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boostRepo := new(mocks.IBoostRepository)
+			creditRepo := new(mocks.ICreditRepository)
+			quotaService := new(mocks.IQuotaService)
+			tt.setupMocks(boostRepo, creditRepo, quotaService)
+
+			service := NewBoostService(passthroughTransactor(), boostRepo, creditRepo, quotaService)
+			boost, err := service.Activate(context.Background(), "user1")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, boost.Active)
+				assert.NotEmpty(t, boost.ID)
+			}
+
+			boostRepo.AssertExpectations(t)
+			creditRepo.AssertExpectations(t)
+			quotaService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBoostService_FindAll(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IBoostRepository)
+		expected      []model.BoostResponse
+		expectedTotal int64
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(br *mocks.IBoostRepository) {
+				br.On("FindByUserID", mock.Anything, "user1", mock.Anything).Return([]model.Boost{
+					{ID: "boost1", UserID: "user1", StartedAt: now.Add(-time.Hour), EndsAt: now.Add(-30 * time.Minute), Views: 40, Likes: 3},
+				}, int64(1), nil)
+			},
+			expected: []model.BoostResponse{
+				{ID: "boost1", Active: false, StartedAt: now.Add(-time.Hour), EndsAt: now.Add(-30 * time.Minute), Views: 40, Likes: 3},
+			},
+			expectedTotal: 1,
+		},
+		{
+			name: "Error - Failed to Find Boosts",
+			setupMocks: func(br *mocks.IBoostRepository) {
+				br.On("FindByUserID", mock.Anything, "user1", mock.Anything).Return(nil, int64(0), errors.New("db error"))
+			},
+			expectedError: errors.New("failed to find boosts"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boostRepo := new(mocks.IBoostRepository)
+			tt.setupMocks(boostRepo)
+
+			service := NewBoostService(passthroughTransactor(), boostRepo, new(mocks.ICreditRepository), new(mocks.IQuotaService))
+			boosts, total, err := service.FindAll(context.Background(), "user1", model.PaginationRequest{})
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, boosts)
+				assert.Equal(t, tt.expectedTotal, total)
+			}
+
+			boostRepo.AssertExpectations(t)
+		})
+	}
+}
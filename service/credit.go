@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/marvelalexius/jones/config"
+	"github.com/marvelalexius/jones/model"
+	stripePkg "github.com/marvelalexius/jones/pkg/stripe"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/utils/logger"
+	"github.com/stripe/stripe-go/v76"
+	"gorm.io/gorm"
+)
+
+type (
+	CreditService struct {
+		Conf         *config.Config
+		StripeClient stripePkg.IStripeClient
+		UserRepo     repository.IUserRepository
+		CreditRepo   repository.ICreditRepository
+	}
+
+	ICreditService interface {
+		Purchase(ctx context.Context, userID string, req model.CreditPurchaseRequest) (string, error)
+		Balances(ctx context.Context, userID string) (map[string]int, error)
+		HandleCheckoutSessionCompleted(ctx context.Context, session *stripe.CheckoutSession) error
+	}
+)
+
+func NewCreditService(conf *config.Config, stripeClient stripePkg.IStripeClient, userRepo repository.IUserRepository, creditRepo repository.ICreditRepository) ICreditService {
+	return &CreditService{Conf: conf, StripeClient: stripeClient, UserRepo: userRepo, CreditRepo: creditRepo}
+}
+
+// Purchase starts a stripe checkout for credit packs and returns its URL. The credits are only granted once
+// the checkout is paid, so there's nothing to buy while stripe is off or the pack has no price.
+func (s *CreditService) Purchase(ctx context.Context, userID string, req model.CreditPurchaseRequest) (string, error) {
+	priceID := s.Conf.Stripe.CreditPriceIDs[req.Type]
+	if !s.Conf.FeatureFlag.EnableStripe || priceID == "" {
+		return "", ErrPaymentsDisabled
+	}
+
+	user, err := s.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "error finding user by ID", err)
+
+		return "", err
+	}
+
+	if user.StripeCustomerID == "" {
+		customer, err := s.StripeClient.CreateCustomer(ctx, user.Email, user.Name)
+		if err != nil {
+			logger.Errorln(ctx, "error requesting create customer to stripe", err)
+
+			return "", err
+		}
+
+		user.StripeCustomerID = customer.ID
+		user, err = s.UserRepo.Update(user)
+		if err != nil {
+			logger.Errorln(ctx, "error updating user", err)
+
+			return "", err
+		}
+	}
+
+	checkoutSession, err := s.StripeClient.CreatePaymentCheckoutSession(ctx, user.StripeCustomerID, priceID, int64(req.Quantity), map[string]string{
+		"user_id":     user.ID,
+		"credit_type": req.Type,
+		"quantity":    strconv.Itoa(req.Quantity),
+	})
+	if err != nil {
+		logger.Errorln(ctx, "error requesting create checkout session to stripe", err)
+
+		return "", err
+	}
+
+	return checkoutSession.URL, nil
+}
+
+func (s *CreditService) Balances(ctx context.Context, userID string) (map[string]int, error) {
+	balances, err := s.CreditRepo.Balances(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "error finding credit balances", err)
+
+		return nil, errors.New("failed to find credit balances")
+	}
+
+	for creditType := range model.CreditPackSizes {
+		if _, ok := balances[creditType]; !ok {
+			balances[creditType] = 0
+		}
+	}
+
+	return balances, nil
+}
+
+func (s *CreditService) HandleCheckoutSessionCompleted(ctx context.Context, session *stripe.CheckoutSession) error {
+	creditType := session.Metadata["credit_type"]
+	if session.Mode != stripe.CheckoutSessionModePayment || creditType == "" {
+		return nil
+	}
+
+	if session.PaymentStatus != stripe.CheckoutSessionPaymentStatusPaid {
+		logger.Infoln(ctx, "checkout session is not paid yet", session.ID)
+
+		return nil
+	}
+
+	quantity, err := strconv.Atoi(session.Metadata["quantity"])
+	if err != nil {
+		logger.Errorln(ctx, "error parsing credit quantity", err)
+
+		return err
+	}
+
+	credit := model.NewCredit(session.Metadata["user_id"], creditType, quantity*model.CreditPackSizes[creditType], model.CreditReasonPurchase, session.ID)
+
+	err = s.CreditRepo.Create(ctx, credit)
+	if err != nil {
+		// stripe retries webhooks, the checkout session has already been granted
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil
+		}
+
+		logger.Errorln(ctx, "error creating credit", err)
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marvelalexius/jones/config"
+	"github.com/marvelalexius/jones/mocks"
+	"github.com/marvelalexius/jones/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stripe/stripe-go/v76"
+	"gorm.io/gorm"
+)
+
+func TestCreditService_Purchase(t *testing.T) {
+	tests := []struct {
+		name          string
+		conf          *config.Config
+		request       model.CreditPurchaseRequest
+		setupMocks    func(*mocks.IStripeClient, *mocks.IUserRepository, *mocks.ICreditRepository)
+		expectedURL   string
+		expectedError error
+	}{
+		{
+			name: "Success - Stripe Checkout",
+			conf: &config.Config{
+				FeatureFlag: config.FeatureFlag{EnableStripe: true},
+				Stripe:      config.Stripe{CreditPriceIDs: map[string]string{model.CreditSuperLike: "price_1"}},
+			},
+			request: model.CreditPurchaseRequest{Type: model.CreditSuperLike, Quantity: 1},
+			setupMocks: func(sc *mocks.IStripeClient, ur *mocks.IUserRepository, cr *mocks.ICreditRepository) {
+				ur.On("FindByID", mock.Anything, "user1").Return(&model.User{ID: "user1", StripeCustomerID: "cus_1"}, nil)
+				sc.On("CreatePaymentCheckoutSession", mock.Anything, "cus_1", "price_1", int64(1), map[string]string{
+					"user_id":     "user1",
+					"credit_type": model.CreditSuperLike,
+					"quantity":    "1",
+				}).Return(&stripe.CheckoutSession{URL: "https://checkout.stripe.com/1"}, nil)
+			},
+			expectedURL: "https://checkout.stripe.com/1",
+		},
+		{
+			name:          "Error - Stripe Disabled",
+			conf:          &config.Config{Stripe: config.Stripe{CreditPriceIDs: map[string]string{model.CreditSuperLike: "price_1"}}},
+			request:       model.CreditPurchaseRequest{Type: model.CreditSuperLike, Quantity: 1},
+			setupMocks:    func(sc *mocks.IStripeClient, ur *mocks.IUserRepository, cr *mocks.ICreditRepository) {},
+			expectedError: ErrPaymentsDisabled,
+		},
+		{
+			name:          "Error - Pack Without Price",
+			conf:          &config.Config{FeatureFlag: config.FeatureFlag{EnableStripe: true}},
+			request:       model.CreditPurchaseRequest{Type: model.CreditSuperLike, Quantity: 1},
+			setupMocks:    func(sc *mocks.IStripeClient, ur *mocks.IUserRepository, cr *mocks.ICreditRepository) {},
+			expectedError: ErrPaymentsDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stripeClient := new(mocks.IStripeClient)
+			userRepo := new(mocks.IUserRepository)
+			creditRepo := new(mocks.ICreditRepository)
+			tt.setupMocks(stripeClient, userRepo, creditRepo)
+
+			service := NewCreditService(tt.conf, stripeClient, userRepo, creditRepo)
+			url, err := service.Purchase(context.Background(), "user1", tt.request)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedURL, url)
+			}
+			stripeClient.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+			creditRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCreditService_HandleCheckoutSessionCompleted(t *testing.T) {
+	paidSession := &stripe.CheckoutSession{
+		ID:            "cs_1",
+		Mode:          stripe.CheckoutSessionModePayment,
+		PaymentStatus: stripe.CheckoutSessionPaymentStatusPaid,
+		Metadata: map[string]string{
+			"user_id":     "user1",
+			"credit_type": model.CreditSuperLike,
+			"quantity":    "2",
+		},
+	}
+
+	tests := []struct {
+		name          string
+		session       *stripe.CheckoutSession
+		setupMocks    func(*mocks.ICreditRepository)
+		expectedError error
+	}{
+		{
+			name:    "Success - Grants Credits",
+			session: paidSession,
+			setupMocks: func(cr *mocks.ICreditRepository) {
+				cr.On("Create", mock.Anything, mock.MatchedBy(func(c model.Credit) bool {
+					return c.UserID == "user1" && c.Amount == 10 && c.ReferenceID == "cs_1"
+				})).Return(nil)
+			},
+		},
+		{
+			name:    "Success - Already Granted",
+			session: paidSession,
+			setupMocks: func(cr *mocks.ICreditRepository) {
+				cr.On("Create", mock.Anything, mock.AnythingOfType("model.Credit")).Return(gorm.ErrDuplicatedKey)
+			},
+		},
+		{
+			name: "Success - Ignores Subscription Checkout",
+			session: &stripe.CheckoutSession{
+				ID:   "cs_2",
+				Mode: stripe.CheckoutSessionModeSubscription,
+			},
+			setupMocks: func(cr *mocks.ICreditRepository) {},
+		},
+		{
+			name: "Success - Ignores Unpaid Checkout",
+			session: &stripe.CheckoutSession{
+				ID:            "cs_3",
+				Mode:          stripe.CheckoutSessionModePayment,
+				PaymentStatus: stripe.CheckoutSessionPaymentStatusUnpaid,
+				Metadata:      paidSession.Metadata,
+			},
+			setupMocks: func(cr *mocks.ICreditRepository) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creditRepo := new(mocks.ICreditRepository)
+			tt.setupMocks(creditRepo)
+
+			service := NewCreditService(&config.Config{}, new(mocks.IStripeClient), new(mocks.IUserRepository), creditRepo)
+			err := service.HandleCheckoutSessionCompleted(context.Background(), tt.session)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			creditRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+type (
+	DeviceService struct {
+		DeviceRepo repository.IDeviceRepository
+	}
+
+	IDeviceService interface {
+		Register(ctx context.Context, userID string, req model.RegisterDeviceRequest) (model.Device, error)
+		Unregister(ctx context.Context, userID, token string) error
+	}
+)
+
+func NewDeviceService(deviceRepo repository.IDeviceRepository) IDeviceService {
+	return &DeviceService{DeviceRepo: deviceRepo}
+}
+
+// Register is called on every app start, registering a token again only refreshes it.
+func (s *DeviceService) Register(ctx context.Context, userID string, req model.RegisterDeviceRequest) (model.Device, error) {
+	device := model.NewDevice(userID, req, time.Now())
+
+	err := s.DeviceRepo.Upsert(ctx, &device)
+	if err != nil {
+		logger.Errorln(ctx, "failed to register device", err)
+
+		return model.Device{}, errors.New("failed to register device")
+	}
+
+	return device, nil
+}
+
+// Unregister is called on sign out so the device stops getting the user's notifications.
+func (s *DeviceService) Unregister(ctx context.Context, userID, token string) error {
+	found, err := s.DeviceRepo.Delete(ctx, userID, token)
+	if err != nil {
+		logger.Errorln(ctx, "failed to unregister device", err)
+
+		return errors.New("failed to unregister device")
+	}
+
+	if !found {
+		return ErrDeviceNotFound
+	}
+
+	return nil
+}
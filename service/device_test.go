@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/marvelalexius/jones/mocks"
+	"github.com/marvelalexius/jones/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDeviceService_Register(t *testing.T) {
+	ctx := context.Background()
+	req := model.RegisterDeviceRequest{Platform: model.PlatformIOS, Token: "token1"}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IDeviceRepository)
+		expectedID    string
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(dr *mocks.IDeviceRepository) {
+				dr.On("Upsert", ctx, mock.MatchedBy(func(device *model.Device) bool {
+					return device.UserID == "user1" && device.Platform == model.PlatformIOS && device.Token == "token1"
+				})).Return(nil)
+			},
+		},
+		{
+			name: "Success - Token Already Registered",
+			setupMocks: func(dr *mocks.IDeviceRepository) {
+				dr.On("Upsert", ctx, mock.AnythingOfType("*model.Device")).Run(func(args mock.Arguments) {
+					args.Get(1).(*model.Device).ID = "device1"
+				}).Return(nil)
+			},
+			expectedID: "device1",
+		},
+		{
+			name: "Error - DB Error",
+			setupMocks: func(dr *mocks.IDeviceRepository) {
+				dr.On("Upsert", ctx, mock.AnythingOfType("*model.Device")).Return(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to register device"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deviceRepo := new(mocks.IDeviceRepository)
+			tt.setupMocks(deviceRepo)
+
+			service := NewDeviceService(deviceRepo)
+			device, err := service.Register(ctx, "user1", req)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, device.ID)
+				assert.Equal(t, "token1", device.Token)
+				// the stored device comes back, not the one that lost to it
+				if tt.expectedID != "" {
+					assert.Equal(t, tt.expectedID, device.ID)
+				}
+			}
+
+			deviceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeviceService_Unregister(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IDeviceRepository)
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(dr *mocks.IDeviceRepository) {
+				dr.On("Delete", ctx, "user1", "token1").Return(true, nil)
+			},
+		},
+		{
+			name: "Error - Someone Else's Device",
+			setupMocks: func(dr *mocks.IDeviceRepository) {
+				dr.On("Delete", ctx, "user1", "token1").Return(false, nil)
+			},
+			expectedError: ErrDeviceNotFound,
+		},
+		{
+			name: "Error - DB Error",
+			setupMocks: func(dr *mocks.IDeviceRepository) {
+				dr.On("Delete", ctx, "user1", "token1").Return(false, errors.New("db error"))
+			},
+			expectedError: errors.New("failed to unregister device"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deviceRepo := new(mocks.IDeviceRepository)
+			tt.setupMocks(deviceRepo)
+
+			service := NewDeviceService(deviceRepo)
+			err := service.Unregister(ctx, "user1", "token1")
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			deviceRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/i18n"
+	"github.com/marvelalexius/jones/pkg/mailer"
+	"github.com/marvelalexius/jones/pkg/push"
+	"github.com/marvelalexius/jones/pkg/realtime"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+const deferredNotificationBatchSize = 500
+
+var errNotificationStored = errors.New("notification is already stored")
+
+type (
+	// NotificationDispatcher writes a notification in the recipient's language and hands it to every channel
+	// its type goes out on that the recipient hasn't turned off. Channels that aren't configured are skipped.
+	NotificationDispatcher struct {
+		Transactor     repository.ITransactor
+		UserRepo       repository.IUserRepository
+		PreferenceRepo repository.INotificationPreferenceRepository
+		DeferredRepo   repository.IDeferredNotificationRepository
+		OutboxRepo     repository.IOutboxRepository
+		Channels       map[string]INotificationChannel
+	}
+
+	INotificationDispatcher interface {
+		Dispatch(ctx context.Context, id, userID, referenceID string, payload model.NotificationPayload) error
+		DeliverDeferred(ctx context.Context) error
+		SendDeferred(ctx context.Context, item model.DeferredNotification) error
+	}
+
+	// INotificationChannel delivers a notification that's already written in the recipient's language.
+	INotificationChannel interface {
+		Deliver(ctx context.Context, recipient *model.User, notification model.Notification, copy i18n.Message) error
+	}
+
+	// InAppChannel stores the notification for the inbox and pushes it to the user's open streams.
+	InAppChannel struct {
+		NotificationRepo repository.INotificationRepository
+		Publisher        realtime.IPublisher
+	}
+
+	// PushChannel sends to every device the user registered, forgetting the ones the provider rejects for good.
+	PushChannel struct {
+		DeviceRepo repository.IDeviceRepository
+		// Providers are keyed by device platform
+		Providers map[string]push.IProvider
+	}
+
+	EmailChannel struct {
+		Mailer mailer.IMailer
+	}
+)
+
+func NewNotificationDispatcher(transactor repository.ITransactor, userRepo repository.IUserRepository, preferenceRepo repository.INotificationPreferenceRepository, deferredRepo repository.IDeferredNotificationRepository, outboxRepo repository.IOutboxRepository, channels map[string]INotificationChannel) INotificationDispatcher {
+	return &NotificationDispatcher{Transactor: transactor, UserRepo: userRepo, PreferenceRepo: preferenceRepo, DeferredRepo: deferredRepo, OutboxRepo: outboxRepo, Channels: channels}
+}
+
+func NewInAppChannel(notificationRepo repository.INotificationRepository, publisher realtime.IPublisher) INotificationChannel {
+	return &InAppChannel{NotificationRepo: notificationRepo, Publisher: publisher}
+}
+
+func NewPushChannel(deviceRepo repository.IDeviceRepository, providers map[string]push.IProvider) INotificationChannel {
+	return &PushChannel{DeviceRepo: deviceRepo, Providers: providers}
+}
+
+func NewEmailChannel(mailer mailer.IMailer) INotificationChannel {
+	return &EmailChannel{Mailer: mailer}
+}
+
+// Dispatch fails when the notification can't be stored, the other channels are best effort. Sending the same ID
+// again does nothing once it's stored, the inbox comes first in every notification type.
+func (d *NotificationDispatcher) Dispatch(ctx context.Context, id, userID, referenceID string, payload model.NotificationPayload) error {
+	recipient, err := d.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		// still worth sending in-app, just not in their language
+		logger.Errorln(ctx, "failed to find notification recipient", err)
+
+		recipient = &model.User{ID: userID}
+	}
+
+	notification, copy, err := newNotification(id, recipient, referenceID, payload)
+	if err != nil {
+		return err
+	}
+
+	kind := model.NotificationTypes[notification.Type]
+
+	var disabled map[string]bool
+	var quietUntil time.Time
+	var quiet bool
+	if !kind.Transactional {
+		disabled = d.disabledChannels(ctx, recipient.ID, notification.Type)
+		quietUntil, quiet = recipient.QuietUntil(notification.CreatedAt)
+	}
+
+	for _, name := range kind.Channels {
+		channel, ok := d.Channels[name]
+		if !ok || disabled[name] {
+			continue
+		}
+
+		// the inbox doesn't buzz, so only push and email wait for the quiet hours to end
+		if quiet && name != model.NotificationChannelInApp {
+			err = d.DeferredRepo.Create(ctx, model.NewDeferredNotification(notification, name, copy.Title, copy.Body, quietUntil))
+			if err != nil {
+				logger.Errorln(ctx, "failed to defer notification", name, notification.ID, err)
+			}
+
+			continue
+		}
+
+		err = channel.Deliver(ctx, recipient, notification, copy)
+		if err == nil {
+			continue
+		}
+
+		// an earlier attempt already sent it
+		if errors.Is(err, errNotificationStored) {
+			return nil
+		}
+
+		if name == model.NotificationChannelInApp {
+			return err
+		}
+
+		logger.Errorln(ctx, "failed to deliver notification", name, notification.ID, err)
+	}
+
+	return nil
+}
+
+// DeliverDeferred hands what quiet hours held back to the outbox once they're over, which sends it with retries.
+func (d *NotificationDispatcher) DeliverDeferred(ctx context.Context) error {
+	now := time.Now()
+
+	lastID := ""
+	for {
+		deferred, err := d.DeferredRepo.FindDue(ctx, lastID, now, deferredNotificationBatchSize)
+		if err != nil {
+			logger.Errorln(ctx, "failed to find deferred notifications", err)
+
+			return errors.New("failed to find deferred notifications")
+		}
+
+		for _, item := range deferred {
+			err = d.enqueueDeferred(ctx, item, now)
+			if err != nil {
+				logger.Errorln(ctx, "failed to enqueue deferred notification", item.ID, err)
+			}
+		}
+
+		if len(deferred) < deferredNotificationBatchSize {
+			break
+		}
+
+		lastID = deferred[len(deferred)-1].ID
+	}
+
+	return nil
+}
+
+// enqueueDeferred moves a due notification to the outbox, it stays deferred if that fails.
+func (d *NotificationDispatcher) enqueueDeferred(ctx context.Context, item model.DeferredNotification, now time.Time) error {
+	return d.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		claimed, err := d.DeferredRepo.Delete(ctx, item.ID)
+		if err != nil {
+			return err
+		}
+
+		// another run already moved it
+		if !claimed {
+			return nil
+		}
+
+		message, err := model.NewDeferredNotificationMessage(item, now)
+		if err != nil {
+			return err
+		}
+
+		return d.OutboxRepo.Create(ctx, message)
+	})
+}
+
+// SendDeferred delivers a notification quiet hours held back on the channel it waited for. It fails when the
+// delivery can be tried again.
+func (d *NotificationDispatcher) SendDeferred(ctx context.Context, item model.DeferredNotification) error {
+	channel, ok := d.Channels[item.Channel]
+	if !ok {
+		return nil
+	}
+
+	recipient, err := d.UserRepo.FindByID(ctx, item.UserID)
+	if err != nil {
+		return err
+	}
+
+	// they may have turned it off while it waited
+	if d.disabledChannels(ctx, recipient.ID, item.Type)[item.Channel] {
+		return nil
+	}
+
+	return channel.Deliver(ctx, recipient, item.Notification(), i18n.Message{Title: item.Title, Body: item.Body})
+}
+
+// disabledChannels returns the channels the user turned off for the notification type. Preferences that can't
+// be loaded don't hold the notification back.
+func (d *NotificationDispatcher) disabledChannels(ctx context.Context, userID, notificationType string) map[string]bool {
+	preferences, err := d.PreferenceRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find notification preferences", err)
+
+		return nil
+	}
+
+	disabled := map[string]bool{}
+	for _, preference := range preferences {
+		if preference.Type == notificationType && !preference.Enabled && model.ConfigurableChannel(preference.Type, preference.Channel) {
+			disabled[preference.Channel] = true
+		}
+	}
+
+	return disabled
+}
+
+func (c *InAppChannel) Deliver(ctx context.Context, recipient *model.User, notification model.Notification, copy i18n.Message) error {
+	stored, err := c.NotificationRepo.Create(&notification)
+	if err != nil {
+		return err
+	}
+
+	if !stored {
+		return errNotificationStored
+	}
+
+	publish(ctx, c.Publisher, realtime.EventNotification, notification.UserID, notification)
+
+	return nil
+}
+
+func (c *PushChannel) Deliver(ctx context.Context, recipient *model.User, notification model.Notification, copy i18n.Message) error {
+	devices, err := c.DeviceRepo.FindByUserID(ctx, recipient.ID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, device := range devices {
+		provider, ok := c.Providers[device.Platform]
+		if !ok {
+			continue
+		}
+
+		err = provider.Send(ctx, push.Message{
+			Token: device.Token,
+			Title: copy.Title,
+			Body:  copy.Body,
+			Data:  map[string]string{"notification_id": notification.ID, "type": notification.Type},
+		})
+		if errors.Is(err, push.ErrInvalidToken) {
+			if err = c.DeviceRepo.DeleteByToken(ctx, device.Token); err != nil {
+				logger.Errorln(ctx, "failed to forget push token", err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *EmailChannel) Deliver(ctx context.Context, recipient *model.User, notification model.Notification, copy i18n.Message) error {
+	// the recipient couldn't be found
+	if recipient.Email == "" {
+		return nil
+	}
+
+	return c.Mailer.Send(ctx, mailer.Mail{To: recipient.Email, Subject: copy.Title, Body: copy.Body})
+}
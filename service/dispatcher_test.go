@@ -0,0 +1,526 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marvelalexius/jones/mocks"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/mailer"
+	"github.com/marvelalexius/jones/pkg/push"
+	"github.com/marvelalexius/jones/pkg/realtime"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testDispatcher only delivers in-app, so services can be tested against the notification repo alone.
+func testDispatcher(userRepo repository.IUserRepository, notificationRepo repository.INotificationRepository, publisher realtime.IPublisher) INotificationDispatcher {
+	return NewNotificationDispatcher(passthroughTransactor(), userRepo, noPreferences(), new(mocks.IDeferredNotificationRepository), new(mocks.IOutboxRepository), map[string]INotificationChannel{
+		model.NotificationChannelInApp: NewInAppChannel(notificationRepo, publisher),
+	})
+}
+
+// noDeferred has nothing held back by quiet hours.
+func noDeferred() *mocks.IDeferredNotificationRepository {
+	deferredRepo := new(mocks.IDeferredNotificationRepository)
+	deferredRepo.On("DeleteByReferenceID", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return deferredRepo
+}
+
+// noPreferences is a user who left every notification on.
+func noPreferences() *mocks.INotificationPreferenceRepository {
+	preferenceRepo := new(mocks.INotificationPreferenceRepository)
+	preferenceRepo.On("FindByUserID", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+
+	return preferenceRepo
+}
+
+func TestNotificationDispatcher_Dispatch(t *testing.T) {
+	ctx := context.Background()
+	payload := model.MatchPayload{MatchID: "match1", UserID: "user2"}
+
+	tests := []struct {
+		name            string
+		recipient       *model.User
+		findErr         error
+		createErr       error
+		alreadyStored   bool
+		expectedTitle   string
+		expectedMessage string
+		expectedEvent   bool
+	}{
+		{
+			name:            "Success - Written In The Recipient's Language",
+			recipient:       &model.User{ID: "user1", Language: "id"},
+			expectedTitle:   "Kamu dapat match!",
+			expectedMessage: "Selamat! Kamu mendapatkan match",
+			expectedEvent:   true,
+		},
+		{
+			name:            "Success - Untranslated Language Falls Back To English",
+			recipient:       &model.User{ID: "user1", Language: "fr"},
+			expectedTitle:   "It's a match!",
+			expectedMessage: "Congratulations! You matched",
+			expectedEvent:   true,
+		},
+		{
+			name:            "Success - Sent In English When The Recipient Can't Be Found",
+			findErr:         errors.New("db error"),
+			expectedTitle:   "It's a match!",
+			expectedMessage: "Congratulations! You matched",
+			expectedEvent:   true,
+		},
+		{
+			name:          "Success - Sent Again Isn't Stored Or Pushed Twice",
+			recipient:     &model.User{ID: "user1", Language: model.DefaultLanguage},
+			alreadyStored: true,
+		},
+		{
+			name:      "Error - Not Pushed When Not Stored",
+			recipient: &model.User{ID: "user1", Language: model.DefaultLanguage},
+			createErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			userRepo.On("FindByID", ctx, "user1").Return(tt.recipient, tt.findErr)
+
+			var stored model.Notification
+			notificationRepo := new(mocks.INotificationRepository)
+			notificationRepo.On("Create", mock.AnythingOfType("*model.Notification")).Run(func(args mock.Arguments) {
+				notification := args.Get(0).(*model.Notification)
+				notification.Seq = 42
+				stored = *notification
+			}).Return(tt.createErr == nil && !tt.alreadyStored, tt.createErr)
+
+			broker, hub := realtime.NewMemoryBroker(), realtime.NewHub()
+			broker.Subscribe(hub.Deliver)
+			client := realtime.NewClient("user1")
+			hub.Register(client)
+
+			err := testDispatcher(userRepo, notificationRepo, broker).Dispatch(ctx, "notification1", "user1", "reaction1", payload)
+			assert.Equal(t, tt.createErr, err)
+
+			if tt.expectedEvent {
+				assert.Equal(t, "notification1", stored.ID)
+				assert.Equal(t, "user1", stored.UserID)
+				assert.Equal(t, model.NotificationTypeMatch, stored.Type)
+				assert.Equal(t, "reaction1", stored.ReferenceID)
+
+				var content struct {
+					Type    string             `json:"type"`
+					Title   string             `json:"title"`
+					Message string             `json:"message"`
+					Data    model.MatchPayload `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal([]byte(stored.Content), &content))
+				assert.Equal(t, model.NotificationTypeMatch, content.Type)
+				assert.Equal(t, tt.expectedTitle, content.Title)
+				assert.Equal(t, tt.expectedMessage, content.Message)
+				assert.Equal(t, payload, content.Data)
+
+				// streams resume from the sequence the database gave it
+				var published model.Notification
+				event := <-client.Send
+				assert.Equal(t, realtime.EventNotification, event.Type)
+				assert.NoError(t, json.Unmarshal(event.Payload, &published))
+				assert.Equal(t, stored.ID, published.ID)
+				assert.Equal(t, int64(42), published.Seq)
+			} else {
+				assert.Empty(t, client.Send)
+			}
+
+			userRepo.AssertExpectations(t)
+			notificationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationDispatcher_Channels(t *testing.T) {
+	ctx := context.Background()
+	recipient := &model.User{ID: "user1", Email: "ana@example.com", Language: model.DefaultLanguage}
+	devices := []model.Device{
+		{ID: "device1", UserID: "user1", Platform: model.PlatformAndroid, Token: "android"},
+		{ID: "device2", UserID: "user1", Platform: model.PlatformIOS, Token: "uninstalled"},
+	}
+
+	tests := []struct {
+		name           string
+		payload        model.NotificationPayload
+		createErr      error
+		setupMocks     func(*mocks.IDeviceRepository)
+		expectedError  error
+		expectedPushes []string
+		expectedMails  int
+	}{
+		{
+			name:    "Success - Pushed To Every Device, Uninstalled Ones Forgotten",
+			payload: model.MatchPayload{MatchID: "match1", UserID: "user2"},
+			setupMocks: func(dr *mocks.IDeviceRepository) {
+				dr.On("FindByUserID", ctx, "user1").Return(devices, nil)
+				dr.On("DeleteByToken", ctx, "uninstalled").Return(nil)
+			},
+			expectedPushes: []string{"android"},
+		},
+		{
+			name:           "Success - Billing Also Goes Out By Email",
+			payload:        model.PaymentFailedPayload{},
+			setupMocks:     func(dr *mocks.IDeviceRepository) { dr.On("FindByUserID", ctx, "user1").Return(devices[:1], nil) },
+			expectedPushes: []string{"android"},
+			expectedMails:  1,
+		},
+		{
+			name:          "Success - Renewals Aren't Pushed",
+			payload:       model.SubscriptionPayload{Type: model.NotificationTypeSubscriptionRenewed, SubscriptionID: "subscription1"},
+			setupMocks:    func(dr *mocks.IDeviceRepository) {},
+			expectedMails: 1,
+		},
+		{
+			name:    "Success - Push Failures Don't Fail The Notification",
+			payload: model.MatchPayload{MatchID: "match1", UserID: "user2"},
+			setupMocks: func(dr *mocks.IDeviceRepository) {
+				dr.On("FindByUserID", ctx, "user1").Return(nil, errors.New("db error"))
+			},
+		},
+		{
+			name:          "Error - Nothing Else Goes Out When Not Stored",
+			payload:       model.PaymentFailedPayload{},
+			createErr:     errors.New("db error"),
+			setupMocks:    func(dr *mocks.IDeviceRepository) {},
+			expectedError: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			userRepo.On("FindByID", ctx, "user1").Return(recipient, nil)
+
+			notificationRepo := new(mocks.INotificationRepository)
+			notificationRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(tt.createErr == nil, tt.createErr)
+
+			deviceRepo := new(mocks.IDeviceRepository)
+			tt.setupMocks(deviceRepo)
+
+			fcm, apns, mail := push.NewFake(), push.NewFake("uninstalled"), mailer.NewFake()
+			dispatcher := NewNotificationDispatcher(passthroughTransactor(), userRepo, noPreferences(), new(mocks.IDeferredNotificationRepository), new(mocks.IOutboxRepository), map[string]INotificationChannel{
+				model.NotificationChannelInApp: NewInAppChannel(notificationRepo, realtime.NewMemoryBroker()),
+				model.NotificationChannelPush:  NewPushChannel(deviceRepo, map[string]push.IProvider{model.PlatformAndroid: fcm, model.PlatformIOS: apns}),
+				model.NotificationChannelEmail: NewEmailChannel(mail),
+			})
+
+			err := dispatcher.Dispatch(ctx, "notification1", "user1", "reference1", tt.payload)
+			assert.Equal(t, tt.expectedError, err)
+
+			var pushed []string
+			for _, msg := range append(fcm.Sent(), apns.Sent()...) {
+				assert.Equal(t, tt.payload.NotificationType(), msg.Data["type"])
+				pushed = append(pushed, msg.Token)
+			}
+			assert.Equal(t, tt.expectedPushes, pushed)
+
+			assert.Len(t, mail.Sent(), tt.expectedMails)
+			for _, sent := range mail.Sent() {
+				assert.Equal(t, recipient.Email, sent.To)
+				assert.NotEmpty(t, sent.Subject)
+			}
+
+			deviceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationDispatcher_Preferences(t *testing.T) {
+	ctx := context.Background()
+	match := model.MatchPayload{MatchID: "match1", UserID: "user2"}
+	paymentFailed := model.PaymentFailedPayload{}
+
+	// quiet hours around the time the test runs
+	now := time.Now().UTC()
+	quietUser := &model.User{ID: "user1", Email: "ana@example.com", Timezone: "UTC", QuietHoursStart: now.Add(-time.Hour).Format(model.QuietHoursLayout), QuietHoursEnd: now.Add(time.Hour).Format(model.QuietHoursLayout)}
+
+	tests := []struct {
+		name             string
+		recipient        *model.User
+		payload          model.NotificationPayload
+		setupMocks       func(*mocks.INotificationPreferenceRepository, *mocks.IDeferredNotificationRepository)
+		expectedStored   bool
+		expectedPushes   int
+		expectedMails    int
+		expectedDeferred []string
+	}{
+		{
+			name:    "Success - Turned Off Channel Is Skipped",
+			payload: match,
+			setupMocks: func(pr *mocks.INotificationPreferenceRepository, dr *mocks.IDeferredNotificationRepository) {
+				pr.On("FindByUserID", ctx, "user1").Return([]model.NotificationPreference{
+					{UserID: "user1", Type: model.NotificationTypeMatch, Channel: model.NotificationChannelPush, Enabled: false},
+					{UserID: "user1", Type: model.NotificationTypeMessage, Channel: model.NotificationChannelInApp, Enabled: false},
+				}, nil)
+			},
+			expectedStored: true,
+		},
+		{
+			name:    "Success - Inbox Can't Be Turned Off",
+			payload: match,
+			setupMocks: func(pr *mocks.INotificationPreferenceRepository, dr *mocks.IDeferredNotificationRepository) {
+				pr.On("FindByUserID", ctx, "user1").Return([]model.NotificationPreference{
+					{UserID: "user1", Type: model.NotificationTypeMatch, Channel: model.NotificationChannelInApp, Enabled: false},
+				}, nil)
+			},
+			expectedStored: true,
+			expectedPushes: 1,
+		},
+		{
+			name:    "Success - Sent Anyway When Preferences Can't Be Loaded",
+			payload: match,
+			setupMocks: func(pr *mocks.INotificationPreferenceRepository, dr *mocks.IDeferredNotificationRepository) {
+				pr.On("FindByUserID", ctx, "user1").Return(nil, errors.New("db error"))
+			},
+			expectedStored: true,
+			expectedPushes: 1,
+		},
+		{
+			name:       "Success - Transactional Notifications Can't Be Turned Off",
+			payload:    paymentFailed,
+			setupMocks: func(pr *mocks.INotificationPreferenceRepository, dr *mocks.IDeferredNotificationRepository) {},
+			// preferences aren't even looked at
+			expectedStored: true,
+			expectedPushes: 1,
+			expectedMails:  1,
+		},
+		{
+			name:      "Success - Quiet Hours Hold Back Push But Not The Inbox",
+			recipient: quietUser,
+			payload:   match,
+			setupMocks: func(pr *mocks.INotificationPreferenceRepository, dr *mocks.IDeferredNotificationRepository) {
+				pr.On("FindByUserID", ctx, "user1").Return(nil, nil)
+				dr.On("Create", ctx, mock.MatchedBy(func(deferred model.DeferredNotification) bool {
+					return deferred.UserID == "user1" && deferred.Type == model.NotificationTypeMatch && deferred.Title == "It's a match!" &&
+						deferred.DeliverAt.After(now) && !deferred.DeliverAt.After(now.Add(time.Hour))
+				})).Return(nil)
+			},
+			expectedStored:   true,
+			expectedDeferred: []string{model.NotificationChannelPush},
+		},
+		{
+			name:           "Success - Transactional Notifications Go Out During Quiet Hours",
+			recipient:      quietUser,
+			payload:        paymentFailed,
+			setupMocks:     func(pr *mocks.INotificationPreferenceRepository, dr *mocks.IDeferredNotificationRepository) {},
+			expectedStored: true,
+			expectedPushes: 1,
+			expectedMails:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipient := tt.recipient
+			if recipient == nil {
+				recipient = &model.User{ID: "user1", Email: "ana@example.com"}
+			}
+
+			userRepo := new(mocks.IUserRepository)
+			userRepo.On("FindByID", ctx, "user1").Return(recipient, nil)
+
+			notificationRepo := new(mocks.INotificationRepository)
+			if tt.expectedStored {
+				notificationRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil)
+			}
+
+			deviceRepo := new(mocks.IDeviceRepository)
+			deviceRepo.On("FindByUserID", ctx, "user1").Return([]model.Device{{UserID: "user1", Platform: model.PlatformAndroid, Token: "android"}}, nil).Maybe()
+
+			preferenceRepo, deferredRepo := new(mocks.INotificationPreferenceRepository), new(mocks.IDeferredNotificationRepository)
+			tt.setupMocks(preferenceRepo, deferredRepo)
+
+			fcm, mail := push.NewFake(), mailer.NewFake()
+			dispatcher := NewNotificationDispatcher(passthroughTransactor(), userRepo, preferenceRepo, deferredRepo, new(mocks.IOutboxRepository), map[string]INotificationChannel{
+				model.NotificationChannelInApp: NewInAppChannel(notificationRepo, realtime.NewMemoryBroker()),
+				model.NotificationChannelPush:  NewPushChannel(deviceRepo, map[string]push.IProvider{model.PlatformAndroid: fcm}),
+				model.NotificationChannelEmail: NewEmailChannel(mail),
+			})
+
+			err := dispatcher.Dispatch(ctx, "notification1", "user1", "reference1", tt.payload)
+			assert.NoError(t, err)
+
+			assert.Len(t, fcm.Sent(), tt.expectedPushes)
+			assert.Len(t, mail.Sent(), tt.expectedMails)
+
+			var deferred []string
+			for _, call := range deferredRepo.Calls {
+				deferred = append(deferred, call.Arguments.Get(1).(model.DeferredNotification).Channel)
+			}
+			assert.Equal(t, tt.expectedDeferred, deferred)
+
+			notificationRepo.AssertExpectations(t)
+			preferenceRepo.AssertExpectations(t)
+			deferredRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationDispatcher_DeliverDeferred(t *testing.T) {
+	ctx := context.Background()
+	transactor, txCtx := txTransactor(ctx)
+	due := model.DeferredNotification{
+		ID:             "deferred1",
+		NotificationID: "notification1",
+		ReferenceID:    "reference1",
+		UserID:         "user1",
+		Channel:        model.NotificationChannelPush,
+		Type:           model.NotificationTypeMatch,
+		Title:          "It's a match!",
+		Body:           "Congratulations! You matched",
+	}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IDeferredNotificationRepository, *mocks.IOutboxRepository)
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(dr *mocks.IDeferredNotificationRepository, or *mocks.IOutboxRepository) {
+				dr.On("FindDue", ctx, "", mock.AnythingOfType("time.Time"), deferredNotificationBatchSize).Return([]model.DeferredNotification{due}, nil)
+				dr.On("Delete", txCtx, "deferred1").Return(true, nil)
+				or.On("Create", txCtx, mock.MatchedBy(func(message model.OutboxMessage) bool {
+					var deferred model.DeferredNotification
+					return message.Topic == model.OutboxTopicDeferredNotification && message.ReferenceID == "reference1" &&
+						json.Unmarshal([]byte(message.Payload), &deferred) == nil && deferred == due
+				})).Return(nil)
+			},
+		},
+		{
+			name: "Success - Already Queued By Another Run",
+			setupMocks: func(dr *mocks.IDeferredNotificationRepository, or *mocks.IOutboxRepository) {
+				dr.On("FindDue", ctx, "", mock.AnythingOfType("time.Time"), deferredNotificationBatchSize).Return([]model.DeferredNotification{due}, nil)
+				dr.On("Delete", txCtx, "deferred1").Return(false, nil)
+			},
+		},
+		{
+			name: "Success - Stays Deferred When It Can't Be Queued",
+			setupMocks: func(dr *mocks.IDeferredNotificationRepository, or *mocks.IOutboxRepository) {
+				dr.On("FindDue", ctx, "", mock.AnythingOfType("time.Time"), deferredNotificationBatchSize).Return([]model.DeferredNotification{due}, nil)
+				dr.On("Delete", txCtx, "deferred1").Return(true, nil)
+				// rolls the delete back
+				or.On("Create", txCtx, mock.AnythingOfType("model.OutboxMessage")).Return(errors.New("db error"))
+			},
+		},
+		{
+			name: "Error - DB Error",
+			setupMocks: func(dr *mocks.IDeferredNotificationRepository, or *mocks.IOutboxRepository) {
+				dr.On("FindDue", ctx, "", mock.AnythingOfType("time.Time"), deferredNotificationBatchSize).Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("failed to find deferred notifications"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deferredRepo, outboxRepo := new(mocks.IDeferredNotificationRepository), new(mocks.IOutboxRepository)
+			tt.setupMocks(deferredRepo, outboxRepo)
+
+			dispatcher := NewNotificationDispatcher(transactor, new(mocks.IUserRepository), new(mocks.INotificationPreferenceRepository), deferredRepo, outboxRepo, map[string]INotificationChannel{})
+
+			err := dispatcher.DeliverDeferred(ctx)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			deferredRepo.AssertExpectations(t)
+			outboxRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationDispatcher_SendDeferred(t *testing.T) {
+	ctx := context.Background()
+	recipient := &model.User{ID: "user1", Email: "ana@example.com"}
+	due := model.DeferredNotification{
+		ID:             "deferred1",
+		NotificationID: "notification1",
+		UserID:         "user1",
+		Channel:        model.NotificationChannelPush,
+		Type:           model.NotificationTypeMatch,
+		Title:          "It's a match!",
+		Body:           "Congratulations! You matched",
+	}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*mocks.IUserRepository, *mocks.INotificationPreferenceRepository)
+		expectedError  error
+		expectedPushes []push.Message
+	}{
+		{
+			name: "Success",
+			setupMocks: func(ur *mocks.IUserRepository, pr *mocks.INotificationPreferenceRepository) {
+				ur.On("FindByID", ctx, "user1").Return(recipient, nil)
+				pr.On("FindByUserID", ctx, "user1").Return(nil, nil)
+			},
+			expectedPushes: []push.Message{{
+				Token: "android",
+				Title: "It's a match!",
+				Body:  "Congratulations! You matched",
+				Data:  map[string]string{"notification_id": "notification1", "type": model.NotificationTypeMatch},
+			}},
+		},
+		{
+			name: "Success - Turned Off While It Waited",
+			setupMocks: func(ur *mocks.IUserRepository, pr *mocks.INotificationPreferenceRepository) {
+				ur.On("FindByID", ctx, "user1").Return(recipient, nil)
+				pr.On("FindByUserID", ctx, "user1").Return([]model.NotificationPreference{
+					{UserID: "user1", Type: model.NotificationTypeMatch, Channel: model.NotificationChannelPush, Enabled: false},
+				}, nil)
+			},
+		},
+		{
+			name: "Error - Recipient Not Loaded Is Retried",
+			setupMocks: func(ur *mocks.IUserRepository, pr *mocks.INotificationPreferenceRepository) {
+				ur.On("FindByID", ctx, "user1").Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo, preferenceRepo := new(mocks.IUserRepository), new(mocks.INotificationPreferenceRepository)
+			tt.setupMocks(userRepo, preferenceRepo)
+
+			deviceRepo := new(mocks.IDeviceRepository)
+			deviceRepo.On("FindByUserID", ctx, "user1").Return([]model.Device{{UserID: "user1", Platform: model.PlatformAndroid, Token: "android"}}, nil).Maybe()
+
+			fcm := push.NewFake()
+			dispatcher := NewNotificationDispatcher(passthroughTransactor(), userRepo, preferenceRepo, new(mocks.IDeferredNotificationRepository), new(mocks.IOutboxRepository), map[string]INotificationChannel{
+				model.NotificationChannelPush: NewPushChannel(deviceRepo, map[string]push.IProvider{model.PlatformAndroid: fcm}),
+			})
+
+			err := dispatcher.SendDeferred(ctx, due)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedPushes, fcm.Sent())
+
+			userRepo.AssertExpectations(t)
+			preferenceRepo.AssertExpectations(t)
+		})
+	}
+}
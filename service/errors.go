@@ -0,0 +1,58 @@
+package service
+
+import "errors"
+
+var (
+	ErrMatchNotFound    = errors.New("match not found")
+	ErrRewindNotAllowed = errors.New("rewind is not available on your plan")
+	ErrNothingToRewind  = errors.New("there is no recent swipe to rewind")
+
+	ErrStartOverNotAllowed = errors.New("start over is not available on your plan")
+	ErrSeeLikesNotAllowed  = errors.New("seeing who liked you is not available on your plan")
+
+	ErrCannotSwipeSelf        = errors.New("you can't swipe on yourself")
+	ErrSwipeTargetNotFound    = errors.New("the profile you swiped on doesn't exist")
+	ErrSwipeTargetNotEligible = errors.New("the profile you swiped on isn't in your deck")
+	ErrInvalidDeckToken       = errors.New("invalid or expired deck token")
+
+	ErrAlreadySwiped      = errors.New("user has already swiped")
+	ErrNoSuperLikesLeft   = errors.New("no super likes left. please purchase more super likes")
+	ErrSwipeLimitReached  = errors.New("daily swipe limit reached. please try again tomorrow")
+	ErrRewindLimitReached = errors.New("daily rewind limit reached. please try again tomorrow")
+
+	ErrCommentNotAllowed = errors.New("only likes can carry a comment")
+	ErrCommentRejected   = errors.New("your comment doesn't follow our community guidelines")
+	ErrInvalidLikeTarget = errors.New("the liked photo isn't on this profile")
+
+	ErrMessageNotFound = errors.New("message not found")
+	ErrEmptyMessage    = errors.New("message can't be empty")
+	ErrMessageRejected = errors.New("your message doesn't follow our community guidelines")
+	ErrNotYourMove     = errors.New("the other person has to send the first message")
+
+	ErrExtendNotAllowed     = errors.New("extending matches is not available on your plan")
+	ErrMatchNotExpiring     = errors.New("this match doesn't expire")
+	ErrMatchAlreadyExtended = errors.New("this match was already extended")
+
+	ErrEmptyAttachment       = errors.New("attachment can't be empty")
+	ErrAttachmentTooLarge    = errors.New("attachment is too large")
+	ErrVoiceNoteTooLong      = errors.New("voice note is too long")
+	ErrUnsupportedAttachment = errors.New("unsupported attachment type")
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrInvalidAttachmentURL  = errors.New("attachment link is invalid or has expired")
+
+	ErrTeaserNotFound   = errors.New("teaser not found")
+	ErrInvalidTeaserURL = errors.New("teaser link is invalid or has expired")
+
+	ErrInvalidDateRange = errors.New("the end date can't be before the start date")
+
+	ErrInvalidLastEventID   = errors.New("invalid last event id")
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrDeviceNotFound       = errors.New("device not found")
+
+	ErrNotificationPreferenceNotAllowed = errors.New("this notification can't be turned off on that channel")
+
+	ErrPaymentsDisabled = errors.New("purchases are not available right now")
+
+	ErrBoostActive  = errors.New("a boost is already active")
+	ErrNoBoostsLeft = errors.New("no boosts left. please purchase more boosts")
+)
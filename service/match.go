@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/marvelalexius/jones/config"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/utils/logger"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// matchExpiryBatchSize is how many matches the expiry job ends per query.
+const matchExpiryBatchSize = 500
+
+type (
+	MatchService struct {
+		Conf             *config.Config
+		Transactor       repository.ITransactor
+		MatchRepo        repository.IMatchRepository
+		ReactionRepo     repository.IReactionRepository
+		MessageRepo      repository.IMessageRepository
+		NotificationRepo repository.INotificationRepository
+		QuotaService     IQuotaService
+		OutboxRepo       repository.IOutboxRepository
+		DeferredRepo     repository.IDeferredNotificationRepository
+	}
+
+	IMatchService interface {
+		FindAll(ctx context.Context, userID string, pagination model.PaginationRequest) (matches []model.MatchResponse, total int64, err error)
+		FindByID(ctx context.Context, userID, id string) (model.MatchResponse, error)
+		Unmatch(ctx context.Context, userID, id string, req model.UnmatchRequest) error
+		Extend(ctx context.Context, userID, id string) (model.MatchResponse, error)
+		ExpireDue(ctx context.Context) error
+	}
+)
+
+func NewMatchService(conf *config.Config, transactor repository.ITransactor, matchRepo repository.IMatchRepository, reactionRepo repository.IReactionRepository, messageRepo repository.IMessageRepository, notificationRepo repository.INotificationRepository, quotaService IQuotaService, outboxRepo repository.IOutboxRepository, deferredRepo repository.IDeferredNotificationRepository) IMatchService {
+	return &MatchService{Conf: conf, Transactor: transactor, MatchRepo: matchRepo, ReactionRepo: reactionRepo, MessageRepo: messageRepo, NotificationRepo: notificationRepo, QuotaService: quotaService, OutboxRepo: outboxRepo, DeferredRepo: deferredRepo}
+}
+
+func (s *MatchService) FindAll(ctx context.Context, userID string, pagination model.PaginationRequest) ([]model.MatchResponse, int64, error) {
+	pagination.Normalize()
+
+	matches, total, err := s.MatchRepo.FindByUserID(ctx, userID, pagination)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find matches", err)
+
+		return nil, 0, errors.New("failed to find matches")
+	}
+
+	res := make([]model.MatchResponse, 0, len(matches))
+	for _, match := range matches {
+		res = append(res, match.ToMatchResponse(userID))
+	}
+
+	return res, total, nil
+}
+
+func (s *MatchService) FindByID(ctx context.Context, userID, id string) (model.MatchResponse, error) {
+	match, err := findParticipantMatch(ctx, s.MatchRepo, userID, id)
+	if err != nil {
+		return model.MatchResponse{}, err
+	}
+
+	comments, err := s.ReactionRepo.FindComments(ctx, match.UserID, match.MatchedUserID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find like comments", err)
+
+		return model.MatchResponse{}, errors.New("failed to find match")
+	}
+
+	res := match.ToMatchResponse(userID)
+	for _, comment := range comments {
+		res.Comments = append(res.Comments, comment.ToLikeComment())
+	}
+
+	return res, nil
+}
+
+func (s *MatchService) Unmatch(ctx context.Context, userID, id string, req model.UnmatchRequest) error {
+	match, err := findParticipantMatch(ctx, s.MatchRepo, userID, id)
+	if err != nil {
+		return err
+	}
+
+	match.Unmatch(userID, req, time.Now())
+
+	err = s.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		err := s.MatchRepo.Update(ctx, match)
+		if err != nil {
+			logger.Errorln(ctx, "failed to unmatch", err)
+
+			return errors.New("failed to unmatch")
+		}
+
+		return s.dropNotifications(ctx, match.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"match_id": match.ID,
+		"user_id":  userID,
+		"reason":   req.Reason,
+	}).Info("match unmatched")
+
+	return nil
+}
+
+// dropNotifications takes back everything either user was told about the match and its messages, sent or not.
+func (s *MatchService) dropNotifications(ctx context.Context, matchID string) error {
+	messageIDs, err := s.MessageRepo.FindIDsByMatchID(ctx, matchID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find match messages", err)
+
+		return errors.New("failed to unmatch")
+	}
+
+	referenceIDs := append([]string{matchID}, messageIDs...)
+
+	err = s.NotificationRepo.DeleteByReferenceIDs(ctx, referenceIDs)
+	if err != nil {
+		logger.Errorln(ctx, "failed to delete match notifications", err)
+
+		return errors.New("failed to unmatch")
+	}
+
+	err = s.OutboxRepo.DeleteByReferenceIDs(ctx, referenceIDs)
+	if err != nil {
+		logger.Errorln(ctx, "failed to delete pending match notifications", err)
+
+		return errors.New("failed to unmatch")
+	}
+
+	err = s.DeferredRepo.DeleteByReferenceIDs(ctx, referenceIDs)
+	if err != nil {
+		logger.Errorln(ctx, "failed to delete deferred match notifications", err)
+
+		return errors.New("failed to unmatch")
+	}
+
+	return nil
+}
+
+// Extend gives a match that's waiting for its first message another window, once, for plans with the feature.
+func (s *MatchService) Extend(ctx context.Context, userID, id string) (model.MatchResponse, error) {
+	plan, err := s.QuotaService.FindPlan(ctx, userID)
+	if err != nil {
+		return model.MatchResponse{}, err
+	}
+
+	if !model.HasFeature(plan.Name, model.FeatureExtendMatch) {
+		return model.MatchResponse{}, ErrExtendNotAllowed
+	}
+
+	match, err := findParticipantMatch(ctx, s.MatchRepo, userID, id)
+	if err != nil {
+		return model.MatchResponse{}, err
+	}
+
+	if match.ExpiresAt == nil || match.FirstMessageAt != nil {
+		return model.MatchResponse{}, ErrMatchNotExpiring
+	}
+
+	if match.ExtendedAt != nil {
+		return model.MatchResponse{}, ErrMatchAlreadyExtended
+	}
+
+	now := time.Now()
+	match.Extend(s.Conf.Matches.ExpiresAfter, now)
+
+	extended, err := s.MatchRepo.Extend(ctx, match, now)
+	if err != nil {
+		logger.Errorln(ctx, "failed to extend match", err)
+
+		return model.MatchResponse{}, errors.New("failed to extend match")
+	}
+
+	// the other participant extended it, wrote to it or it expired in the meantime
+	if !extended {
+		return model.MatchResponse{}, ErrMatchNotExpiring
+	}
+
+	return match.ToMatchResponse(userID), nil
+}
+
+// ExpireDue ends the matches nobody wrote to in time and lets both participants know.
+func (s *MatchService) ExpireDue(ctx context.Context) error {
+	now := time.Now()
+
+	lastID := ""
+	for {
+		matches, err := s.MatchRepo.FindExpiring(ctx, lastID, now, matchExpiryBatchSize)
+		if err != nil {
+			logger.Errorln(ctx, "failed to find expiring matches", err)
+
+			return errors.New("failed to find expiring matches")
+		}
+
+		for _, match := range matches {
+			err = s.expire(ctx, match, now)
+			if err != nil {
+				logger.Errorln(ctx, "failed to expire match", match.ID, err)
+			}
+		}
+
+		if len(matches) < matchExpiryBatchSize {
+			break
+		}
+
+		lastID = matches[len(matches)-1].ID
+	}
+
+	return nil
+}
+
+// expire ends the match and notifies both participants in one transaction.
+func (s *MatchService) expire(ctx context.Context, match model.Match, now time.Time) error {
+	return s.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		expired, err := s.MatchRepo.Expire(ctx, match.ID, now)
+		if err != nil {
+			return err
+		}
+
+		// a message made it in since the batch was loaded
+		if !expired {
+			return nil
+		}
+
+		for _, userID := range []string{match.UserID, match.MatchedUserID} {
+			payload := model.MatchExpiredPayload{MatchID: match.ID, UserID: match.OtherUserID(userID)}
+
+			err = enqueueNotification(ctx, s.OutboxRepo, userID, match.ID, payload)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// findParticipantMatch finds an active match and makes sure the given user is part of it.
+func findParticipantMatch(ctx context.Context, matchRepo repository.IMatchRepository, userID, id string) (*model.Match, error) {
+	match, err := matchRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMatchNotFound
+		}
+
+		logger.Errorln(ctx, "failed to find match", err)
+
+		return nil, errors.New("failed to find match")
+	}
+
+	if !match.HasParticipant(userID) || match.Status != model.MatchStatusActive || match.IsExpired(time.Now()) {
+		return nil, ErrMatchNotFound
+	}
+
+	return match, nil
+}
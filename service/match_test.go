@@ -0,0 +1,462 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marvelalexius/jones/config"
+	"github.com/marvelalexius/jones/mocks"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/realtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestMatchService_FindAll(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		userID        string
+		pagination    model.PaginationRequest
+		setupMocks    func(*mocks.IMatchRepository)
+		expected      []model.MatchResponse
+		expectedTotal int64
+		expectedError error
+	}{
+		{
+			name:   "Success - Shows The Other User's Profile",
+			userID: "user1",
+			setupMocks: func(mr *mocks.IMatchRepository) {
+				matches := []model.Match{
+					{
+						ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive, MatchedAt: now,
+						User:        model.User{ID: "user1", Name: "Me"},
+						MatchedUser: model.User{ID: "user2", Name: "Jane", Images: []model.Image{{ID: 1, URL: "jane.jpg", IsPrimary: true}}},
+					},
+					{
+						ID: "match2", UserID: "user0", MatchedUserID: "user1", Status: model.MatchStatusActive, MatchedAt: now,
+						User:        model.User{ID: "user0", Name: "Anna"},
+						MatchedUser: model.User{ID: "user1", Name: "Me"},
+					},
+				}
+				mr.On("FindByUserID", mock.Anything, "user1", model.PaginationRequest{Page: 1, Limit: model.DefaultPageLimit}).Return(matches, int64(2), nil)
+			},
+			expected: []model.MatchResponse{
+				{ID: "match1", Status: model.MatchStatusActive, MatchedAt: now, User: model.MatchProfile{ID: "user2", Name: "Jane", Photo: &model.Image{ID: 1, URL: "jane.jpg", IsPrimary: true}}},
+				{ID: "match2", Status: model.MatchStatusActive, MatchedAt: now, User: model.MatchProfile{ID: "user0", Name: "Anna"}},
+			},
+			expectedTotal: 2,
+		},
+		{
+			name:       "Error - Find Matches",
+			userID:     "user1",
+			pagination: model.PaginationRequest{Page: 2, Limit: 10},
+			setupMocks: func(mr *mocks.IMatchRepository) {
+				mr.On("FindByUserID", mock.Anything, "user1", model.PaginationRequest{Page: 2, Limit: 10}).Return(nil, int64(0), gorm.ErrInvalidDB)
+			},
+			expectedError: errors.New("failed to find matches"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			tt.setupMocks(matchRepo)
+
+			service := NewMatchService(&config.Config{}, passthroughTransactor(), matchRepo, new(mocks.IReactionRepository), new(mocks.IMessageRepository), new(mocks.INotificationRepository), new(mocks.IQuotaService), testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+			matches, total, err := service.FindAll(ctx, tt.userID, tt.pagination)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, matches)
+				assert.Equal(t, tt.expectedTotal, total)
+			}
+
+			matchRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMatchService_FindByID(t *testing.T) {
+	ctx := context.Background()
+	comment, targetType, targetID := "Where was this taken?", model.LikeTargetImage, "7"
+
+	tests := []struct {
+		name             string
+		userID           string
+		matchID          string
+		setupMocks       func(*mocks.IMatchRepository, *mocks.IReactionRepository)
+		expectedComments []model.LikeComment
+		expectedError    error
+	}{
+		{
+			name:    "Success - Participant",
+			userID:  "user2",
+			matchID: "match1",
+			setupMocks: func(mr *mocks.IMatchRepository, rr *mocks.IReactionRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive, User: model.User{ID: "user1"}}, nil)
+				rr.On("FindComments", mock.Anything, "user1", "user2").Return([]model.Reaction{}, nil)
+			},
+		},
+		{
+			name:    "Success - With Like Comments",
+			userID:  "user2",
+			matchID: "match1",
+			setupMocks: func(mr *mocks.IMatchRepository, rr *mocks.IReactionRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive, User: model.User{ID: "user1"}}, nil)
+				rr.On("FindComments", mock.Anything, "user1", "user2").Return([]model.Reaction{
+					{ID: "reaction1", UserID: "user1", MatchedUserID: "user2", Type: model.ReactionLike, Comment: &comment, TargetType: &targetType, TargetID: &targetID},
+				}, nil)
+			},
+			expectedComments: []model.LikeComment{
+				{UserID: "user1", Comment: "Where was this taken?", TargetType: &targetType, TargetID: &targetID},
+			},
+		},
+		{
+			name:    "Error - Not A Participant",
+			userID:  "user3",
+			matchID: "match1",
+			setupMocks: func(mr *mocks.IMatchRepository, rr *mocks.IReactionRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}, nil)
+			},
+			expectedError: ErrMatchNotFound,
+		},
+		{
+			name:    "Error - Unmatched",
+			userID:  "user1",
+			matchID: "match1",
+			setupMocks: func(mr *mocks.IMatchRepository, rr *mocks.IReactionRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusUnmatched}, nil)
+			},
+			expectedError: ErrMatchNotFound,
+		},
+		{
+			name:    "Error - Not Found",
+			userID:  "user1",
+			matchID: "missing",
+			setupMocks: func(mr *mocks.IMatchRepository, rr *mocks.IReactionRepository) {
+				mr.On("FindByID", mock.Anything, "missing").Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedError: ErrMatchNotFound,
+		},
+		{
+			name:    "Error - Find Match",
+			userID:  "user1",
+			matchID: "match1",
+			setupMocks: func(mr *mocks.IMatchRepository, rr *mocks.IReactionRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(nil, gorm.ErrInvalidDB)
+			},
+			expectedError: errors.New("failed to find match"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			reactionRepo := new(mocks.IReactionRepository)
+			tt.setupMocks(matchRepo, reactionRepo)
+
+			service := NewMatchService(&config.Config{}, passthroughTransactor(), matchRepo, reactionRepo, new(mocks.IMessageRepository), new(mocks.INotificationRepository), new(mocks.IQuotaService), testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+			match, err := service.FindByID(ctx, tt.userID, tt.matchID)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.matchID, match.ID)
+				assert.Equal(t, "user1", match.User.ID)
+				assert.Equal(t, tt.expectedComments, match.Comments)
+			}
+
+			matchRepo.AssertExpectations(t)
+			reactionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMatchService_Unmatch(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		userID        string
+		request       model.UnmatchRequest
+		setupMocks    func(*mocks.IMatchRepository)
+		expectedError error
+	}{
+		{
+			name:    "Success - Unmatch With Reason",
+			userID:  "user2",
+			request: model.UnmatchRequest{Reason: "NO_CHEMISTRY", Detail: "we didn't click"},
+			setupMocks: func(mr *mocks.IMatchRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}, nil)
+				mr.On("Update", mock.Anything, mock.MatchedBy(func(match *model.Match) bool {
+					return match.Status == model.MatchStatusUnmatched &&
+						match.UnmatchedAt != nil &&
+						*match.UnmatchedBy == "user2" &&
+						*match.UnmatchReason == "NO_CHEMISTRY" &&
+						*match.UnmatchDetail == "we didn't click"
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "Success - Unmatch Without Reason",
+			userID: "user1",
+			setupMocks: func(mr *mocks.IMatchRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}, nil)
+				mr.On("Update", mock.Anything, mock.MatchedBy(func(match *model.Match) bool {
+					return match.Status == model.MatchStatusUnmatched && match.UnmatchReason == nil
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "Error - Already Unmatched",
+			userID: "user1",
+			setupMocks: func(mr *mocks.IMatchRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusUnmatched}, nil)
+			},
+			expectedError: ErrMatchNotFound,
+		},
+		{
+			name:   "Error - Update Match",
+			userID: "user1",
+			setupMocks: func(mr *mocks.IMatchRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}, nil)
+				mr.On("Update", mock.Anything, mock.Anything).Return(gorm.ErrInvalidDB)
+			},
+			expectedError: errors.New("failed to unmatch"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			tt.setupMocks(matchRepo)
+
+			referenceIDs := []string{"match1", "message1"}
+			messageRepo := new(mocks.IMessageRepository)
+			messageRepo.On("FindIDsByMatchID", mock.MatchedBy(inTransaction), "match1").Return([]string{"message1"}, nil).Maybe()
+			notificationRepo := new(mocks.INotificationRepository)
+			notificationRepo.On("DeleteByReferenceIDs", mock.MatchedBy(inTransaction), referenceIDs).Return(nil).Maybe()
+			deferredRepo := new(mocks.IDeferredNotificationRepository)
+			deferredRepo.On("DeleteByReferenceIDs", mock.MatchedBy(inTransaction), referenceIDs).Return(nil).Maybe()
+
+			// still waiting to be sent, about the match, one of its messages and another match
+			other := model.OutboxMessage{ID: "outbox3", ReferenceID: "match2"}
+			outbox := &txOutbox{committed: []model.OutboxMessage{{ID: "outbox1", ReferenceID: "match1"}, {ID: "outbox2", ReferenceID: "message1"}, other}}
+
+			service := NewMatchService(&config.Config{}, outbox, matchRepo, new(mocks.IReactionRepository), messageRepo, notificationRepo, new(mocks.IQuotaService), outbox, deferredRepo)
+			err := service.Unmatch(ctx, tt.userID, "match1", tt.request)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+				assert.Len(t, outbox.committed, 3)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []model.OutboxMessage{other}, outbox.committed)
+				notificationRepo.AssertCalled(t, "DeleteByReferenceIDs", mock.Anything, referenceIDs)
+				deferredRepo.AssertCalled(t, "DeleteByReferenceIDs", mock.Anything, referenceIDs)
+			}
+
+			matchRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMatchService_Extend(t *testing.T) {
+	ctx := context.Background()
+	conf := &config.Config{Matches: config.Matches{ExpiresAfter: 24 * time.Hour}}
+	expiresAt := time.Now().Add(time.Hour)
+	extendedAt := time.Now().Add(-time.Hour)
+	firstMessageAt := time.Now()
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IMatchRepository, *mocks.IQuotaService)
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(mr *mocks.IMatchRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(&model.SubscriptionPlan{Name: model.SubscriptionPlanPro}, nil)
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive, ExpiresAt: &expiresAt}, nil)
+				mr.On("Extend", mock.Anything, mock.MatchedBy(func(match *model.Match) bool {
+					return match.ExpiresAt.Equal(expiresAt.Add(24*time.Hour)) && match.ExtendedAt != nil
+				}), mock.AnythingOfType("time.Time")).Return(true, nil)
+			},
+		},
+		{
+			name: "Error - Changed Since It Was Read",
+			setupMocks: func(mr *mocks.IMatchRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(&model.SubscriptionPlan{Name: model.SubscriptionPlanPro}, nil)
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive, ExpiresAt: &expiresAt}, nil)
+				mr.On("Extend", mock.Anything, mock.Anything, mock.AnythingOfType("time.Time")).Return(false, nil)
+			},
+			expectedError: ErrMatchNotExpiring,
+		},
+		{
+			name: "Error - Free Plan",
+			setupMocks: func(mr *mocks.IMatchRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(&model.SubscriptionPlan{Name: model.SubscriptionPlanFree}, nil)
+			},
+			expectedError: ErrExtendNotAllowed,
+		},
+		{
+			name: "Error - Already Talking",
+			setupMocks: func(mr *mocks.IMatchRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(&model.SubscriptionPlan{Name: model.SubscriptionPlanPro}, nil)
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive, FirstMessageAt: &firstMessageAt}, nil)
+			},
+			expectedError: ErrMatchNotExpiring,
+		},
+		{
+			name: "Error - Already Extended",
+			setupMocks: func(mr *mocks.IMatchRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(&model.SubscriptionPlan{Name: model.SubscriptionPlanPro}, nil)
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive, ExpiresAt: &expiresAt, ExtendedAt: &extendedAt}, nil)
+			},
+			expectedError: ErrMatchAlreadyExtended,
+		},
+		{
+			name: "Error - Update Match",
+			setupMocks: func(mr *mocks.IMatchRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(&model.SubscriptionPlan{Name: model.SubscriptionPlanPro}, nil)
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive, ExpiresAt: &expiresAt}, nil)
+				mr.On("Extend", mock.Anything, mock.Anything, mock.AnythingOfType("time.Time")).Return(false, gorm.ErrInvalidDB)
+			},
+			expectedError: errors.New("failed to extend match"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			quotaService := new(mocks.IQuotaService)
+			tt.setupMocks(matchRepo, quotaService)
+
+			service := NewMatchService(conf, passthroughTransactor(), matchRepo, new(mocks.IReactionRepository), new(mocks.IMessageRepository), new(mocks.INotificationRepository), quotaService, testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+			_, err := service.Extend(ctx, "user1", "match1")
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			matchRepo.AssertExpectations(t)
+			quotaService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMatchService_ExpireDue(t *testing.T) {
+	ctx := context.Background()
+	matches := []model.Match{
+		{ID: "match1", UserID: "user1", MatchedUserID: "user2"},
+		{ID: "match2", UserID: "user3", MatchedUserID: "user4"},
+	}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IMatchRepository, *mocks.INotificationRepository)
+		expectedError error
+	}{
+		{
+			name: "Success - Both Participants Are Told",
+			setupMocks: func(mr *mocks.IMatchRepository, nr *mocks.INotificationRepository) {
+				mr.On("FindExpiring", mock.Anything, "", mock.Anything, matchExpiryBatchSize).Return(matches, nil)
+				mr.On("Expire", mock.Anything, "match1", mock.Anything).Return(true, nil)
+				// someone wrote to match2 after the batch was loaded
+				mr.On("Expire", mock.Anything, "match2", mock.Anything).Return(false, nil)
+				nr.On("Create", mock.MatchedBy(func(n *model.Notification) bool {
+					return n.ReferenceID == "match1" && (n.UserID == "user1" || n.UserID == "user2")
+				})).Return(true, nil).Twice()
+			},
+		},
+		{
+			name: "Error - Find Expiring",
+			setupMocks: func(mr *mocks.IMatchRepository, nr *mocks.INotificationRepository) {
+				mr.On("FindExpiring", mock.Anything, "", mock.Anything, matchExpiryBatchSize).Return(nil, gorm.ErrInvalidDB)
+			},
+			expectedError: errors.New("failed to find expiring matches"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			notificationRepo := new(mocks.INotificationRepository)
+			tt.setupMocks(matchRepo, notificationRepo)
+
+			service := NewMatchService(&config.Config{}, passthroughTransactor(), matchRepo, new(mocks.IReactionRepository), new(mocks.IMessageRepository), new(mocks.INotificationRepository), new(mocks.IQuotaService), testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), notificationRepo, realtime.NewMemoryBroker()), noDeferred())
+			err := service.ExpireDue(ctx)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			matchRepo.AssertExpectations(t)
+			notificationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMatchService_ExpireDue_Outbox(t *testing.T) {
+	ctx := context.Background()
+	match := model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2"}
+
+	tests := []struct {
+		name         string
+		failAt       int
+		expectedSent []string
+	}{
+		{
+			name:         "Success - Both Participants Are Told Once It Commits",
+			expectedSent: []string{"user1", "user2"},
+		},
+		{
+			// the match stays active and is expired again on the next run
+			name:   "Success - Nothing Is Sent When The Transaction Fails",
+			failAt: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			matchRepo.On("FindExpiring", mock.Anything, "", mock.Anything, matchExpiryBatchSize).Return([]model.Match{match}, nil)
+			matchRepo.On("Expire", mock.MatchedBy(inTransaction), "match1", mock.Anything).Return(true, nil).Once()
+
+			outbox := &txOutbox{failAt: tt.failAt}
+			service := NewMatchService(&config.Config{}, outbox, matchRepo, new(mocks.IReactionRepository), new(mocks.IMessageRepository), new(mocks.INotificationRepository), new(mocks.IQuotaService), outbox, noDeferred())
+			err := service.ExpireDue(ctx)
+			assert.NoError(t, err)
+
+			var sent []string
+			for _, message := range outbox.committed {
+				var notification model.NotificationMessage
+				assert.NoError(t, json.Unmarshal([]byte(message.Payload), &notification))
+				assert.Equal(t, model.NotificationTypeMatchExpired, notification.Type)
+				sent = append(sent, notification.UserID)
+			}
+			assert.Equal(t, tt.expectedSent, sent)
+
+			matchRepo.AssertExpectations(t)
+		})
+	}
+}
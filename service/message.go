@@ -0,0 +1,575 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/blob"
+	"github.com/marvelalexius/jones/pkg/moderation"
+	"github.com/marvelalexius/jones/pkg/realtime"
+	"github.com/marvelalexius/jones/pkg/signedurl"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/utils/logger"
+	"gorm.io/gorm"
+)
+
+type (
+	MessageService struct {
+		Transactor       repository.ITransactor
+		MatchRepo        repository.IMatchRepository
+		ConversationRepo repository.IConversationRepository
+		MessageRepo      repository.IMessageRepository
+		AttachmentRepo   repository.IAttachmentRepository
+		ReactionRepo     repository.IReactionRepository
+		NotificationRepo repository.INotificationRepository
+		Moderator        moderation.IModerator
+		Storage          blob.IStorage
+		URLSigner        signedurl.ISigner
+		Publisher        realtime.IPublisher
+		OutboxRepo       repository.IOutboxRepository
+		DeferredRepo     repository.IDeferredNotificationRepository
+	}
+
+	IMessageService interface {
+		FindConversations(ctx context.Context, userID string, pagination model.PaginationRequest) ([]model.ConversationResponse, int64, error)
+		FindMessages(ctx context.Context, userID, matchID string, pagination model.PaginationRequest) ([]model.Message, int64, error)
+		Send(ctx context.Context, userID, matchID string, req model.SendMessageRequest) (model.Message, error)
+		SendAttachment(ctx context.Context, userID, matchID string, req model.SendAttachmentRequest, file io.Reader, size int64) (model.Message, error)
+		OpenAttachment(ctx context.Context, id string, query url.Values) (*model.Attachment, io.ReadCloser, error)
+		Delete(ctx context.Context, userID, matchID, messageID string) error
+		Typing(ctx context.Context, userID, matchID string) error
+		MarkRead(ctx context.Context, userID, matchID string) error
+	}
+)
+
+func NewMessageService(transactor repository.ITransactor, matchRepo repository.IMatchRepository, conversationRepo repository.IConversationRepository, messageRepo repository.IMessageRepository, attachmentRepo repository.IAttachmentRepository, reactionRepo repository.IReactionRepository, notificationRepo repository.INotificationRepository, moderator moderation.IModerator, storage blob.IStorage, urlSigner signedurl.ISigner, publisher realtime.IPublisher, outboxRepo repository.IOutboxRepository, deferredRepo repository.IDeferredNotificationRepository) IMessageService {
+	return &MessageService{Transactor: transactor, MatchRepo: matchRepo, ConversationRepo: conversationRepo, MessageRepo: messageRepo, AttachmentRepo: attachmentRepo, ReactionRepo: reactionRepo, NotificationRepo: notificationRepo, Moderator: moderator, Storage: storage, URLSigner: urlSigner, Publisher: publisher, OutboxRepo: outboxRepo, DeferredRepo: deferredRepo}
+}
+
+// FindConversations lists the chats of the user's active matches with their last message and unread count.
+func (s *MessageService) FindConversations(ctx context.Context, userID string, pagination model.PaginationRequest) ([]model.ConversationResponse, int64, error) {
+	pagination.Normalize()
+
+	conversations, total, err := s.ConversationRepo.FindByUserID(ctx, userID, pagination)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find conversations", err)
+
+		return nil, 0, errors.New("failed to find conversations")
+	}
+
+	res := make([]model.ConversationResponse, 0, len(conversations))
+	if len(conversations) == 0 {
+		return res, total, nil
+	}
+
+	conversationIDs := make([]string, 0, len(conversations))
+	for _, conversation := range conversations {
+		conversationIDs = append(conversationIDs, conversation.ID)
+	}
+
+	latest, err := s.MessageRepo.FindLatest(ctx, conversationIDs)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find latest messages", err)
+
+		return nil, 0, errors.New("failed to find conversations")
+	}
+
+	unread, err := s.MessageRepo.CountUnread(ctx, userID, conversationIDs)
+	if err != nil {
+		logger.Errorln(ctx, "failed to count unread messages", err)
+
+		return nil, 0, errors.New("failed to find conversations")
+	}
+
+	now := time.Now()
+	for _, conversation := range conversations {
+		var lastMessage *model.Message
+		if message, ok := latest[conversation.ID]; ok {
+			message = s.signAttachments(message, userID, now)
+			lastMessage = &message
+		}
+
+		res = append(res, conversation.ToConversationResponse(userID, lastMessage, unread[conversation.ID]))
+	}
+
+	return res, total, nil
+}
+
+// FindMessages returns the history of a match's chat, newest first, and marks it as read.
+func (s *MessageService) FindMessages(ctx context.Context, userID, matchID string, pagination model.PaginationRequest) ([]model.Message, int64, error) {
+	pagination.Normalize()
+
+	match, err := findParticipantMatch(ctx, s.MatchRepo, userID, matchID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	conversation, err := s.ConversationRepo.FindByMatchID(ctx, match.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return []model.Message{}, 0, nil
+		}
+
+		logger.Errorln(ctx, "failed to find conversation", err)
+
+		return nil, 0, errors.New("failed to find messages")
+	}
+
+	messages, total, err := s.MessageRepo.FindByConversationID(ctx, conversation.ID, pagination)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find messages", err)
+
+		return nil, 0, errors.New("failed to find messages")
+	}
+
+	now := time.Now()
+	for i := range messages {
+		messages[i] = s.signAttachments(messages[i], userID, now)
+	}
+
+	err = s.markRead(ctx, match, conversation, userID, now)
+	if err != nil {
+		logger.Errorln(ctx, "failed to mark conversation as read", err)
+	}
+
+	return messages, total, nil
+}
+
+// Send posts a message to a match's chat, opening the conversation on the first message.
+func (s *MessageService) Send(ctx context.Context, userID, matchID string, req model.SendMessageRequest) (model.Message, error) {
+	match, err := findParticipantMatch(ctx, s.MatchRepo, userID, matchID)
+	if err != nil {
+		return model.Message{}, err
+	}
+
+	if !match.CanMessage(userID) {
+		return model.Message{}, ErrNotYourMove
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return model.Message{}, ErrEmptyMessage
+	}
+
+	allowed, err := s.Moderator.Allowed(ctx, body)
+	if err != nil {
+		logger.Errorln(ctx, "failed to moderate message", err)
+
+		return model.Message{}, errors.New("failed to moderate message")
+	}
+
+	if !allowed {
+		return model.Message{}, ErrMessageRejected
+	}
+
+	return s.deliver(ctx, match, userID, body, nil, time.Now())
+}
+
+// SendAttachment posts a photo or a voice note to a match's chat. The file is stored before the message,
+// and removed again when the message can't be sent.
+func (s *MessageService) SendAttachment(ctx context.Context, userID, matchID string, req model.SendAttachmentRequest, file io.Reader, size int64) (model.Message, error) {
+	match, err := findParticipantMatch(ctx, s.MatchRepo, userID, matchID)
+	if err != nil {
+		return model.Message{}, err
+	}
+
+	if !match.CanMessage(userID) {
+		return model.Message{}, ErrNotYourMove
+	}
+
+	if size <= 0 {
+		return model.Message{}, ErrEmptyAttachment
+	}
+
+	if size > model.MaxAttachmentSizes[req.Kind] {
+		return model.Message{}, ErrAttachmentTooLarge
+	}
+
+	if req.Kind == model.AttachmentVoice && (req.DurationMs <= 0 || time.Duration(req.DurationMs)*time.Millisecond > model.MaxVoiceDuration) {
+		return model.Message{}, ErrVoiceNoteTooLong
+	}
+
+	// the declared type can't be trusted, the content decides
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		logger.Errorln(ctx, "failed to read attachment", err)
+
+		return model.Message{}, errors.New("failed to read attachment")
+	}
+	head = head[:n]
+
+	contentType, ok := model.AttachmentContentTypes[req.Kind][http.DetectContentType(head)]
+	if !ok {
+		return model.Message{}, ErrUnsupportedAttachment
+	}
+
+	caption := strings.TrimSpace(req.Caption)
+	if caption != "" {
+		allowed, err := s.Moderator.Allowed(ctx, caption)
+		if err != nil {
+			logger.Errorln(ctx, "failed to moderate message", err)
+
+			return model.Message{}, errors.New("failed to moderate message")
+		}
+
+		if !allowed {
+			return model.Message{}, ErrMessageRejected
+		}
+	}
+
+	now := time.Now()
+	attachment := model.NewAttachment(match.ID, req.Kind, contentType, size, now)
+	if req.Kind == model.AttachmentVoice {
+		attachment.DurationMs = &req.DurationMs
+	}
+
+	err = s.Storage.Put(ctx, attachment.StorageKey, io.LimitReader(io.MultiReader(bytes.NewReader(head), file), size))
+	if err != nil {
+		logger.Errorln(ctx, "failed to store attachment", err)
+
+		return model.Message{}, errors.New("failed to store attachment")
+	}
+
+	message, err := s.deliver(ctx, match, userID, caption, &attachment, now)
+	if err != nil {
+		if err := s.Storage.Delete(ctx, attachment.StorageKey); err != nil {
+			logger.Errorln(ctx, "failed to remove orphaned attachment", err)
+		}
+
+		return model.Message{}, err
+	}
+
+	return message, nil
+}
+
+// OpenAttachment checks a signed attachment URL and opens the file for its viewer, who must still be matched
+// with the sender.
+func (s *MessageService) OpenAttachment(ctx context.Context, id string, query url.Values) (*model.Attachment, io.ReadCloser, error) {
+	path := (&model.Attachment{ID: id}).Path()
+
+	viewerID, err := s.URLSigner.Verify(path, query, time.Now())
+	if err != nil {
+		return nil, nil, ErrInvalidAttachmentURL
+	}
+
+	attachment, err := s.AttachmentRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+
+		logger.Errorln(ctx, "failed to find attachment", err)
+
+		return nil, nil, errors.New("failed to find attachment")
+	}
+
+	_, err = findParticipantMatch(ctx, s.MatchRepo, viewerID, attachment.MatchID)
+	if err != nil {
+		if errors.Is(err, ErrMatchNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+
+		return nil, nil, err
+	}
+
+	file, err := s.Storage.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, blob.ErrNotFound) {
+			return nil, nil, ErrAttachmentNotFound
+		}
+
+		logger.Errorln(ctx, "failed to open attachment", err)
+
+		return nil, nil, errors.New("failed to open attachment")
+	}
+
+	return attachment, file, nil
+}
+
+// deliver stores a message in the match's chat, opening the conversation on the first message, and tells
+// both participants about it.
+func (s *MessageService) deliver(ctx context.Context, match *model.Match, userID, body string, attachment *model.Attachment, now time.Time) (model.Message, error) {
+	var message model.Message
+	err := s.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		conversation, err := s.openConversation(ctx, match, now)
+		if err != nil {
+			return err
+		}
+
+		message = model.NewMessage(conversation.ID, userID, body, now)
+
+		err = s.MessageRepo.Create(ctx, message)
+		if err != nil {
+			logger.Errorln(ctx, "failed to create message", err)
+
+			return errors.New("failed to send message")
+		}
+
+		if match.FirstMessageAt == nil {
+			active, err := s.MatchRepo.MarkFirstMessage(ctx, match.ID, now)
+			if err != nil {
+				logger.Errorln(ctx, "failed to mark first message", err)
+
+				return errors.New("failed to send message")
+			}
+
+			// the match expired since it was loaded
+			if !active {
+				return ErrMatchNotFound
+			}
+		}
+
+		if attachment != nil {
+			attachment.MessageID = message.ID
+
+			err = s.AttachmentRepo.Create(ctx, *attachment)
+			if err != nil {
+				logger.Errorln(ctx, "failed to create attachment", err)
+
+				return errors.New("failed to send message")
+			}
+
+			message.Attachments = []model.Attachment{*attachment}
+		}
+
+		err = s.ConversationRepo.Touch(ctx, conversation.ID, now)
+		if err != nil {
+			logger.Errorln(ctx, "failed to update conversation", err)
+
+			return errors.New("failed to send message")
+		}
+
+		// the sender has read everything up to their own message
+		_, err = s.ConversationRepo.MarkRead(ctx, conversation, userID, now)
+		if err != nil {
+			logger.Errorln(ctx, "failed to mark conversation as read", err)
+
+			return errors.New("failed to send message")
+		}
+
+		payload := model.MessagePayload{MatchID: match.ID, MessageID: message.ID, UserID: message.SenderID}
+
+		return enqueueNotification(ctx, s.OutboxRepo, match.OtherUserID(userID), message.ID, payload)
+	})
+	if err != nil {
+		return model.Message{}, err
+	}
+
+	// the sender's other devices show the message too, attachment URLs are signed for each of them
+	recipientID := match.OtherUserID(userID)
+	publish(ctx, s.Publisher, realtime.EventMessage, recipientID, model.MessageEvent{MatchID: match.ID, Message: s.signAttachments(message, recipientID, now)})
+	publish(ctx, s.Publisher, realtime.EventMessage, userID, model.MessageEvent{MatchID: match.ID, Message: s.signAttachments(message, userID, now)})
+
+	return s.signAttachments(message, userID, now), nil
+}
+
+// signAttachments returns the message with attachment URLs only the given viewer can use.
+func (s *MessageService) signAttachments(message model.Message, viewerID string, now time.Time) model.Message {
+	if len(message.Attachments) == 0 {
+		return message
+	}
+
+	attachments := make([]model.Attachment, len(message.Attachments))
+	for i, attachment := range message.Attachments {
+		attachment.URL = s.URLSigner.Sign(attachment.Path(), viewerID, now)
+		attachments[i] = attachment
+	}
+	message.Attachments = attachments
+
+	return message
+}
+
+// Typing tells the other participant the user is writing, nothing is stored.
+func (s *MessageService) Typing(ctx context.Context, userID, matchID string) error {
+	match, err := findParticipantMatch(ctx, s.MatchRepo, userID, matchID)
+	if err != nil {
+		return err
+	}
+
+	publish(ctx, s.Publisher, realtime.EventTyping, match.OtherUserID(userID), model.TypingEvent{MatchID: match.ID, UserID: userID})
+
+	return nil
+}
+
+// MarkRead marks the match's chat as read and sends a read receipt to the other participant.
+func (s *MessageService) MarkRead(ctx context.Context, userID, matchID string) error {
+	match, err := findParticipantMatch(ctx, s.MatchRepo, userID, matchID)
+	if err != nil {
+		return err
+	}
+
+	conversation, err := s.ConversationRepo.FindByMatchID(ctx, match.ID)
+	if err != nil {
+		// nothing was said yet, so there's nothing to read
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+
+		logger.Errorln(ctx, "failed to find conversation", err)
+
+		return errors.New("failed to mark conversation as read")
+	}
+
+	err = s.markRead(ctx, match, conversation, userID, time.Now())
+	if err != nil {
+		logger.Errorln(ctx, "failed to mark conversation as read", err)
+
+		return errors.New("failed to mark conversation as read")
+	}
+
+	return nil
+}
+
+// Delete removes one of the user's own messages along with the notification it sent.
+func (s *MessageService) Delete(ctx context.Context, userID, matchID, messageID string) error {
+	match, err := findParticipantMatch(ctx, s.MatchRepo, userID, matchID)
+	if err != nil {
+		return err
+	}
+
+	conversation, err := s.ConversationRepo.FindByMatchID(ctx, match.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMessageNotFound
+		}
+
+		logger.Errorln(ctx, "failed to find conversation", err)
+
+		return errors.New("failed to delete message")
+	}
+
+	message, err := s.MessageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMessageNotFound
+		}
+
+		logger.Errorln(ctx, "failed to find message", err)
+
+		return errors.New("failed to delete message")
+	}
+
+	if message.ConversationID != conversation.ID || message.SenderID != userID {
+		return ErrMessageNotFound
+	}
+
+	return s.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		err := s.MessageRepo.Delete(ctx, message.ID, time.Now())
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete message", err)
+
+			return errors.New("failed to delete message")
+		}
+
+		err = s.NotificationRepo.DeleteByReferenceID(ctx, message.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete message notification", err)
+
+			return errors.New("failed to delete message")
+		}
+
+		err = s.OutboxRepo.DeleteByReferenceID(ctx, message.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete pending message notification", err)
+
+			return errors.New("failed to delete message")
+		}
+
+		err = s.DeferredRepo.DeleteByReferenceID(ctx, message.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete deferred message notification", err)
+
+			return errors.New("failed to delete message")
+		}
+
+		return nil
+	})
+}
+
+// openConversation finds the match's conversation, creating it when this is the first message.
+// A new conversation starts with the comments the pair left on their likes.
+func (s *MessageService) openConversation(ctx context.Context, match *model.Match, now time.Time) (*model.Conversation, error) {
+	conversation, err := s.ConversationRepo.FindByMatchID(ctx, match.ID)
+	if err == nil {
+		return conversation, nil
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Errorln(ctx, "failed to find conversation", err)
+
+		return nil, errors.New("failed to find conversation")
+	}
+
+	created, err := s.ConversationRepo.Create(ctx, model.NewConversation(match, now))
+	if err != nil {
+		logger.Errorln(ctx, "failed to create conversation", err)
+
+		return nil, errors.New("failed to create conversation")
+	}
+
+	// someone else opened it first
+	conversation, err = s.ConversationRepo.FindByMatchID(ctx, match.ID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find conversation", err)
+
+		return nil, errors.New("failed to find conversation")
+	}
+
+	if !created {
+		return conversation, nil
+	}
+
+	comments, err := s.ReactionRepo.FindComments(ctx, match.UserID, match.MatchedUserID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find like comments", err)
+
+		return nil, errors.New("failed to create conversation")
+	}
+
+	for _, comment := range comments {
+		if comment.Comment == nil {
+			continue
+		}
+
+		err = s.MessageRepo.Create(ctx, model.NewMessage(conversation.ID, comment.UserID, *comment.Comment, comment.CreatedAt))
+		if err != nil {
+			logger.Errorln(ctx, "failed to copy like comment", err)
+
+			return nil, errors.New("failed to create conversation")
+		}
+	}
+
+	return conversation, nil
+}
+
+// markRead sends a read receipt only when there was something new to read, so opening a chat again is quiet.
+func (s *MessageService) markRead(ctx context.Context, match *model.Match, conversation *model.Conversation, userID string, readAt time.Time) error {
+	read, err := s.ConversationRepo.MarkRead(ctx, conversation, userID, readAt)
+	if err != nil || !read {
+		return err
+	}
+
+	publish(ctx, s.Publisher, realtime.EventRead, match.OtherUserID(userID), model.ReadReceipt{MatchID: match.ID, UserID: userID, ReadAt: readAt})
+
+	return nil
+}
+
+// publish pushes a realtime event. Delivery is best effort, clients catch up through the REST endpoints.
+func publish(ctx context.Context, publisher realtime.IPublisher, eventType, userID string, payload interface{}) {
+	event, err := realtime.NewEvent(eventType, userID, payload)
+	if err == nil {
+		err = publisher.Publish(ctx, event)
+	}
+
+	if err != nil {
+		logger.Errorln(ctx, "failed to publish realtime event", err)
+	}
+}
@@ -0,0 +1,893 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marvelalexius/jones/mocks"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/blob"
+	"github.com/marvelalexius/jones/pkg/realtime"
+	"github.com/marvelalexius/jones/pkg/signedurl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestMessageService_Send(t *testing.T) {
+	ctx := context.Background()
+	activeMatch := &model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}
+	conversation := &model.Conversation{ID: "conv1", MatchID: "match1", UserID: "user1", MatchedUserID: "user2"}
+	comment := "love this photo"
+
+	tests := []struct {
+		name          string
+		request       model.SendMessageRequest
+		setupMocks    func(*mocks.IMatchRepository, *mocks.IConversationRepository, *mocks.IMessageRepository, *mocks.IReactionRepository, *mocks.INotificationRepository, *mocks.IModerator)
+		expectedError error
+	}{
+		{
+			name:    "Success - Existing Conversation",
+			request: model.SendMessageRequest{Body: " hey there "},
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, rr *mocks.IReactionRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				mod.On("Allowed", mock.Anything, "hey there").Return(true, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				msr.On("Create", mock.Anything, mock.MatchedBy(func(m model.Message) bool {
+					return m.ConversationID == "conv1" && m.SenderID == "user1" && m.Body == "hey there"
+				})).Return(nil)
+				mr.On("MarkFirstMessage", mock.Anything, "match1", mock.Anything).Return(true, nil)
+				cr.On("Touch", mock.Anything, "conv1", mock.Anything).Return(nil)
+				cr.On("MarkRead", mock.Anything, conversation, "user1", mock.Anything).Return(true, nil)
+				nr.On("Create", mock.MatchedBy(func(n *model.Notification) bool {
+					return n.UserID == "user2" && n.ReferenceID != ""
+				})).Return(true, nil)
+			},
+		},
+		{
+			name:    "Success - First Message Opens Conversation With Like Comments",
+			request: model.SendMessageRequest{Body: "hey there"},
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, rr *mocks.IReactionRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				mod.On("Allowed", mock.Anything, "hey there").Return(true, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(nil, gorm.ErrRecordNotFound).Once()
+				cr.On("Create", mock.Anything, mock.AnythingOfType("model.Conversation")).Return(true, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil).Once()
+				rr.On("FindComments", mock.Anything, "user1", "user2").Return([]model.Reaction{
+					{ID: "reaction1", UserID: "user2", Comment: &comment},
+					{ID: "reaction2", UserID: "user1"},
+				}, nil)
+				msr.On("Create", mock.Anything, mock.MatchedBy(func(m model.Message) bool {
+					return m.SenderID == "user2" && m.Body == comment
+				})).Return(nil).Once()
+				msr.On("Create", mock.Anything, mock.MatchedBy(func(m model.Message) bool {
+					return m.SenderID == "user1" && m.Body == "hey there"
+				})).Return(nil).Once()
+				mr.On("MarkFirstMessage", mock.Anything, "match1", mock.Anything).Return(true, nil)
+				cr.On("Touch", mock.Anything, "conv1", mock.Anything).Return(nil)
+				cr.On("MarkRead", mock.Anything, conversation, "user1", mock.Anything).Return(true, nil)
+				nr.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil)
+			},
+		},
+		{
+			name:    "Error - Match Not Found",
+			request: model.SendMessageRequest{Body: "hey there"},
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, rr *mocks.IReactionRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedError: ErrMatchNotFound,
+		},
+		{
+			name:    "Error - Unmatched",
+			request: model.SendMessageRequest{Body: "hey there"},
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, rr *mocks.IReactionRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusUnmatched}, nil)
+			},
+			expectedError: ErrMatchNotFound,
+		},
+		{
+			name:    "Error - Not A Participant",
+			request: model.SendMessageRequest{Body: "hey there"},
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, rr *mocks.IReactionRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user3", MatchedUserID: "user2", Status: model.MatchStatusActive}, nil)
+			},
+			expectedError: ErrMatchNotFound,
+		},
+		{
+			name:    "Error - Not Your Move",
+			request: model.SendMessageRequest{Body: "hey there"},
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, rr *mocks.IReactionRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				firstMoveBy := "user2"
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive, FirstMoveBy: &firstMoveBy}, nil)
+			},
+			expectedError: ErrNotYourMove,
+		},
+		{
+			name:    "Error - Expired",
+			request: model.SendMessageRequest{Body: "hey there"},
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, rr *mocks.IReactionRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				expiresAt := time.Now().Add(-time.Minute)
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive, ExpiresAt: &expiresAt}, nil)
+			},
+			expectedError: ErrMatchNotFound,
+		},
+		{
+			name:    "Error - Expired While Sending",
+			request: model.SendMessageRequest{Body: "hey there"},
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, rr *mocks.IReactionRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				mod.On("Allowed", mock.Anything, "hey there").Return(true, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				msr.On("Create", mock.Anything, mock.AnythingOfType("model.Message")).Return(nil)
+				mr.On("MarkFirstMessage", mock.Anything, "match1", mock.Anything).Return(false, nil)
+			},
+			expectedError: ErrMatchNotFound,
+		},
+		{
+			name:    "Error - Blank Message",
+			request: model.SendMessageRequest{Body: "   "},
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, rr *mocks.IReactionRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+			},
+			expectedError: ErrEmptyMessage,
+		},
+		{
+			name:    "Error - Rejected By Moderation",
+			request: model.SendMessageRequest{Body: "find me on spam.example"},
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, rr *mocks.IReactionRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				mod.On("Allowed", mock.Anything, "find me on spam.example").Return(false, nil)
+			},
+			expectedError: ErrMessageRejected,
+		},
+		{
+			name:    "Error - Failed to Create Message",
+			request: model.SendMessageRequest{Body: "hey there"},
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, rr *mocks.IReactionRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				mod.On("Allowed", mock.Anything, "hey there").Return(true, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				msr.On("Create", mock.Anything, mock.AnythingOfType("model.Message")).Return(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to send message"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			conversationRepo := new(mocks.IConversationRepository)
+			messageRepo := new(mocks.IMessageRepository)
+			reactionRepo := new(mocks.IReactionRepository)
+			notificationRepo := new(mocks.INotificationRepository)
+			moderator := new(mocks.IModerator)
+			tt.setupMocks(matchRepo, conversationRepo, messageRepo, reactionRepo, notificationRepo, moderator)
+
+			broker, hub := realtime.NewMemoryBroker(), realtime.NewHub()
+			broker.Subscribe(hub.Deliver)
+			sender, recipient := realtime.NewClient("user1"), realtime.NewClient("user2")
+			hub.Register(sender)
+			hub.Register(recipient)
+
+			service := NewMessageService(passthroughTransactor(), matchRepo, conversationRepo, messageRepo, new(mocks.IAttachmentRepository), reactionRepo, notificationRepo, moderator, new(mocks.IStorage), testURLSigner(), broker, testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), notificationRepo, broker), noDeferred())
+			message, err := service.Send(ctx, "user1", "match1", tt.request)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+				assert.Empty(t, recipient.Send)
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, message.ID)
+
+				// the recipient's notification streams get the stored notification first
+				assert.Equal(t, realtime.EventNotification, (<-recipient.Send).Type)
+
+				// both participants' devices are told about the message
+				assert.Equal(t, realtime.EventMessage, (<-recipient.Send).Type)
+				assert.Equal(t, realtime.EventMessage, (<-sender.Send).Type)
+			}
+
+			matchRepo.AssertExpectations(t)
+			conversationRepo.AssertExpectations(t)
+			messageRepo.AssertExpectations(t)
+			reactionRepo.AssertExpectations(t)
+			notificationRepo.AssertExpectations(t)
+			moderator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageService_Send_Outbox(t *testing.T) {
+	ctx := context.Background()
+	activeMatch := &model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}
+	conversation := &model.Conversation{ID: "conv1", MatchID: "match1", UserID: "user1", MatchedUserID: "user2"}
+
+	tests := []struct {
+		name          string
+		failAt        int
+		expectedError error
+		expectedSent  int
+	}{
+		{
+			name:         "Success - Recipient Is Told Once It Commits",
+			expectedSent: 1,
+		},
+		{
+			// the message was written in the same transaction, so it's gone too
+			name:          "Error - Nothing Is Sent When The Transaction Fails",
+			failAt:        1,
+			expectedError: errors.New("failed to enqueue notification"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			matchRepo.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+			matchRepo.On("MarkFirstMessage", mock.MatchedBy(inTransaction), "match1", mock.Anything).Return(true, nil)
+
+			conversationRepo := new(mocks.IConversationRepository)
+			conversationRepo.On("FindByMatchID", mock.MatchedBy(inTransaction), "match1").Return(conversation, nil)
+			conversationRepo.On("Touch", mock.MatchedBy(inTransaction), "conv1", mock.Anything).Return(nil)
+			conversationRepo.On("MarkRead", mock.MatchedBy(inTransaction), conversation, "user1", mock.Anything).Return(true, nil)
+
+			messageRepo := new(mocks.IMessageRepository)
+			messageRepo.On("Create", mock.MatchedBy(inTransaction), mock.AnythingOfType("model.Message")).Return(nil).Once()
+
+			moderator := new(mocks.IModerator)
+			moderator.On("Allowed", mock.Anything, "hey there").Return(true, nil)
+
+			broker, hub := realtime.NewMemoryBroker(), realtime.NewHub()
+			broker.Subscribe(hub.Deliver)
+			recipient := realtime.NewClient("user2")
+			hub.Register(recipient)
+
+			outbox := &txOutbox{failAt: tt.failAt}
+			service := NewMessageService(outbox, matchRepo, conversationRepo, messageRepo, new(mocks.IAttachmentRepository), new(mocks.IReactionRepository), new(mocks.INotificationRepository), moderator, new(mocks.IStorage), testURLSigner(), broker, outbox, noDeferred())
+			message, err := service.Send(ctx, "user1", "match1", model.SendMessageRequest{Body: "hey there"})
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+				assert.Empty(t, recipient.Send)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, message.ID, outbox.committed[0].ReferenceID)
+			}
+			assert.Len(t, outbox.committed, tt.expectedSent)
+
+			matchRepo.AssertExpectations(t)
+			conversationRepo.AssertExpectations(t)
+			messageRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageService_FindConversations(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IConversationRepository, *mocks.IMessageRepository)
+		expected      []model.ConversationResponse
+		expectedTotal int64
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(cr *mocks.IConversationRepository, msr *mocks.IMessageRepository) {
+				cr.On("FindByUserID", mock.Anything, "user1", model.PaginationRequest{Page: 1, Limit: model.DefaultPageLimit}).Return([]model.Conversation{
+					{ID: "conv1", MatchID: "match1", UserID: "user1", MatchedUserID: "user2", LastMessageAt: &now, MatchedUser: model.User{ID: "user2", Name: "User 2"}},
+					{ID: "conv2", MatchID: "match2", UserID: "user3", MatchedUserID: "user1", User: model.User{ID: "user3", Name: "User 3"}},
+				}, int64(2), nil)
+				msr.On("FindLatest", mock.Anything, []string{"conv1", "conv2"}).Return(map[string]model.Message{
+					"conv1": {ID: "msg1", ConversationID: "conv1", SenderID: "user2", Body: "hi", CreatedAt: now},
+				}, nil)
+				msr.On("CountUnread", mock.Anything, "user1", []string{"conv1", "conv2"}).Return(map[string]int64{"conv1": 3}, nil)
+			},
+			expected: []model.ConversationResponse{
+				{
+					ID:            "conv1",
+					MatchID:       "match1",
+					User:          model.MatchProfile{ID: "user2", Name: "User 2"},
+					LastMessage:   &model.Message{ID: "msg1", ConversationID: "conv1", SenderID: "user2", Body: "hi", CreatedAt: now},
+					LastMessageAt: &now,
+					UnreadCount:   3,
+				},
+				{
+					ID:      "conv2",
+					MatchID: "match2",
+					User:    model.MatchProfile{ID: "user3", Name: "User 3"},
+				},
+			},
+			expectedTotal: 2,
+		},
+		{
+			name: "Success - No Conversations",
+			setupMocks: func(cr *mocks.IConversationRepository, msr *mocks.IMessageRepository) {
+				cr.On("FindByUserID", mock.Anything, "user1", mock.Anything).Return([]model.Conversation{}, int64(0), nil)
+			},
+			expected: []model.ConversationResponse{},
+		},
+		{
+			name: "Error - Failed to Count Unread",
+			setupMocks: func(cr *mocks.IConversationRepository, msr *mocks.IMessageRepository) {
+				cr.On("FindByUserID", mock.Anything, "user1", mock.Anything).Return([]model.Conversation{{ID: "conv1"}}, int64(1), nil)
+				msr.On("FindLatest", mock.Anything, []string{"conv1"}).Return(map[string]model.Message{}, nil)
+				msr.On("CountUnread", mock.Anything, "user1", []string{"conv1"}).Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("failed to find conversations"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conversationRepo := new(mocks.IConversationRepository)
+			messageRepo := new(mocks.IMessageRepository)
+			tt.setupMocks(conversationRepo, messageRepo)
+
+			service := NewMessageService(passthroughTransactor(), new(mocks.IMatchRepository), conversationRepo, messageRepo, new(mocks.IAttachmentRepository), new(mocks.IReactionRepository), new(mocks.INotificationRepository), new(mocks.IModerator), new(mocks.IStorage), testURLSigner(), realtime.NewMemoryBroker(), testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+			conversations, total, err := service.FindConversations(ctx, "user1", model.PaginationRequest{})
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, conversations)
+				assert.Equal(t, tt.expectedTotal, total)
+			}
+
+			conversationRepo.AssertExpectations(t)
+			messageRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageService_FindMessages(t *testing.T) {
+	ctx := context.Background()
+	activeMatch := &model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}
+	conversation := &model.Conversation{ID: "conv1", MatchID: "match1", UserID: "user1", MatchedUserID: "user2"}
+
+	tests := []struct {
+		name            string
+		setupMocks      func(*mocks.IMatchRepository, *mocks.IConversationRepository, *mocks.IMessageRepository)
+		expected        []model.Message
+		expectedTotal   int64
+		expectedReceipt bool
+		expectedError   error
+	}{
+		{
+			name: "Success - Marks Conversation As Read",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				msr.On("FindByConversationID", mock.Anything, "conv1", model.PaginationRequest{Page: 1, Limit: model.DefaultPageLimit}).Return([]model.Message{{ID: "msg1", Body: "hi"}}, int64(1), nil)
+				cr.On("MarkRead", mock.Anything, conversation, "user1", mock.Anything).Return(true, nil)
+			},
+			expected:        []model.Message{{ID: "msg1", Body: "hi"}},
+			expectedTotal:   1,
+			expectedReceipt: true,
+		},
+		{
+			name: "Success - Nothing New Sends No Read Receipt",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				msr.On("FindByConversationID", mock.Anything, "conv1", model.PaginationRequest{Page: 1, Limit: model.DefaultPageLimit}).Return([]model.Message{{ID: "msg1", Body: "hi"}}, int64(1), nil)
+				cr.On("MarkRead", mock.Anything, conversation, "user1", mock.Anything).Return(false, nil)
+			},
+			expected:      []model.Message{{ID: "msg1", Body: "hi"}},
+			expectedTotal: 1,
+		},
+		{
+			name: "Success - No Messages Yet",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(nil, gorm.ErrRecordNotFound)
+			},
+			expected: []model.Message{},
+		},
+		{
+			name: "Error - Match Not Found",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedError: ErrMatchNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			conversationRepo := new(mocks.IConversationRepository)
+			messageRepo := new(mocks.IMessageRepository)
+			tt.setupMocks(matchRepo, conversationRepo, messageRepo)
+
+			broker, hub := realtime.NewMemoryBroker(), realtime.NewHub()
+			broker.Subscribe(hub.Deliver)
+			other := realtime.NewClient("user2")
+			hub.Register(other)
+
+			service := NewMessageService(passthroughTransactor(), matchRepo, conversationRepo, messageRepo, new(mocks.IAttachmentRepository), new(mocks.IReactionRepository), new(mocks.INotificationRepository), new(mocks.IModerator), new(mocks.IStorage), testURLSigner(), broker, testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), new(mocks.INotificationRepository), broker), noDeferred())
+			messages, total, err := service.FindMessages(ctx, "user1", "match1", model.PaginationRequest{})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, messages)
+				assert.Equal(t, tt.expectedTotal, total)
+			}
+
+			if tt.expectedReceipt {
+				assert.Equal(t, realtime.EventRead, (<-other.Send).Type)
+			} else {
+				assert.Empty(t, other.Send)
+			}
+
+			matchRepo.AssertExpectations(t)
+			conversationRepo.AssertExpectations(t)
+			messageRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageService_Delete(t *testing.T) {
+	ctx := context.Background()
+	activeMatch := &model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}
+	conversation := &model.Conversation{ID: "conv1", MatchID: "match1", UserID: "user1", MatchedUserID: "user2"}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IMatchRepository, *mocks.IConversationRepository, *mocks.IMessageRepository, *mocks.INotificationRepository, *mocks.IDeferredNotificationRepository)
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, nr *mocks.INotificationRepository, dr *mocks.IDeferredNotificationRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				msr.On("FindByID", mock.Anything, "msg1").Return(&model.Message{ID: "msg1", ConversationID: "conv1", SenderID: "user1"}, nil)
+				msr.On("Delete", mock.Anything, "msg1", mock.Anything).Return(nil)
+				nr.On("DeleteByReferenceID", mock.Anything, "msg1").Return(nil)
+				dr.On("DeleteByReferenceID", mock.Anything, "msg1").Return(nil)
+			},
+		},
+		{
+			name: "Error - Someone Else's Message",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, nr *mocks.INotificationRepository, dr *mocks.IDeferredNotificationRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				msr.On("FindByID", mock.Anything, "msg1").Return(&model.Message{ID: "msg1", ConversationID: "conv1", SenderID: "user2"}, nil)
+			},
+			expectedError: ErrMessageNotFound,
+		},
+		{
+			name: "Error - Message From Another Conversation",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, nr *mocks.INotificationRepository, dr *mocks.IDeferredNotificationRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				msr.On("FindByID", mock.Anything, "msg1").Return(&model.Message{ID: "msg1", ConversationID: "conv2", SenderID: "user1"}, nil)
+			},
+			expectedError: ErrMessageNotFound,
+		},
+		{
+			name: "Error - Message Not Found",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, nr *mocks.INotificationRepository, dr *mocks.IDeferredNotificationRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				msr.On("FindByID", mock.Anything, "msg1").Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedError: ErrMessageNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			conversationRepo := new(mocks.IConversationRepository)
+			messageRepo := new(mocks.IMessageRepository)
+			notificationRepo := new(mocks.INotificationRepository)
+			deferredRepo := new(mocks.IDeferredNotificationRepository)
+			tt.setupMocks(matchRepo, conversationRepo, messageRepo, notificationRepo, deferredRepo)
+
+			service := NewMessageService(passthroughTransactor(), matchRepo, conversationRepo, messageRepo, new(mocks.IAttachmentRepository), new(mocks.IReactionRepository), notificationRepo, new(mocks.IModerator), new(mocks.IStorage), testURLSigner(), realtime.NewMemoryBroker(), testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), notificationRepo, realtime.NewMemoryBroker()), deferredRepo)
+			err := service.Delete(ctx, "user1", "match1", "msg1")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			matchRepo.AssertExpectations(t)
+			conversationRepo.AssertExpectations(t)
+			messageRepo.AssertExpectations(t)
+			notificationRepo.AssertExpectations(t)
+			deferredRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageService_MarkRead(t *testing.T) {
+	ctx := context.Background()
+	activeMatch := &model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}
+	conversation := &model.Conversation{ID: "conv1", MatchID: "match1", UserID: "user1", MatchedUserID: "user2"}
+
+	tests := []struct {
+		name            string
+		setupMocks      func(*mocks.IMatchRepository, *mocks.IConversationRepository)
+		expectedReceipt bool
+		expectedError   error
+	}{
+		{
+			name: "Success - Sends Read Receipt",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				cr.On("MarkRead", mock.Anything, conversation, "user1", mock.Anything).Return(true, nil)
+			},
+			expectedReceipt: true,
+		},
+		{
+			name: "Success - Nothing New To Read",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				cr.On("MarkRead", mock.Anything, conversation, "user1", mock.Anything).Return(false, nil)
+			},
+		},
+		{
+			name: "Success - Nothing Said Yet",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(nil, gorm.ErrRecordNotFound)
+			},
+		},
+		{
+			name: "Error - Failed to Mark Read",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				cr.On("MarkRead", mock.Anything, conversation, "user1", mock.Anything).Return(false, errors.New("db error"))
+			},
+			expectedError: errors.New("failed to mark conversation as read"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			conversationRepo := new(mocks.IConversationRepository)
+			tt.setupMocks(matchRepo, conversationRepo)
+
+			broker, hub := realtime.NewMemoryBroker(), realtime.NewHub()
+			broker.Subscribe(hub.Deliver)
+			other := realtime.NewClient("user2")
+			hub.Register(other)
+
+			service := NewMessageService(passthroughTransactor(), matchRepo, conversationRepo, new(mocks.IMessageRepository), new(mocks.IAttachmentRepository), new(mocks.IReactionRepository), new(mocks.INotificationRepository), new(mocks.IModerator), new(mocks.IStorage), testURLSigner(), broker, testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), new(mocks.INotificationRepository), broker), noDeferred())
+			err := service.MarkRead(ctx, "user1", "match1")
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.expectedReceipt {
+				assert.Equal(t, realtime.EventRead, (<-other.Send).Type)
+			} else {
+				assert.Empty(t, other.Send)
+			}
+
+			matchRepo.AssertExpectations(t)
+			conversationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageService_Typing(t *testing.T) {
+	ctx := context.Background()
+
+	matchRepo := new(mocks.IMatchRepository)
+	matchRepo.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}, nil)
+	matchRepo.On("FindByID", mock.Anything, "match2").Return(&model.Match{ID: "match2", UserID: "user3", MatchedUserID: "user2", Status: model.MatchStatusActive}, nil)
+
+	broker, hub := realtime.NewMemoryBroker(), realtime.NewHub()
+	broker.Subscribe(hub.Deliver)
+	other := realtime.NewClient("user2")
+	hub.Register(other)
+
+	service := NewMessageService(passthroughTransactor(), matchRepo, new(mocks.IConversationRepository), new(mocks.IMessageRepository), new(mocks.IAttachmentRepository), new(mocks.IReactionRepository), new(mocks.INotificationRepository), new(mocks.IModerator), new(mocks.IStorage), testURLSigner(), broker, testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), new(mocks.INotificationRepository), broker), noDeferred())
+
+	assert.NoError(t, service.Typing(ctx, "user1", "match1"))
+
+	event := <-other.Send
+	assert.Equal(t, realtime.EventTyping, event.Type)
+	assert.JSONEq(t, `{"match_id":"match1","user_id":"user1"}`, string(event.Payload))
+
+	// only participants can say they're typing
+	assert.ErrorIs(t, service.Typing(ctx, "user1", "match2"), ErrMatchNotFound)
+	assert.Empty(t, other.Send)
+}
+
+func TestMessageService_SendAttachment(t *testing.T) {
+	ctx := context.Background()
+	activeMatch := &model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}
+	conversation := &model.Conversation{ID: "conv1", MatchID: "match1", UserID: "user1", MatchedUserID: "user2"}
+	png := "\x89PNG\r\n\x1a\n" + strings.Repeat("\x00", 64)
+	mp3 := "ID3" + strings.Repeat("\x00", 64)
+
+	delivered := func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, ar *mocks.IAttachmentRepository, nr *mocks.INotificationRepository, kind, contentType string) {
+		cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+		msr.On("Create", mock.Anything, mock.MatchedBy(func(m model.Message) bool {
+			return m.ConversationID == "conv1" && m.SenderID == "user1"
+		})).Return(nil)
+		ar.On("Create", mock.Anything, mock.MatchedBy(func(a model.Attachment) bool {
+			return a.MessageID != "" && a.MatchID == "match1" && a.Kind == kind && a.ContentType == contentType
+		})).Return(nil)
+		mr.On("MarkFirstMessage", mock.Anything, "match1", mock.Anything).Return(true, nil)
+		cr.On("Touch", mock.Anything, "conv1", mock.Anything).Return(nil)
+		cr.On("MarkRead", mock.Anything, conversation, "user1", mock.Anything).Return(true, nil)
+		nr.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil)
+	}
+
+	tests := []struct {
+		name          string
+		request       model.SendAttachmentRequest
+		file          string
+		size          int64
+		setupMocks    func(*mocks.IMatchRepository, *mocks.IConversationRepository, *mocks.IMessageRepository, *mocks.IAttachmentRepository, *mocks.INotificationRepository, *mocks.IModerator)
+		expectedType  string
+		expectedError error
+	}{
+		{
+			name:    "Success - Photo With Caption",
+			request: model.SendAttachmentRequest{Kind: model.AttachmentPhoto, Caption: " look "},
+			file:    png,
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, ar *mocks.IAttachmentRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				mod.On("Allowed", mock.Anything, "look").Return(true, nil)
+				delivered(mr, cr, msr, ar, nr, model.AttachmentPhoto, "image/png")
+			},
+			expectedType: "image/png",
+		},
+		{
+			name:    "Success - Voice Note",
+			request: model.SendAttachmentRequest{Kind: model.AttachmentVoice, DurationMs: 15000},
+			file:    mp3,
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, ar *mocks.IAttachmentRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				delivered(mr, cr, msr, ar, nr, model.AttachmentVoice, "audio/mpeg")
+			},
+			expectedType: "audio/mpeg",
+		},
+		{
+			name:    "Error - Too Large",
+			request: model.SendAttachmentRequest{Kind: model.AttachmentPhoto},
+			file:    png,
+			size:    model.MaxPhotoSize + 1,
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, ar *mocks.IAttachmentRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+			},
+			expectedError: ErrAttachmentTooLarge,
+		},
+		{
+			name:    "Error - Voice Note Too Long",
+			request: model.SendAttachmentRequest{Kind: model.AttachmentVoice, DurationMs: int(model.MaxVoiceDuration/time.Millisecond) + 1},
+			file:    mp3,
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, ar *mocks.IAttachmentRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+			},
+			expectedError: ErrVoiceNoteTooLong,
+		},
+		{
+			name:    "Error - Content Doesn't Match Kind",
+			request: model.SendAttachmentRequest{Kind: model.AttachmentVoice, DurationMs: 1000},
+			file:    png,
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, ar *mocks.IAttachmentRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+			},
+			expectedError: ErrUnsupportedAttachment,
+		},
+		{
+			name:    "Error - Not An Image",
+			request: model.SendAttachmentRequest{Kind: model.AttachmentPhoto},
+			file:    "<html><script>alert(1)</script></html>",
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, ar *mocks.IAttachmentRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+			},
+			expectedError: ErrUnsupportedAttachment,
+		},
+		{
+			name:    "Error - Caption Rejected",
+			request: model.SendAttachmentRequest{Kind: model.AttachmentPhoto, Caption: "dm me at spam.com"},
+			file:    png,
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, ar *mocks.IAttachmentRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				mod.On("Allowed", mock.Anything, "dm me at spam.com").Return(false, nil)
+			},
+			expectedError: ErrMessageRejected,
+		},
+		{
+			name:    "Error - Match Not Found",
+			request: model.SendAttachmentRequest{Kind: model.AttachmentPhoto},
+			file:    png,
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, ar *mocks.IAttachmentRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedError: ErrMatchNotFound,
+		},
+		{
+			name:    "Error - Stored File Removed When Message Fails",
+			request: model.SendAttachmentRequest{Kind: model.AttachmentPhoto},
+			file:    png,
+			setupMocks: func(mr *mocks.IMatchRepository, cr *mocks.IConversationRepository, msr *mocks.IMessageRepository, ar *mocks.IAttachmentRepository, nr *mocks.INotificationRepository, mod *mocks.IModerator) {
+				mr.On("FindByID", mock.Anything, "match1").Return(activeMatch, nil)
+				cr.On("FindByMatchID", mock.Anything, "match1").Return(conversation, nil)
+				msr.On("Create", mock.Anything, mock.AnythingOfType("model.Message")).Return(nil)
+				mr.On("MarkFirstMessage", mock.Anything, "match1", mock.Anything).Return(true, nil)
+				ar.On("Create", mock.Anything, mock.AnythingOfType("model.Attachment")).Return(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to send message"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			conversationRepo := new(mocks.IConversationRepository)
+			messageRepo := new(mocks.IMessageRepository)
+			attachmentRepo := new(mocks.IAttachmentRepository)
+			notificationRepo := new(mocks.INotificationRepository)
+			moderator := new(mocks.IModerator)
+			tt.setupMocks(matchRepo, conversationRepo, messageRepo, attachmentRepo, notificationRepo, moderator)
+
+			dir := t.TempDir()
+			storage, err := blob.NewLocalStorage(dir)
+			assert.NoError(t, err)
+
+			size := tt.size
+			if size == 0 {
+				size = int64(len(tt.file))
+			}
+
+			service := NewMessageService(passthroughTransactor(), matchRepo, conversationRepo, messageRepo, attachmentRepo, new(mocks.IReactionRepository), notificationRepo, moderator, storage, testURLSigner(), realtime.NewMemoryBroker(), testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), notificationRepo, realtime.NewMemoryBroker()), noDeferred())
+			message, err := service.SendAttachment(ctx, "user1", "match1", tt.request, strings.NewReader(tt.file), size)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+
+				// nothing is left behind in storage
+				stored, _ := filepath.Glob(filepath.Join(dir, "matches", "match1", "*"))
+				assert.Empty(t, stored)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, message.Attachments, 1)
+
+				attachment := message.Attachments[0]
+				assert.Equal(t, tt.expectedType, attachment.ContentType)
+				assert.Contains(t, attachment.URL, attachment.Path()+"?")
+
+				r, err := storage.Open(ctx, attachment.StorageKey)
+				assert.NoError(t, err)
+				b, _ := io.ReadAll(r)
+				r.Close()
+				assert.Equal(t, tt.file, string(b))
+			}
+
+			matchRepo.AssertExpectations(t)
+			conversationRepo.AssertExpectations(t)
+			messageRepo.AssertExpectations(t)
+			attachmentRepo.AssertExpectations(t)
+			notificationRepo.AssertExpectations(t)
+			moderator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageService_OpenAttachment(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	signer := testURLSigner()
+	attachment := &model.Attachment{ID: "file1", MatchID: "match1", StorageKey: "matches/match1/file1", ContentType: "image/png"}
+
+	signedFor := func(viewerID string) url.Values {
+		parsed, err := url.Parse(signer.Sign(attachment.Path(), viewerID, now))
+		assert.NoError(t, err)
+
+		return parsed.Query()
+	}
+
+	tests := []struct {
+		name          string
+		query         url.Values
+		setupMocks    func(*mocks.IMatchRepository, *mocks.IAttachmentRepository, *mocks.IStorage)
+		expectedError error
+	}{
+		{
+			name:  "Success",
+			query: signedFor("user2"),
+			setupMocks: func(mr *mocks.IMatchRepository, ar *mocks.IAttachmentRepository, st *mocks.IStorage) {
+				ar.On("FindByID", mock.Anything, "file1").Return(attachment, nil)
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}, nil)
+				st.On("Open", mock.Anything, "matches/match1/file1").Return(io.NopCloser(strings.NewReader("png")), nil)
+			},
+		},
+		{
+			name:          "Error - Tampered URL",
+			query:         url.Values{"user": {"user3"}, "expires": signedFor("user2")["expires"], "signature": signedFor("user2")["signature"]},
+			setupMocks:    func(mr *mocks.IMatchRepository, ar *mocks.IAttachmentRepository, st *mocks.IStorage) {},
+			expectedError: ErrInvalidAttachmentURL,
+		},
+		{
+			name:  "Error - No Longer Matched",
+			query: signedFor("user2"),
+			setupMocks: func(mr *mocks.IMatchRepository, ar *mocks.IAttachmentRepository, st *mocks.IStorage) {
+				ar.On("FindByID", mock.Anything, "file1").Return(attachment, nil)
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusUnmatched}, nil)
+			},
+			expectedError: ErrAttachmentNotFound,
+		},
+		{
+			name:  "Error - Message Deleted",
+			query: signedFor("user2"),
+			setupMocks: func(mr *mocks.IMatchRepository, ar *mocks.IAttachmentRepository, st *mocks.IStorage) {
+				ar.On("FindByID", mock.Anything, "file1").Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedError: ErrAttachmentNotFound,
+		},
+		{
+			name:  "Error - Blob Missing",
+			query: signedFor("user1"),
+			setupMocks: func(mr *mocks.IMatchRepository, ar *mocks.IAttachmentRepository, st *mocks.IStorage) {
+				ar.On("FindByID", mock.Anything, "file1").Return(attachment, nil)
+				mr.On("FindByID", mock.Anything, "match1").Return(&model.Match{ID: "match1", UserID: "user1", MatchedUserID: "user2", Status: model.MatchStatusActive}, nil)
+				st.On("Open", mock.Anything, "matches/match1/file1").Return(nil, blob.ErrNotFound)
+			},
+			expectedError: ErrAttachmentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := new(mocks.IMatchRepository)
+			attachmentRepo := new(mocks.IAttachmentRepository)
+			storage := new(mocks.IStorage)
+			tt.setupMocks(matchRepo, attachmentRepo, storage)
+
+			service := NewMessageService(passthroughTransactor(), matchRepo, new(mocks.IConversationRepository), new(mocks.IMessageRepository), attachmentRepo, new(mocks.IReactionRepository), new(mocks.INotificationRepository), new(mocks.IModerator), storage, signer, realtime.NewMemoryBroker(), testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+			found, file, err := service.OpenAttachment(ctx, "file1", tt.query)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, attachment, found)
+				assert.NoError(t, file.Close())
+			}
+
+			matchRepo.AssertExpectations(t)
+			attachmentRepo.AssertExpectations(t)
+			storage.AssertExpectations(t)
+		})
+	}
+}
+
+func testURLSigner() signedurl.ISigner {
+	signer, _ := signedurl.NewSigner("test-secret", "", model.AttachmentURLTTL)
+
+	return signer
+}
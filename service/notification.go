@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/i18n"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+//go:embed templates/notifications/*.json
+var notificationTemplates embed.FS
+
+// notificationCopy has the copy of every notification type in every language we support.
+var notificationCopy = i18n.MustLoad(notificationTemplates, "templates/notifications", model.DefaultLanguage)
+
+type (
+	NotificationService struct {
+		NotificationRepo repository.INotificationRepository
+	}
+
+	INotificationService interface {
+		FindMissed(ctx context.Context, userID, lastEventID string) ([]model.Notification, error)
+		FindAll(ctx context.Context, userID string, req model.NotificationRequest) ([]model.Notification, int64, error)
+		CountUnread(ctx context.Context, userID string) (int64, error)
+		MarkRead(ctx context.Context, userID, id string) error
+		MarkAllRead(ctx context.Context, userID string) (int64, error)
+		Delete(ctx context.Context, userID, id string) error
+	}
+)
+
+func NewNotificationService(notificationRepo repository.INotificationRepository) INotificationService {
+	return &NotificationService{NotificationRepo: notificationRepo}
+}
+
+// FindMissed returns the next page of notifications stored after lastEventID, the sequence of the last one a
+// stream got, so it can catch up after reconnecting.
+func (s *NotificationService) FindMissed(ctx context.Context, userID, lastEventID string) ([]model.Notification, error) {
+	afterSeq, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil || afterSeq < 0 {
+		return nil, ErrInvalidLastEventID
+	}
+
+	notifications, err := s.NotificationRepo.FindAfter(ctx, userID, afterSeq, model.NotificationReplayLimit)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find missed notifications", err)
+
+		return nil, errors.New("failed to find notifications")
+	}
+
+	return notifications, nil
+}
+
+func (s *NotificationService) FindAll(ctx context.Context, userID string, req model.NotificationRequest) ([]model.Notification, int64, error) {
+	req.Normalize()
+
+	notifications, total, err := s.NotificationRepo.FindByUserID(ctx, userID, req.Type, req.PaginationRequest)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find notifications", err)
+
+		return nil, 0, errors.New("failed to find notifications")
+	}
+
+	return notifications, total, nil
+}
+
+func (s *NotificationService) CountUnread(ctx context.Context, userID string) (int64, error) {
+	unread, err := s.NotificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to count unread notifications", err)
+
+		return 0, errors.New("failed to count unread notifications")
+	}
+
+	return unread, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id string) error {
+	found, err := s.NotificationRepo.MarkRead(ctx, userID, id, time.Now())
+	if err != nil {
+		logger.Errorln(ctx, "failed to mark notification as read", err)
+
+		return errors.New("failed to mark notification as read")
+	}
+
+	if !found {
+		return ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+// MarkAllRead returns how many notifications were marked as read.
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	marked, err := s.NotificationRepo.MarkAllRead(ctx, userID, time.Now())
+	if err != nil {
+		logger.Errorln(ctx, "failed to mark notifications as read", err)
+
+		return 0, errors.New("failed to mark notifications as read")
+	}
+
+	return marked, nil
+}
+
+func (s *NotificationService) Delete(ctx context.Context, userID, id string) error {
+	found, err := s.NotificationRepo.Delete(ctx, userID, id, time.Now())
+	if err != nil {
+		logger.Errorln(ctx, "failed to delete notification", err)
+
+		return errors.New("failed to delete notification")
+	}
+
+	if !found {
+		return ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+// newNotification writes the notification in the recipient's language.
+func newNotification(id string, recipient *model.User, referenceID string, payload model.NotificationPayload) (model.Notification, i18n.Message, error) {
+	language := recipient.Language
+	if language == "" {
+		language = model.DefaultLanguage
+	}
+
+	copy, err := notificationCopy.Render(language, payload.NotificationType(), payload)
+	if err != nil {
+		return model.Notification{}, i18n.Message{}, err
+	}
+
+	b, err := json.Marshal(model.NotificationContent{
+		Type:    payload.NotificationType(),
+		Title:   copy.Title,
+		Message: copy.Body,
+		Data:    payload,
+	})
+	if err != nil {
+		return model.Notification{}, i18n.Message{}, err
+	}
+
+	return model.Notification{
+		ID:          id,
+		UserID:      recipient.ID,
+		Type:        payload.NotificationType(),
+		Content:     string(b),
+		IsRead:      false,
+		ReferenceID: referenceID,
+		CreatedAt:   time.Now(),
+	}, copy, nil
+}
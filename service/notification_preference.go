@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+type (
+	NotificationPreferenceService struct {
+		UserRepo       repository.IUserRepository
+		PreferenceRepo repository.INotificationPreferenceRepository
+	}
+
+	INotificationPreferenceService interface {
+		Find(ctx context.Context, userID string) (model.NotificationPreferences, error)
+		Update(ctx context.Context, userID string, req model.UpdateNotificationPreferencesRequest) (model.NotificationPreferences, error)
+		UpdateQuietHours(ctx context.Context, userID string, req model.UpdateQuietHoursRequest) (model.NotificationPreferences, error)
+		ClearQuietHours(ctx context.Context, userID string) error
+	}
+)
+
+func NewNotificationPreferenceService(userRepo repository.IUserRepository, preferenceRepo repository.INotificationPreferenceRepository) INotificationPreferenceService {
+	return &NotificationPreferenceService{UserRepo: userRepo, PreferenceRepo: preferenceRepo}
+}
+
+// Find returns every type and channel the user can turn off, with the ones they haven't touched still on.
+func (s *NotificationPreferenceService) Find(ctx context.Context, userID string) (model.NotificationPreferences, error) {
+	user, err := s.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find user", err)
+
+		return model.NotificationPreferences{}, errors.New("failed to find notification preferences")
+	}
+
+	stored, err := s.PreferenceRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find notification preferences", err)
+
+		return model.NotificationPreferences{}, errors.New("failed to find notification preferences")
+	}
+
+	enabled := map[[2]string]bool{}
+	for _, preference := range stored {
+		enabled[[2]string{preference.Type, preference.Channel}] = preference.Enabled
+	}
+
+	types := make([]string, 0, len(model.NotificationTypes))
+	for notificationType := range model.NotificationTypes {
+		types = append(types, notificationType)
+	}
+	sort.Strings(types)
+
+	preferences := []model.NotificationPreference{}
+	for _, notificationType := range types {
+		for _, channel := range model.NotificationTypes[notificationType].Channels {
+			if !model.ConfigurableChannel(notificationType, channel) {
+				continue
+			}
+
+			preference := model.NotificationPreference{UserID: userID, Type: notificationType, Channel: channel, Enabled: true}
+			if on, ok := enabled[[2]string{notificationType, channel}]; ok {
+				preference.Enabled = on
+			}
+
+			preferences = append(preferences, preference)
+		}
+	}
+
+	return model.NotificationPreferences{Preferences: preferences, QuietHours: user.QuietHours(), Timezone: user.Timezone}, nil
+}
+
+func (s *NotificationPreferenceService) Update(ctx context.Context, userID string, req model.UpdateNotificationPreferencesRequest) (model.NotificationPreferences, error) {
+	now := time.Now()
+
+	preferences := make([]model.NotificationPreference, 0, len(req.Preferences))
+	for _, preference := range req.Preferences {
+		// transactional notifications and channels a type never goes out on can't be turned off
+		if !model.ConfigurableChannel(preference.Type, preference.Channel) {
+			return model.NotificationPreferences{}, ErrNotificationPreferenceNotAllowed
+		}
+
+		preferences = append(preferences, model.NotificationPreference{
+			UserID:    userID,
+			Type:      preference.Type,
+			Channel:   preference.Channel,
+			Enabled:   *preference.Enabled,
+			UpdatedAt: now,
+		})
+	}
+
+	err := s.PreferenceRepo.Upsert(ctx, preferences)
+	if err != nil {
+		logger.Errorln(ctx, "failed to update notification preferences", err)
+
+		return model.NotificationPreferences{}, errors.New("failed to update notification preferences")
+	}
+
+	return s.Find(ctx, userID)
+}
+
+// UpdateQuietHours sets the hours push and email wait out, in the user's timezone.
+func (s *NotificationPreferenceService) UpdateQuietHours(ctx context.Context, userID string, req model.UpdateQuietHoursRequest) (model.NotificationPreferences, error) {
+	err := s.UserRepo.UpdateQuietHours(ctx, userID, req.Start, req.End, time.Now())
+	if err != nil {
+		logger.Errorln(ctx, "failed to update quiet hours", err)
+
+		return model.NotificationPreferences{}, errors.New("failed to update quiet hours")
+	}
+
+	return s.Find(ctx, userID)
+}
+
+// ClearQuietHours turns quiet hours off, what they're holding back still goes out when they would have ended.
+func (s *NotificationPreferenceService) ClearQuietHours(ctx context.Context, userID string) error {
+	err := s.UserRepo.UpdateQuietHours(ctx, userID, "", "", time.Now())
+	if err != nil {
+		logger.Errorln(ctx, "failed to clear quiet hours", err)
+
+		return errors.New("failed to clear quiet hours")
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/marvelalexius/jones/mocks"
+	"github.com/marvelalexius/jones/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotificationPreferenceService_Find(t *testing.T) {
+	ctx := context.Background()
+	user := &model.User{ID: "user1", Timezone: "Asia/Jakarta", QuietHoursStart: "22:00", QuietHoursEnd: "07:00"}
+
+	userRepo := new(mocks.IUserRepository)
+	userRepo.On("FindByID", ctx, "user1").Return(user, nil)
+
+	preferenceRepo := new(mocks.INotificationPreferenceRepository)
+	preferenceRepo.On("FindByUserID", ctx, "user1").Return([]model.NotificationPreference{
+		{UserID: "user1", Type: model.NotificationTypeMessage, Channel: model.NotificationChannelPush, Enabled: false},
+	}, nil)
+
+	service := NewNotificationPreferenceService(userRepo, preferenceRepo)
+	preferences, err := service.Find(ctx, "user1")
+	assert.NoError(t, err)
+
+	assert.Equal(t, &model.QuietHours{Start: "22:00", End: "07:00"}, preferences.QuietHours)
+	assert.Equal(t, "Asia/Jakarta", preferences.Timezone)
+
+	enabled := map[string]bool{}
+	for _, preference := range preferences.Preferences {
+		assert.False(t, model.NotificationTypes[preference.Type].Transactional, preference.Type)
+		enabled[preference.Type+"/"+preference.Channel] = preference.Enabled
+	}
+	assert.Equal(t, map[string]bool{
+		"LIKE_RECEIVED/PUSH": true,
+		"MATCH/PUSH":         true,
+		"MATCH_EXPIRED/PUSH": true,
+		"MESSAGE/PUSH":       false,
+	}, enabled)
+}
+
+func TestNotificationPreferenceService_Update(t *testing.T) {
+	ctx := context.Background()
+	off := false
+
+	tests := []struct {
+		name          string
+		preference    model.NotificationPreferenceRequest
+		setupMocks    func(*mocks.INotificationPreferenceRepository)
+		expectedError error
+	}{
+		{
+			name:       "Success",
+			preference: model.NotificationPreferenceRequest{Type: model.NotificationTypeMessage, Channel: model.NotificationChannelPush, Enabled: &off},
+			setupMocks: func(pr *mocks.INotificationPreferenceRepository) {
+				pr.On("Upsert", ctx, mock.MatchedBy(func(preferences []model.NotificationPreference) bool {
+					return len(preferences) == 1 && preferences[0].UserID == "user1" && preferences[0].Type == model.NotificationTypeMessage && !preferences[0].Enabled
+				})).Return(nil)
+				pr.On("FindByUserID", ctx, "user1").Return(nil, nil)
+			},
+		},
+		{
+			name:          "Error - Transactional",
+			preference:    model.NotificationPreferenceRequest{Type: model.NotificationTypePaymentFailed, Channel: model.NotificationChannelEmail, Enabled: &off},
+			setupMocks:    func(pr *mocks.INotificationPreferenceRepository) {},
+			expectedError: ErrNotificationPreferenceNotAllowed,
+		},
+		{
+			name:          "Error - Not Sent On That Channel",
+			preference:    model.NotificationPreferenceRequest{Type: model.NotificationTypeMatch, Channel: model.NotificationChannelEmail, Enabled: &off},
+			setupMocks:    func(pr *mocks.INotificationPreferenceRepository) {},
+			expectedError: ErrNotificationPreferenceNotAllowed,
+		},
+		{
+			name:          "Error - Unknown Type",
+			preference:    model.NotificationPreferenceRequest{Type: "POKE", Channel: model.NotificationChannelPush, Enabled: &off},
+			setupMocks:    func(pr *mocks.INotificationPreferenceRepository) {},
+			expectedError: ErrNotificationPreferenceNotAllowed,
+		},
+		{
+			name:       "Error - DB Error",
+			preference: model.NotificationPreferenceRequest{Type: model.NotificationTypeMessage, Channel: model.NotificationChannelPush, Enabled: &off},
+			setupMocks: func(pr *mocks.INotificationPreferenceRepository) {
+				pr.On("Upsert", ctx, mock.Anything).Return(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to update notification preferences"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			userRepo.On("FindByID", ctx, "user1").Return(&model.User{ID: "user1"}, nil).Maybe()
+
+			preferenceRepo := new(mocks.INotificationPreferenceRepository)
+			tt.setupMocks(preferenceRepo)
+
+			service := NewNotificationPreferenceService(userRepo, preferenceRepo)
+			_, err := service.Update(ctx, "user1", model.UpdateNotificationPreferencesRequest{Preferences: []model.NotificationPreferenceRequest{tt.preference}})
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			preferenceRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationPreferenceService_UpdateQuietHours(t *testing.T) {
+	ctx := context.Background()
+	req := model.UpdateQuietHoursRequest{Start: "22:00", End: "07:00"}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IUserRepository)
+		expected      *model.QuietHours
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(ur *mocks.IUserRepository) {
+				ur.On("UpdateQuietHours", ctx, "user1", "22:00", "07:00", mock.AnythingOfType("time.Time")).Return(nil)
+				ur.On("FindByID", ctx, "user1").Return(&model.User{ID: "user1", QuietHoursStart: "22:00", QuietHoursEnd: "07:00"}, nil)
+			},
+			expected: &model.QuietHours{Start: "22:00", End: "07:00"},
+		},
+		{
+			name: "Error - DB Error",
+			setupMocks: func(ur *mocks.IUserRepository) {
+				ur.On("UpdateQuietHours", ctx, "user1", "22:00", "07:00", mock.AnythingOfType("time.Time")).Return(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to update quiet hours"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			tt.setupMocks(userRepo)
+
+			service := NewNotificationPreferenceService(userRepo, noPreferences())
+			preferences, err := service.UpdateQuietHours(ctx, "user1", req)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, preferences.QuietHours)
+			}
+
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
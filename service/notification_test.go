@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/marvelalexius/jones/mocks"
+	"github.com/marvelalexius/jones/model"
+	"github.com/oklog/ulid/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNotificationService_FindMissed(t *testing.T) {
+	ctx := context.Background()
+	lastEventID := "41"
+	missed := []model.Notification{{ID: ulid.Make().String(), UserID: "user1", Seq: 42}}
+
+	tests := []struct {
+		name          string
+		lastEventID   string
+		setupMocks    func(*mocks.INotificationRepository)
+		expected      []model.Notification
+		expectedError error
+	}{
+		{
+			name:        "Success",
+			lastEventID: lastEventID,
+			setupMocks: func(nr *mocks.INotificationRepository) {
+				nr.On("FindAfter", ctx, "user1", int64(41), model.NotificationReplayLimit).Return(missed, nil)
+			},
+			expected: missed,
+		},
+		{
+			name:          "Error - Invalid Last Event ID",
+			lastEventID:   ulid.Make().String(),
+			setupMocks:    func(nr *mocks.INotificationRepository) {},
+			expectedError: ErrInvalidLastEventID,
+		},
+		{
+			name:          "Error - Negative Last Event ID",
+			lastEventID:   "-1",
+			setupMocks:    func(nr *mocks.INotificationRepository) {},
+			expectedError: ErrInvalidLastEventID,
+		},
+		{
+			name:        "Error - DB Error",
+			lastEventID: lastEventID,
+			setupMocks: func(nr *mocks.INotificationRepository) {
+				nr.On("FindAfter", ctx, "user1", int64(41), model.NotificationReplayLimit).Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("failed to find notifications"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notificationRepo := new(mocks.INotificationRepository)
+			tt.setupMocks(notificationRepo)
+
+			service := NewNotificationService(notificationRepo)
+			notifications, err := service.FindMissed(ctx, "user1", tt.lastEventID)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, notifications)
+			}
+
+			notificationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+// every type needs copy in every language, and has to render from its zero payload
+func TestNotificationCopy(t *testing.T) {
+	languages := []string{"en", "id"}
+
+	for notificationType, kind := range model.NotificationTypes {
+		payload := kind.NewPayload()
+		assert.Equal(t, notificationType, payload.NotificationType())
+
+		for _, language := range languages {
+			assert.True(t, notificationCopy.Has(language, notificationType), "%s has no %s copy", notificationType, language)
+
+			copy, err := notificationCopy.Render(language, notificationType, payload)
+			assert.NoError(t, err, notificationType)
+			assert.NotEmpty(t, copy.Title, notificationType)
+			assert.NotEmpty(t, copy.Body, notificationType)
+		}
+	}
+}
+
+// rows stored before billing notifications were split by event still decode
+func TestDecodeNotificationPayload_LegacySubscription(t *testing.T) {
+	payload, err := model.DecodeNotificationPayload(model.NotificationTypeSubscription, []byte(`{}`))
+	assert.NoError(t, err)
+	assert.Equal(t, &model.LegacySubscriptionPayload{}, payload)
+	assert.False(t, model.ConfigurableChannel(model.NotificationTypeSubscription, model.NotificationChannelPush))
+}
+
+func TestNotificationService_FindAll(t *testing.T) {
+	ctx := context.Background()
+	notifications := []model.Notification{{ID: ulid.Make().String(), UserID: "user1", Type: model.NotificationTypeMessage}}
+
+	tests := []struct {
+		name          string
+		request       model.NotificationRequest
+		setupMocks    func(*mocks.INotificationRepository)
+		expected      []model.Notification
+		expectedTotal int64
+		expectedError error
+	}{
+		{
+			name:    "Success - Defaults To The First Page",
+			request: model.NotificationRequest{},
+			setupMocks: func(nr *mocks.INotificationRepository) {
+				nr.On("FindByUserID", ctx, "user1", "", model.PaginationRequest{Page: 1, Limit: model.DefaultPageLimit}).Return(notifications, int64(1), nil)
+			},
+			expected:      notifications,
+			expectedTotal: 1,
+		},
+		{
+			name:    "Success - Filtered By Type",
+			request: model.NotificationRequest{PaginationRequest: model.PaginationRequest{Page: 2, Limit: 10}, Type: model.NotificationTypeMessage},
+			setupMocks: func(nr *mocks.INotificationRepository) {
+				nr.On("FindByUserID", ctx, "user1", model.NotificationTypeMessage, model.PaginationRequest{Page: 2, Limit: 10}).Return(notifications, int64(11), nil)
+			},
+			expected:      notifications,
+			expectedTotal: 11,
+		},
+		{
+			name:    "Error - DB Error",
+			request: model.NotificationRequest{},
+			setupMocks: func(nr *mocks.INotificationRepository) {
+				nr.On("FindByUserID", ctx, "user1", "", mock.Anything).Return(nil, int64(0), errors.New("db error"))
+			},
+			expectedError: errors.New("failed to find notifications"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notificationRepo := new(mocks.INotificationRepository)
+			tt.setupMocks(notificationRepo)
+
+			service := NewNotificationService(notificationRepo)
+			notifications, total, err := service.FindAll(ctx, "user1", tt.request)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, notifications)
+				assert.Equal(t, tt.expectedTotal, total)
+			}
+
+			notificationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationService_MarkRead(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.INotificationRepository)
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(nr *mocks.INotificationRepository) {
+				nr.On("MarkRead", ctx, "user1", "notification1", mock.AnythingOfType("time.Time")).Return(true, nil)
+			},
+		},
+		{
+			name: "Error - Someone Else's Notification",
+			setupMocks: func(nr *mocks.INotificationRepository) {
+				nr.On("MarkRead", ctx, "user1", "notification1", mock.AnythingOfType("time.Time")).Return(false, nil)
+			},
+			expectedError: ErrNotificationNotFound,
+		},
+		{
+			name: "Error - DB Error",
+			setupMocks: func(nr *mocks.INotificationRepository) {
+				nr.On("MarkRead", ctx, "user1", "notification1", mock.AnythingOfType("time.Time")).Return(false, errors.New("db error"))
+			},
+			expectedError: errors.New("failed to mark notification as read"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notificationRepo := new(mocks.INotificationRepository)
+			tt.setupMocks(notificationRepo)
+
+			service := NewNotificationService(notificationRepo)
+			err := service.MarkRead(ctx, "user1", "notification1")
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			notificationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationService_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.INotificationRepository)
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(nr *mocks.INotificationRepository) {
+				nr.On("Delete", ctx, "user1", "notification1", mock.AnythingOfType("time.Time")).Return(true, nil)
+			},
+		},
+		{
+			name: "Error - Already Deleted",
+			setupMocks: func(nr *mocks.INotificationRepository) {
+				nr.On("Delete", ctx, "user1", "notification1", mock.AnythingOfType("time.Time")).Return(false, nil)
+			},
+			expectedError: ErrNotificationNotFound,
+		},
+		{
+			name: "Error - DB Error",
+			setupMocks: func(nr *mocks.INotificationRepository) {
+				nr.On("Delete", ctx, "user1", "notification1", mock.AnythingOfType("time.Time")).Return(false, errors.New("db error"))
+			},
+			expectedError: errors.New("failed to delete notification"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notificationRepo := new(mocks.INotificationRepository)
+			tt.setupMocks(notificationRepo)
+
+			service := NewNotificationService(notificationRepo)
+			err := service.Delete(ctx, "user1", "notification1")
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			notificationRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/utils/logger"
+)
+
+const outboxBatchSize = 100
+
+type (
+	// OutboxWorker sends what services wrote to the outbox. Messages are delivered at least once, a failed one is
+	// retried with exponential backoff and moved to the dead letters when it runs out of attempts.
+	OutboxWorker struct {
+		Transactor  repository.ITransactor
+		OutboxRepo  repository.IOutboxRepository
+		Dispatcher  INotificationDispatcher
+		MaxAttempts int
+	}
+
+	IOutboxWorker interface {
+		Drain(ctx context.Context) (int, error)
+		Run(ctx context.Context, interval time.Duration)
+	}
+
+	// permanentError is a message that fails the same way every time it's tried, so it isn't.
+	permanentError struct {
+		error
+	}
+)
+
+func NewOutboxWorker(transactor repository.ITransactor, outboxRepo repository.IOutboxRepository, dispatcher INotificationDispatcher, maxAttempts int) IOutboxWorker {
+	return &OutboxWorker{Transactor: transactor, OutboxRepo: outboxRepo, Dispatcher: dispatcher, MaxAttempts: maxAttempts}
+}
+
+// Run drains the outbox until ctx is done, waiting for the interval whenever it's caught up.
+func (w *OutboxWorker) Run(ctx context.Context, interval time.Duration) {
+	for {
+		processed, err := w.Drain(ctx)
+		if err != nil {
+			logger.Errorln(ctx, "failed to drain outbox", err)
+		}
+
+		if processed == outboxBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Drain processes a batch of due messages and returns how many it claimed.
+func (w *OutboxWorker) Drain(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	// the lease tells the worker's messages apart, so it's kept to the microseconds the column stores
+	leaseUntil := now.Add(model.OutboxLease).Truncate(time.Microsecond)
+
+	messages, err := w.OutboxRepo.Claim(ctx, now, leaseUntil, outboxBatchSize)
+	if err != nil {
+		logger.Errorln(ctx, "failed to claim outbox messages", err)
+
+		return 0, errors.New("failed to claim outbox messages")
+	}
+
+	for _, message := range messages {
+		// the lease was taken for the whole batch, so the messages near its end may be running late
+		leased, err := w.OutboxRepo.Renew(ctx, message.ID, leaseUntil, time.Now().Add(model.OutboxLease).Truncate(time.Microsecond))
+		if err != nil {
+			logger.Errorln(ctx, "failed to renew outbox message lease", message.ID, err)
+
+			continue
+		}
+
+		if !leased {
+			continue
+		}
+
+		err = w.process(ctx, message)
+		if err != nil {
+			w.fail(ctx, message, err, time.Now())
+
+			continue
+		}
+
+		// it's sent again when the lease runs out, which is what at least once means
+		err = w.OutboxRepo.Delete(ctx, message.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete sent outbox message", message.ID, err)
+		}
+	}
+
+	return len(messages), nil
+}
+
+func (w *OutboxWorker) process(ctx context.Context, message model.OutboxMessage) error {
+	switch message.Topic {
+	case model.OutboxTopicNotification:
+		var notification model.NotificationMessage
+		err := json.Unmarshal([]byte(message.Payload), &notification)
+		if err != nil {
+			return permanentError{err}
+		}
+
+		payload, err := model.DecodeNotificationPayload(notification.Type, notification.Data)
+		if err != nil {
+			return permanentError{err}
+		}
+
+		// the notification keeps the message's ID, so a message that's sent again isn't stored twice
+		return w.Dispatcher.Dispatch(ctx, message.ID, notification.UserID, notification.ReferenceID, payload)
+	case model.OutboxTopicDeferredNotification:
+		var deferred model.DeferredNotification
+		err := json.Unmarshal([]byte(message.Payload), &deferred)
+		if err != nil {
+			return permanentError{err}
+		}
+
+		return w.Dispatcher.SendDeferred(ctx, deferred)
+	default:
+		return permanentError{fmt.Errorf("unknown outbox topic %q", message.Topic)}
+	}
+}
+
+func (w *OutboxWorker) fail(ctx context.Context, message model.OutboxMessage, cause error, now time.Time) {
+	message.Attempts++
+	message.LastError = cause.Error()
+
+	var permanent permanentError
+	if message.Attempts < w.MaxAttempts && !errors.As(cause, &permanent) {
+		err := w.OutboxRepo.Retry(ctx, message.ID, message.Attempts, message.LastError, message.RetryAt(now))
+		if err != nil {
+			logger.Errorln(ctx, "failed to reschedule outbox message", message.ID, err)
+		}
+
+		return
+	}
+
+	logger.Errorln(ctx, "outbox message can't be sent", message.ID, cause)
+
+	err := w.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		err := w.OutboxRepo.CreateDeadLetter(ctx, model.NewDeadLetter(message, now))
+		if err != nil {
+			return err
+		}
+
+		return w.OutboxRepo.Delete(ctx, message.ID)
+	})
+	if err != nil {
+		logger.Errorln(ctx, "failed to dead letter outbox message", message.ID, err)
+	}
+}
+
+// enqueueNotification writes the notification to the outbox. Called with a transaction's ctx, it's only sent once
+// the transaction commits.
+func enqueueNotification(ctx context.Context, outboxRepo repository.IOutboxRepository, userID, referenceID string, payload model.NotificationPayload) error {
+	message, err := model.NewNotificationMessage(userID, referenceID, payload, time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = outboxRepo.Create(ctx, message)
+	if err != nil {
+		logger.Errorln(ctx, "failed to enqueue notification", err)
+
+		return errors.New("failed to enqueue notification")
+	}
+
+	return nil
+}
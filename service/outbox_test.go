@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/marvelalexius/jones/mocks"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/realtime"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// relayOutbox dispatches messages as soon as they're written, so services can be tested against the
+// notification repo as if there was no outbox in between.
+type relayOutbox struct {
+	repository.IOutboxRepository
+	worker *OutboxWorker
+}
+
+func (o relayOutbox) Create(ctx context.Context, message model.OutboxMessage) error {
+	return o.worker.process(ctx, message)
+}
+
+func (o relayOutbox) DeleteByReferenceID(ctx context.Context, referenceID string) error {
+	return nil
+}
+
+func (o relayOutbox) DeleteByReferenceIDs(ctx context.Context, referenceIDs []string) error {
+	return nil
+}
+
+// txOutbox is both the transactor and the outbox. It keeps what a transaction writes to or drops from the outbox
+// until the transaction commits and refuses writes made outside of one, failAt fails the nth write.
+type txOutbox struct {
+	repository.IOutboxRepository
+
+	failAt    int
+	writes    int
+	pending   []model.OutboxMessage
+	dropped   []string
+	committed []model.OutboxMessage
+}
+
+func (o *txOutbox) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	o.pending, o.dropped = nil, nil
+
+	err := fn(context.WithValue(ctx, txKey{}, true))
+	if err == nil {
+		kept := []model.OutboxMessage{}
+		for _, message := range o.committed {
+			if !slices.Contains(o.dropped, message.ReferenceID) {
+				kept = append(kept, message)
+			}
+		}
+		o.committed = append(kept, o.pending...)
+	}
+	o.pending, o.dropped = nil, nil
+
+	return err
+}
+
+func (o *txOutbox) DeleteByReferenceIDs(ctx context.Context, referenceIDs []string) error {
+	if !inTransaction(ctx) {
+		return errors.New("outbox written outside a transaction")
+	}
+
+	o.dropped = append(o.dropped, referenceIDs...)
+
+	return nil
+}
+
+func (o *txOutbox) Create(ctx context.Context, message model.OutboxMessage) error {
+	if !inTransaction(ctx) {
+		return errors.New("outbox written outside a transaction")
+	}
+
+	o.writes++
+	if o.writes == o.failAt {
+		return errors.New("db error")
+	}
+
+	o.pending = append(o.pending, message)
+
+	return nil
+}
+
+func inTransaction(ctx context.Context) bool {
+	return ctx.Value(txKey{}) != nil
+}
+
+func testOutbox(userRepo repository.IUserRepository, notificationRepo repository.INotificationRepository, publisher realtime.IPublisher) repository.IOutboxRepository {
+	return relayOutbox{worker: &OutboxWorker{Dispatcher: testDispatcher(userRepo, notificationRepo, publisher)}}
+}
+
+func outboxMessage(t *testing.T, attempts int) model.OutboxMessage {
+	message, err := model.NewNotificationMessage("user1", "reaction1", model.LikeReceivedPayload{UserID: "user2", Super: true}, time.Now())
+	assert.NoError(t, err)
+
+	message.Attempts = attempts
+
+	return message
+}
+
+var deferredMessageItem = model.DeferredNotification{ID: "deferred1", NotificationID: "notification1", ReferenceID: "reaction1", UserID: "user1", Channel: model.NotificationChannelPush, Type: model.NotificationTypeLikeReceived, Title: "Someone likes you"}
+
+func deferredMessage(t *testing.T) model.OutboxMessage {
+	message, err := model.NewDeferredNotificationMessage(deferredMessageItem, time.Now())
+	assert.NoError(t, err)
+
+	return message
+}
+
+func TestOutboxWorker_Drain(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		messages  []model.OutboxMessage
+		claimErr  error
+		lost      bool
+		deliver   error
+		mock      func(outboxRepo *mocks.IOutboxRepository, messages []model.OutboxMessage)
+		processed int
+		wantErr   bool
+	}{
+		{
+			name:     "Success - Sent Messages Are Deleted",
+			messages: []model.OutboxMessage{outboxMessage(t, 0)},
+			mock: func(outboxRepo *mocks.IOutboxRepository, messages []model.OutboxMessage) {
+				outboxRepo.On("Delete", mock.Anything, messages[0].ID).Return(nil).Once()
+			},
+			processed: 1,
+		},
+		{
+			name:      "Success - Message Taken Over By Another Worker Is Skipped",
+			messages:  []model.OutboxMessage{outboxMessage(t, 0)},
+			lost:      true,
+			mock:      func(outboxRepo *mocks.IOutboxRepository, messages []model.OutboxMessage) {},
+			processed: 1,
+		},
+		{
+			name:     "Success - Failed Message Is Retried With Backoff",
+			messages: []model.OutboxMessage{outboxMessage(t, 2)},
+			deliver:  errors.New("db down"),
+			mock: func(outboxRepo *mocks.IOutboxRepository, messages []model.OutboxMessage) {
+				outboxRepo.On("Retry", mock.Anything, messages[0].ID, 3, "db down", mock.MatchedBy(func(availableAt time.Time) bool {
+					wait := time.Until(availableAt)
+
+					return wait > 15*time.Second && wait <= 20*time.Second
+				})).Return(nil).Once()
+			},
+			processed: 1,
+		},
+		{
+			name:     "Success - Message Out Of Attempts Is Dead Lettered",
+			messages: []model.OutboxMessage{outboxMessage(t, 4)},
+			deliver:  errors.New("db down"),
+			mock: func(outboxRepo *mocks.IOutboxRepository, messages []model.OutboxMessage) {
+				outboxRepo.On("CreateDeadLetter", mock.Anything, mock.MatchedBy(func(deadLetter model.DeadLetter) bool {
+					return deadLetter.ID == messages[0].ID && deadLetter.Attempts == 5 && deadLetter.LastError == "db down"
+				})).Return(nil).Once()
+				outboxRepo.On("Delete", mock.Anything, messages[0].ID).Return(nil).Once()
+			},
+			processed: 1,
+		},
+		{
+			name:     "Success - Deferred Notification Is Sent",
+			messages: []model.OutboxMessage{deferredMessage(t)},
+			mock: func(outboxRepo *mocks.IOutboxRepository, messages []model.OutboxMessage) {
+				outboxRepo.On("Delete", mock.Anything, messages[0].ID).Return(nil).Once()
+			},
+			processed: 1,
+		},
+		{
+			name:     "Success - Unknown Topic Is Dead Lettered Right Away",
+			messages: []model.OutboxMessage{{ID: "message1", Topic: "UNKNOWN"}},
+			mock: func(outboxRepo *mocks.IOutboxRepository, messages []model.OutboxMessage) {
+				outboxRepo.On("CreateDeadLetter", mock.Anything, mock.MatchedBy(func(deadLetter model.DeadLetter) bool {
+					return deadLetter.ID == "message1" && deadLetter.Attempts == 1 && deadLetter.LastError == `unknown outbox topic "UNKNOWN"`
+				})).Return(nil).Once()
+				outboxRepo.On("Delete", mock.Anything, "message1").Return(nil).Once()
+			},
+			processed: 1,
+		},
+		{
+			name:     "Success - Unknown Notification Type Is Dead Lettered Right Away",
+			messages: []model.OutboxMessage{{ID: "message1", Topic: model.OutboxTopicNotification, Payload: `{"user_id":"user1","type":"UNKNOWN","data":{}}`}},
+			mock: func(outboxRepo *mocks.IOutboxRepository, messages []model.OutboxMessage) {
+				outboxRepo.On("CreateDeadLetter", mock.Anything, mock.MatchedBy(func(deadLetter model.DeadLetter) bool {
+					return deadLetter.ID == "message1" && deadLetter.Attempts == 1 && deadLetter.LastError == `unknown notification type "UNKNOWN"`
+				})).Return(nil).Once()
+				outboxRepo.On("Delete", mock.Anything, "message1").Return(nil).Once()
+			},
+			processed: 1,
+		},
+		{
+			name:     "Success - Malformed Payload Is Dead Lettered Right Away",
+			messages: []model.OutboxMessage{{ID: "message1", Topic: model.OutboxTopicDeferredNotification, Payload: "{"}},
+			mock: func(outboxRepo *mocks.IOutboxRepository, messages []model.OutboxMessage) {
+				outboxRepo.On("CreateDeadLetter", mock.Anything, mock.MatchedBy(func(deadLetter model.DeadLetter) bool {
+					return deadLetter.ID == "message1" && deadLetter.Attempts == 1
+				})).Return(nil).Once()
+				outboxRepo.On("Delete", mock.Anything, "message1").Return(nil).Once()
+			},
+			processed: 1,
+		},
+		{
+			name:     "Failed - Claim Error",
+			claimErr: errors.New("db down"),
+			mock:     func(outboxRepo *mocks.IOutboxRepository, messages []model.OutboxMessage) {},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claimed time.Time
+			outboxRepo := new(mocks.IOutboxRepository)
+			outboxRepo.On("Claim", mock.Anything, mock.Anything, mock.Anything, outboxBatchSize).Run(func(args mock.Arguments) {
+				claimed = args.Get(2).(time.Time)
+			}).Return(tt.messages, tt.claimErr).Once()
+			tt.mock(outboxRepo, tt.messages)
+
+			dispatcher := new(mocks.INotificationDispatcher)
+			for _, message := range tt.messages {
+				// only the lease this worker took is renewed, once another worker claimed the message it's theirs
+				outboxRepo.On("Renew", mock.Anything, message.ID, mock.MatchedBy(func(leasedUntil time.Time) bool {
+					return leasedUntil.Equal(claimed) && leasedUntil.Equal(leasedUntil.Truncate(time.Microsecond))
+				}), mock.MatchedBy(func(leaseUntil time.Time) bool {
+					return time.Until(leaseUntil) > model.OutboxLease-time.Second
+				})).Return(!tt.lost, nil).Once()
+
+				// the notification takes the message's ID, so sending it again doesn't store it twice
+				dispatcher.On("Dispatch", mock.Anything, message.ID, "user1", "reaction1", &model.LikeReceivedPayload{UserID: "user2", Super: true}).Return(tt.deliver).Maybe()
+			}
+			dispatcher.On("SendDeferred", mock.Anything, deferredMessageItem).Return(tt.deliver).Maybe()
+
+			worker := NewOutboxWorker(passthroughTransactor(), outboxRepo, dispatcher, 5)
+
+			processed, err := worker.Drain(ctx)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.processed, processed)
+			outboxRepo.AssertExpectations(t)
+			if tt.lost {
+				dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/utils/logger"
+	"github.com/marvelalexius/jones/utils/str"
+)
+
+type (
+	QuotaService struct {
+		UserRepo         repository.IUserRepository
+		ReactionRepo     repository.IReactionRepository
+		SubscriptionRepo repository.ISubscriptionRepository
+		BoostRepo        repository.IBoostRepository
+	}
+
+	IQuotaService interface {
+		FindPlan(ctx context.Context, userID string) (*model.SubscriptionPlan, error)
+		Check(ctx context.Context, userID string, plan *model.SubscriptionPlan, quotaType string) (model.Quota, error)
+		FindAll(ctx context.Context, userID string) ([]model.Quota, error)
+	}
+)
+
+func NewQuotaService(userRepo repository.IUserRepository, reactionRepo repository.IReactionRepository, subscriptionRepo repository.ISubscriptionRepository, boostRepo repository.IBoostRepository) IQuotaService {
+	return &QuotaService{UserRepo: userRepo, ReactionRepo: reactionRepo, SubscriptionRepo: subscriptionRepo, BoostRepo: boostRepo}
+}
+
+// FindPlan returns the plan the user's limits come from, users without a subscription get the FREE plan.
+// Limits are read from subscription_plans on every call so they can be changed without a deploy.
+func (s *QuotaService) FindPlan(ctx context.Context, userID string) (*model.SubscriptionPlan, error) {
+	plan, err := findActivePlan(ctx, s.SubscriptionRepo, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find active plan", err)
+
+		return nil, errors.New("failed to check subscription")
+	}
+
+	if plan != nil {
+		return plan, nil
+	}
+
+	plan, err = s.SubscriptionRepo.FindPlanByName(ctx, model.SubscriptionPlanFree)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find free plan", err)
+
+		return nil, errors.New("failed to find plan")
+	}
+
+	return plan, nil
+}
+
+// Check returns the quota of the current period, periods start at midnight in the user's timezone.
+func (s *QuotaService) Check(ctx context.Context, userID string, plan *model.SubscriptionPlan, quotaType string) (model.Quota, error) {
+	user, err := s.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find user", err)
+
+		return model.Quota{}, errors.New("failed to find user")
+	}
+
+	now := time.Now()
+	startOfDay, endOfDay := str.GetDayTimeRange(now, user.Location())
+	tomorrow := endOfDay.Add(time.Nanosecond)
+
+	switch quotaType {
+	case model.QuotaSwipe:
+		used, err := s.ReactionRepo.FindSwipeCount(ctx, userID, startOfDay)
+		if err != nil {
+			logger.Errorln(ctx, "failed to check swipe count", err)
+
+			return model.Quota{}, errors.New("failed to check swipe count")
+		}
+
+		return model.NewQuota(quotaType, plan.DailySwipeLimit, used, tomorrow), nil
+	case model.QuotaSuperLike:
+		since, resetAt := startOfDay, tomorrow
+		if plan.SuperLikePeriod == model.QuotaPeriodWeekly {
+			startOfWeek, endOfWeek := str.GetWeekTimeRangeIn(now, user.Location())
+			since, resetAt = startOfWeek, endOfWeek.Add(time.Nanosecond)
+		}
+
+		used, err := s.ReactionRepo.CountByTypeSince(ctx, userID, model.ReactionSuperLike, since)
+		if err != nil {
+			logger.Errorln(ctx, "failed to count super likes", err)
+
+			return model.Quota{}, errors.New("failed to check super like quota")
+		}
+
+		limit := plan.SuperLikeQuota
+
+		return model.NewQuota(quotaType, &limit, used, resetAt), nil
+	case model.QuotaRewind:
+		limit := plan.DailyRewindLimit
+		if !model.HasFeature(plan.Name, model.FeatureRewind) {
+			none := 0
+			limit = &none
+		}
+
+		used, err := s.ReactionRepo.CountRewoundSince(ctx, userID, startOfDay)
+		if err != nil {
+			logger.Errorln(ctx, "failed to count rewinds", err)
+
+			return model.Quota{}, errors.New("failed to check rewind count")
+		}
+
+		return model.NewQuota(quotaType, limit, used, tomorrow), nil
+	case model.QuotaBoost:
+		startOfWeek, endOfWeek := str.GetWeekTimeRangeIn(now, user.Location())
+
+		used, err := s.BoostRepo.CountSince(ctx, userID, startOfWeek)
+		if err != nil {
+			logger.Errorln(ctx, "failed to count boosts", err)
+
+			return model.Quota{}, errors.New("failed to check boost quota")
+		}
+
+		limit := plan.WeeklyBoostQuota
+
+		return model.NewQuota(quotaType, &limit, used, endOfWeek.Add(time.Nanosecond)), nil
+	}
+
+	return model.Quota{}, errors.New("unknown quota type")
+}
+
+func (s *QuotaService) FindAll(ctx context.Context, userID string) ([]model.Quota, error) {
+	plan, err := s.FindPlan(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := make([]model.Quota, 0, len(model.QuotaTypes))
+	for _, quotaType := range model.QuotaTypes {
+		quota, err := s.Check(ctx, userID, plan, quotaType)
+		if err != nil {
+			return nil, err
+		}
+
+		quotas = append(quotas, quota)
+	}
+
+	return quotas, nil
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marvelalexius/jones/mocks"
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/utils/str"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestQuotaService_FindPlan(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.ISubscriptionRepository)
+		expectedPlan  string
+		expectedError error
+	}{
+		{
+			name: "Success - Subscribed Plan",
+			setupMocks: func(sr *mocks.ISubscriptionRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{ID: "sub1", PlanID: 2}, nil)
+				sr.On("FindPlanByID", mock.Anything, 2).Return(&model.SubscriptionPlan{ID: 2, Name: model.SubscriptionPlanPro}, nil)
+			},
+			expectedPlan: model.SubscriptionPlanPro,
+		},
+		{
+			name: "Success - Free Plan Without Subscription",
+			setupMocks: func(sr *mocks.ISubscriptionRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(nil, gorm.ErrRecordNotFound)
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+			},
+			expectedPlan: model.SubscriptionPlanFree,
+		},
+		{
+			name: "Error - Free Plan Missing",
+			setupMocks: func(sr *mocks.ISubscriptionRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(nil, gorm.ErrRecordNotFound)
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(nil, gorm.ErrRecordNotFound)
+			},
+			expectedError: errors.New("failed to find plan"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscriptionRepo := new(mocks.ISubscriptionRepository)
+			tt.setupMocks(subscriptionRepo)
+
+			service := NewQuotaService(new(mocks.IUserRepository), new(mocks.IReactionRepository), subscriptionRepo, new(mocks.IBoostRepository))
+			plan, err := service.FindPlan(context.Background(), "user1")
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedPlan, plan.Name)
+			}
+			subscriptionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestQuotaService_Check(t *testing.T) {
+	rewindLimit := 5
+	proPlan := &model.SubscriptionPlan{ID: 2, Name: model.SubscriptionPlanPro, SuperLikeQuota: 5, SuperLikePeriod: model.QuotaPeriodDaily, DailyRewindLimit: &rewindLimit}
+
+	tests := []struct {
+		name              string
+		plan              *model.SubscriptionPlan
+		quotaType         string
+		setupMocks        func(*mocks.IReactionRepository)
+		expectedLimit     *int
+		expectedRemaining *int64
+		expectedExceeded  bool
+		expectedError     error
+	}{
+		{
+			name:      "Swipe - Free Plan Has Swipes Left",
+			plan:      freePlan(),
+			quotaType: model.QuotaSwipe,
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(4), nil)
+			},
+			expectedLimit:     intPtr(10),
+			expectedRemaining: int64Ptr(6),
+		},
+		{
+			name:      "Swipe - Free Plan Limit Reached",
+			plan:      freePlan(),
+			quotaType: model.QuotaSwipe,
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(12), nil)
+			},
+			expectedLimit:     intPtr(10),
+			expectedRemaining: int64Ptr(0),
+			expectedExceeded:  true,
+		},
+		{
+			name:      "Swipe - Unlimited Plan",
+			plan:      proPlan,
+			quotaType: model.QuotaSwipe,
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(100), nil)
+			},
+		},
+		{
+			name:      "Super Like - Weekly Quota Used Up",
+			plan:      freePlan(),
+			quotaType: model.QuotaSuperLike,
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("CountByTypeSince", mock.Anything, "user1", model.ReactionSuperLike, mock.AnythingOfType("time.Time")).Return(int64(1), nil)
+			},
+			expectedLimit:     intPtr(1),
+			expectedRemaining: int64Ptr(0),
+			expectedExceeded:  true,
+		},
+		{
+			name:      "Rewind - Plan Without Rewind Feature",
+			plan:      freePlan(),
+			quotaType: model.QuotaRewind,
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("CountRewoundSince", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+			},
+			expectedLimit:     intPtr(0),
+			expectedRemaining: int64Ptr(0),
+			expectedExceeded:  true,
+		},
+		{
+			name:      "Rewind - Pro Plan",
+			plan:      proPlan,
+			quotaType: model.QuotaRewind,
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("CountRewoundSince", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(2), nil)
+			},
+			expectedLimit:     intPtr(5),
+			expectedRemaining: int64Ptr(3),
+		},
+		{
+			name:      "Error - Count Swipes",
+			plan:      freePlan(),
+			quotaType: model.QuotaSwipe,
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("db error"))
+			},
+			expectedError: errors.New("failed to check swipe count"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reactionRepo := new(mocks.IReactionRepository)
+			tt.setupMocks(reactionRepo)
+
+			service := NewQuotaService(utcUserRepo(), reactionRepo, new(mocks.ISubscriptionRepository), new(mocks.IBoostRepository))
+			quota, err := service.Check(context.Background(), "user1", tt.plan, tt.quotaType)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedLimit, quota.Limit)
+				assert.Equal(t, tt.expectedRemaining, quota.Remaining)
+				assert.Equal(t, tt.expectedExceeded, quota.Exceeded())
+				assert.True(t, quota.ResetAt.After(time.Now()))
+			}
+			reactionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestQuotaService_Check_UserTimezone(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	expectedSince, _ := str.GetDayTimeRange(time.Now(), jakarta)
+
+	userRepo := new(mocks.IUserRepository)
+	userRepo.On("FindByID", mock.Anything, "user1").Return(&model.User{ID: "user1", Timezone: "Asia/Jakarta"}, nil)
+
+	reactionRepo := new(mocks.IReactionRepository)
+	reactionRepo.On("FindSwipeCount", mock.Anything, "user1", mock.MatchedBy(func(since time.Time) bool {
+		return since.Equal(expectedSince)
+	})).Return(int64(3), nil)
+
+	service := NewQuotaService(userRepo, reactionRepo, new(mocks.ISubscriptionRepository), new(mocks.IBoostRepository))
+	quota, err := service.Check(context.Background(), "user1", freePlan(), model.QuotaSwipe)
+
+	assert.NoError(t, err)
+	assert.True(t, quota.ResetAt.Equal(expectedSince.AddDate(0, 0, 1)))
+	assert.Equal(t, 0, quota.ResetAt.In(jakarta).Hour())
+	reactionRepo.AssertExpectations(t)
+}
+
+func utcUserRepo() *mocks.IUserRepository {
+	userRepo := new(mocks.IUserRepository)
+	userRepo.On("FindByID", mock.Anything, mock.Anything).Return(&model.User{ID: "user1", Timezone: model.DefaultTimezone}, nil).Maybe()
+
+	return userRepo
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
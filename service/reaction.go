@@ -2,141 +2,417 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/marvelalexius/jones/config"
 	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/decktoken"
+	"github.com/marvelalexius/jones/pkg/imageproxy"
+	"github.com/marvelalexius/jones/pkg/moderation"
+	"github.com/marvelalexius/jones/pkg/realtime"
+	"github.com/marvelalexius/jones/pkg/signedurl"
 	"github.com/marvelalexius/jones/repository"
 	"github.com/marvelalexius/jones/utils/logger"
-	"github.com/oklog/ulid/v2"
-	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type (
 	ReactionService struct {
+		Conf             *config.Config
+		Transactor       repository.ITransactor
 		UserRepo         repository.IUserRepository
 		ReactionRepo     repository.IReactionRepository
 		SubscriptionRepo repository.ISubscriptionRepository
 		NotificationRepo repository.INotificationRepository
+		MatchRepo        repository.IMatchRepository
+		ConversationRepo repository.IConversationRepository
+		CreditRepo       repository.ICreditRepository
+		BoostRepo        repository.IBoostRepository
+		QuotaService     IQuotaService
+		Moderator        moderation.IModerator
+		DeckTokens       decktoken.ISigner
+		TeaserURLs       signedurl.ISigner
+		ImageProxy       imageproxy.IProxy
+		Publisher        realtime.IPublisher
+		OutboxRepo       repository.IOutboxRepository
+		DeferredRepo     repository.IDeferredNotificationRepository
 	}
 
 	IReactionService interface {
 		Swipe(ctx context.Context, reaction model.ReactionRequest) (model.Reaction, error)
+		BatchSwipe(ctx context.Context, userID string, reactions []model.ReactionRequest) ([]model.BatchReactionResult, error)
 		SeeLikes(ctx context.Context, userID string) ([]model.Reaction, error)
+		SummarizeLikes(ctx context.Context, userID string) (model.LikesSummary, error)
+		OpenLikeTeaser(ctx context.Context, id string, query url.Values) (*imageproxy.Image, error)
+		Rewind(ctx context.Context, userID string) (model.Reaction, error)
+		StartOver(ctx context.Context, userID string) error
+		FindSent(ctx context.Context, userID string, req model.SentReactionRequest) ([]model.SentReaction, int64, error)
 	}
 )
 
-func NewReactionService(userRepo repository.IUserRepository, reactionRepo repository.IReactionRepository, subscriptionRepo repository.ISubscriptionRepository, notificationRepo repository.INotificationRepository) IReactionService {
-	return &ReactionService{UserRepo: userRepo, ReactionRepo: reactionRepo, SubscriptionRepo: subscriptionRepo, NotificationRepo: notificationRepo}
+func NewReactionService(conf *config.Config, transactor repository.ITransactor, userRepo repository.IUserRepository, reactionRepo repository.IReactionRepository, subscriptionRepo repository.ISubscriptionRepository, notificationRepo repository.INotificationRepository, matchRepo repository.IMatchRepository, conversationRepo repository.IConversationRepository, creditRepo repository.ICreditRepository, boostRepo repository.IBoostRepository, quotaService IQuotaService, moderator moderation.IModerator, deckTokens decktoken.ISigner, teaserURLs signedurl.ISigner, imageProxy imageproxy.IProxy, publisher realtime.IPublisher, outboxRepo repository.IOutboxRepository, deferredRepo repository.IDeferredNotificationRepository) IReactionService {
+	return &ReactionService{Conf: conf, Transactor: transactor, UserRepo: userRepo, ReactionRepo: reactionRepo, SubscriptionRepo: subscriptionRepo, NotificationRepo: notificationRepo, MatchRepo: matchRepo, ConversationRepo: conversationRepo, CreditRepo: creditRepo, BoostRepo: boostRepo, QuotaService: quotaService, Moderator: moderator, DeckTokens: deckTokens, TeaserURLs: teaserURLs, ImageProxy: imageProxy, Publisher: publisher, OutboxRepo: outboxRepo, DeferredRepo: deferredRepo}
 }
 
 func (s *ReactionService) Swipe(ctx context.Context, req model.ReactionRequest) (model.Reaction, error) {
-	subscribed, err := s.SubscriptionRepo.FindByUserID(ctx, req.UserID)
+	plan, err := s.QuotaService.FindPlan(ctx, req.UserID)
 	if err != nil {
-		logger.Errorln(ctx, "failed to check subscription", err)
-
-		return model.Reaction{}, errors.New("failed to check subscription")
+		return model.Reaction{}, err
 	}
 
-	if subscribed.ID == "" {
-		count, err := s.ReactionRepo.FindSwipeCount(ctx, req.UserID)
-		if err != nil {
-			logger.Errorln(ctx, "failed to check swipe count", err)
+	swiper, err := s.UserRepo.FindByID(ctx, req.UserID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find swiper", err)
 
-			return model.Reaction{}, errors.New("failed to check swipe count")
-		}
+		return model.Reaction{}, errors.New("failed to find user")
+	}
 
-		if count >= 10 {
-			logger.Errorln(ctx, "cannot swipe more than 10 times", err)
+	return s.swipe(ctx, plan, swiper, req)
+}
 
-			return model.Reaction{}, errors.New("cannot swipe more than 10 times. please try again tomorrow")
-		}
+// BatchSwipe replays swipes queued by offline clients in the given order, applying the same rules as Swipe
+// and reporting a result for every item instead of failing the whole batch.
+func (s *ReactionService) BatchSwipe(ctx context.Context, userID string, reqs []model.ReactionRequest) ([]model.BatchReactionResult, error) {
+	plan, err := s.QuotaService.FindPlan(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	hasSwiped, err := s.ReactionRepo.HasSwiped(ctx, req.UserID, req.MatchedUserID)
+	swiper, err := s.UserRepo.FindByID(ctx, userID)
 	if err != nil {
-		logger.Errorln(ctx, "failed to check if user has swiped", err)
+		logger.Errorln(ctx, "failed to find swiper", err)
 
-		return model.Reaction{}, errors.New("failed to check if user has swiped")
+		return nil, errors.New("failed to find user")
 	}
 
-	if hasSwiped.ID != "" {
-		logger.Errorln(ctx, "user has already swiped")
+	results := make([]model.BatchReactionResult, 0, len(reqs))
+	limitReached := false
+	for _, req := range reqs {
+		req.UserID = userID
+
+		result := model.BatchReactionResult{
+			IdempotencyKey: req.IdempotencyKey,
+			MatchedUserID:  req.MatchedUserID,
+		}
 
-		return model.Reaction{}, errors.New("user has already swiped")
+		// the daily limit won't free up again within the batch, so there's no need to keep checking it
+		if limitReached {
+			result.Status = model.BatchReactionQuotaExceeded
+			result.Error = ErrSwipeLimitReached.Error()
+			results = append(results, result)
+
+			continue
+		}
+
+		reaction, err := s.swipe(ctx, plan, swiper, req)
+		switch {
+		case err == nil && reaction.MatchedAt != nil:
+			result.Status = model.BatchReactionMatched
+		case err == nil:
+			result.Status = model.BatchReactionCreated
+		case errors.Is(err, ErrAlreadySwiped):
+			result.Status = model.BatchReactionDuplicate
+		case errors.Is(err, ErrSwipeLimitReached):
+			limitReached = true
+			result.Status = model.BatchReactionQuotaExceeded
+		case errors.Is(err, ErrNoSuperLikesLeft):
+			result.Status = model.BatchReactionQuotaExceeded
+		case errors.Is(err, ErrCannotSwipeSelf), errors.Is(err, ErrSwipeTargetNotFound), errors.Is(err, ErrSwipeTargetNotEligible), errors.Is(err, ErrInvalidDeckToken):
+			result.Status = model.BatchReactionRejected
+		case errors.Is(err, ErrCommentNotAllowed), errors.Is(err, ErrCommentRejected), errors.Is(err, ErrInvalidLikeTarget):
+			result.Status = model.BatchReactionRejected
+		default:
+			result.Status = model.BatchReactionFailed
+		}
+
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Reaction = &reaction
+		}
+
+		results = append(results, result)
 	}
 
-	reaction := req.ToReactionModel()
-	matched, err := s.ReactionRepo.FindMatch(ctx, req.MatchedUserID, req.UserID)
+	return results, nil
+}
+
+func (s *ReactionService) swipe(ctx context.Context, plan *model.SubscriptionPlan, swiper *model.User, req model.ReactionRequest) (model.Reaction, error) {
+	target, err := s.checkEligibility(ctx, swiper, req)
 	if err != nil {
-		logger.Errorln(ctx, "failed to find match", err)
+		return model.Reaction{}, err
+	}
 
-		return model.Reaction{}, errors.New("failed to find match")
+	if req.HasComment() {
+		err = s.validateComment(ctx, req)
+		if err != nil {
+			return model.Reaction{}, err
+		}
 	}
 
-	if matched.ID == "" {
+	reaction := req.ToReactionModel()
+
+	var matched model.Reaction
+	var match model.Match
+	err = s.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		// the daily limit is counted under the swiper's lock, concurrent swipes see each other
+		err := s.ReactionRepo.LockUser(ctx, req.UserID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to lock swiper", err)
+
+			return errors.New("failed to lock swiper")
+		}
+
+		quota, err := s.QuotaService.Check(ctx, req.UserID, plan, model.QuotaSwipe)
+		if err != nil {
+			return err
+		}
+
+		if quota.Exceeded() {
+			return ErrSwipeLimitReached
+		}
+
+		err = s.ReactionRepo.LockPair(ctx, req.UserID, req.MatchedUserID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to lock reaction pair", err)
+
+			return errors.New("failed to lock reaction pair")
+		}
+
+		hasSwiped, err := s.ReactionRepo.HasSwiped(ctx, req.UserID, req.MatchedUserID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to check if user has swiped", err)
+
+			return errors.New("failed to check if user has swiped")
+		}
+
+		if hasSwiped.ID != "" {
+			now := time.Now()
+			if !hasSwiped.CanResurface(swiper.PassCutoff(now, s.Conf.Discovery.PassCooldown)) {
+				logger.Errorln(ctx, "user has already swiped")
+
+				return ErrAlreadySwiped
+			}
+
+			// the profile came back to the deck after a pass, the new swipe takes its place
+			err = s.ReactionRepo.Replace(ctx, hasSwiped.ID, reaction.ID, now)
+			if err != nil {
+				logger.Errorln(ctx, "failed to replace resurfaced pass", err)
+
+				return errors.New("failed to create reaction")
+			}
+		}
+
+		if reaction.Type == model.ReactionSuperLike {
+			err = s.chargeSuperLike(ctx, plan, reaction)
+			if err != nil {
+				return err
+			}
+		}
+
+		matched, err = s.ReactionRepo.FindMatch(ctx, req.MatchedUserID, req.UserID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to find match", err)
+
+			return errors.New("failed to find match")
+		}
+
+		if matched.ID != "" && reaction.IsLike() {
+			now := time.Now()
+			reaction.MatchedAt = &now
+
+			matched.MatchedAt = &now
+			matched.UpdatedAt = &now
+
+			err = s.ReactionRepo.Update(ctx, &matched)
+			if err != nil {
+				logger.Errorln(ctx, "failed to update reaction", err)
+
+				return errors.New("failed to update reaction")
+			}
+
+			match = model.NewMatch(req.UserID, req.MatchedUserID, now)
+			// the target liked first, their like was waiting for this one
+			match.ApplyRules(model.FirstMover(s.Conf.Matches.FirstMove, target, swiper), s.Conf.Matches.ExpiresAfter)
+			err = s.MatchRepo.Create(ctx, match)
+			if err != nil {
+				logger.Errorln(ctx, "failed to create match", err)
+
+				return errors.New("failed to create match")
+			}
+		}
+
 		err = s.ReactionRepo.Create(ctx, reaction)
 		if err != nil {
 			logger.Errorln(ctx, "failed to create reaction", err)
 
-			return model.Reaction{}, errors.New("failed to create reaction")
+			// either the pair or the idempotency key of a replayed swipe
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrAlreadySwiped
+			}
+
+			return errors.New("failed to create reaction")
 		}
 
+		if reaction.IsLike() {
+			// counts towards the results of the recipient's boost, if they're boosted
+			err = s.BoostRepo.IncrementLikes(ctx, reaction.MatchedUserID, time.Now())
+			if err != nil {
+				logger.Errorln(ctx, "failed to count boost like", err)
+
+				return errors.New("failed to create reaction")
+			}
+		}
+
+		return s.enqueueSwipeNotifications(ctx, reaction, matched, match)
+	})
+	if err != nil {
+		return model.Reaction{}, err
+	}
+
+	if reaction.MatchedAt == nil {
 		return reaction, nil
 	}
 
-	now := time.Now()
-	reaction.MatchedAt = &now
+	publish(ctx, s.Publisher, realtime.EventMatch, reaction.UserID, model.MatchEvent{MatchID: match.ID, UserID: reaction.MatchedUserID})
+	publish(ctx, s.Publisher, realtime.EventMatch, reaction.MatchedUserID, model.MatchEvent{MatchID: match.ID, UserID: reaction.UserID})
 
-	matched.MatchedAt = &now
-	matched.UpdatedAt = &now
+	return reaction, nil
+}
+
+// checkEligibility makes sure the swiped profile could have been served by the swiper's deck, and returns it.
+func (s *ReactionService) checkEligibility(ctx context.Context, swiper *model.User, req model.ReactionRequest) (*model.User, error) {
+	if req.MatchedUserID == swiper.ID {
+		return nil, ErrCannotSwipeSelf
+	}
+
+	if req.DeckToken != "" {
+		err := s.DeckTokens.Verify(req.DeckToken, swiper.ID, req.MatchedUserID, time.Now())
+		if err != nil {
+			return nil, ErrInvalidDeckToken
+		}
+	} else if s.DeckTokens.Required() {
+		return nil, ErrInvalidDeckToken
+	}
 
-	err = s.ReactionRepo.Update(ctx, &matched)
+	target, err := s.UserRepo.FindByID(ctx, req.MatchedUserID)
 	if err != nil {
-		logger.Errorln(ctx, "failed to update reaction", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSwipeTargetNotFound
+		}
 
-		return model.Reaction{}, errors.New("failed to update reaction")
+		logger.Errorln(ctx, "failed to find swiped user", err)
+
+		return nil, errors.New("failed to find swiped user")
 	}
 
-	err = s.ReactionRepo.Create(ctx, reaction)
+	// the same people the deck leaves out, unmatched pairs never see each other again
+	hidden, err := s.MatchRepo.FindUnmatchedUserIDs(ctx, swiper.ID)
 	if err != nil {
-		logger.Errorln(ctx, "failed to create reaction", err)
+		logger.Errorln(ctx, "failed to find unmatched users", err)
+
+		return nil, errors.New("failed to find unmatched users")
+	}
 
-		return model.Reaction{}, errors.New("failed to create reaction")
+	if !swiper.CanDiscover(target, hidden) {
+		return nil, ErrSwipeTargetNotEligible
 	}
 
-	// send notification to swipe
-	s.sendMatchNotification(reaction)
+	return target, nil
+}
 
-	// send notification to matched
-	s.sendMatchNotification(matched)
+// validateComment makes sure a comment is left on a like, passes moderation and points at the liked profile.
+func (s *ReactionService) validateComment(ctx context.Context, req model.ReactionRequest) error {
+	if req.Type != model.ReactionLike && req.Type != model.ReactionSuperLike {
+		return ErrCommentNotAllowed
+	}
 
-	return reaction, nil
+	if strings.TrimSpace(req.Comment) != "" {
+		allowed, err := s.Moderator.Allowed(ctx, req.Comment)
+		if err != nil {
+			logger.Errorln(ctx, "failed to moderate comment", err)
+
+			return errors.New("failed to moderate comment")
+		}
+
+		if !allowed {
+			return ErrCommentRejected
+		}
+	}
+
+	if req.TargetType == model.LikeTargetImage {
+		imageID, err := strconv.Atoi(req.TargetID)
+		if err != nil {
+			return ErrInvalidLikeTarget
+		}
+
+		found, err := s.UserRepo.HasImage(ctx, req.MatchedUserID, imageID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to find liked image", err)
+
+			return errors.New("failed to find liked image")
+		}
+
+		if !found {
+			return ErrInvalidLikeTarget
+		}
+	}
+
+	return nil
 }
 
-func (s *ReactionService) SeeLikes(ctx context.Context, userID string) ([]model.Reaction, error) {
-	subscribed, err := s.SubscriptionRepo.FindByUserID(ctx, userID)
+// chargeSuperLike pays with a credit for a super like beyond the plan's quota. The quota is counted under the
+// user's credit lock, so concurrent super likes can't all be let through on the last free one.
+func (s *ReactionService) chargeSuperLike(ctx context.Context, plan *model.SubscriptionPlan, reaction model.Reaction) error {
+	err := s.CreditRepo.LockUser(ctx, reaction.UserID)
 	if err != nil {
-		logger.Errorln(ctx, "failed to check subscription", err)
+		logger.Errorln(ctx, "failed to lock credits", err)
 
-		if err == gorm.ErrRecordNotFound {
-			return nil, errors.New("you are not subscribed to any plan")
-		}
+		return errors.New("failed to check super like credits")
+	}
+
+	quota, err := s.QuotaService.Check(ctx, reaction.UserID, plan, model.QuotaSuperLike)
+	if err != nil {
+		return err
+	}
+
+	if !quota.Exceeded() {
+		return nil
+	}
+
+	balance, err := s.CreditRepo.Balance(ctx, reaction.UserID, model.CreditSuperLike)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find super like balance", err)
+
+		return errors.New("failed to check super like credits")
+	}
 
-		return nil, errors.New("failed to check subscription")
+	if balance < 1 {
+		return ErrNoSuperLikesLeft
 	}
 
-	plan, err := s.SubscriptionRepo.FindPlanByID(ctx, subscribed.PlanID)
+	err = s.CreditRepo.Create(ctx, model.NewCredit(reaction.UserID, model.CreditSuperLike, -1, model.CreditReasonConsume, reaction.ID))
 	if err != nil {
-		logger.Errorln(ctx, "failed to find plan by ID", err)
+		logger.Errorln(ctx, "failed to consume super like credit", err)
 
-		return nil, errors.New("failed to find plan")
+		return errors.New("failed to use super like credit")
 	}
 
-	if plan.Name != model.SubscriptionPlanPro {
-		return nil, errors.New("you are not a pro user")
+	return nil
+}
+
+func (s *ReactionService) SeeLikes(ctx context.Context, userID string) ([]model.Reaction, error) {
+	plan, err := s.QuotaService.FindPlan(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !model.HasFeature(plan.Name, model.FeatureSeeLikes) {
+		return nil, ErrSeeLikesNotAllowed
 	}
 
 	reactions, err := s.ReactionRepo.FindLikes(ctx, userID)
@@ -146,31 +422,385 @@ func (s *ReactionService) SeeLikes(ctx context.Context, userID string) ([]model.
 		return nil, errors.New("failed to find likes")
 	}
 
+	now := time.Now()
+	for i := range reactions {
+		reactions[i].DeckToken = s.DeckTokens.Sign(userID, reactions[i].UserID, now)
+	}
+
 	return reactions, nil
 }
 
-func (u *ReactionService) sendMatchNotification(reaction model.Reaction) {
-	content := model.MatchMessage{
-		Type:    model.ReactionLike,
-		UserID:  reaction.MatchedUserID,
-		Message: "Congratulations! You matched",
+// SummarizeLikes is the likes teaser every plan gets: how many likes are waiting and blurred
+// thumbnails of the latest likers.
+func (s *ReactionService) SummarizeLikes(ctx context.Context, userID string) (model.LikesSummary, error) {
+	plan, err := s.QuotaService.FindPlan(ctx, userID)
+	if err != nil {
+		return model.LikesSummary{}, err
+	}
+
+	count, err := s.ReactionRepo.CountLikes(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to count likes", err)
+
+		return model.LikesSummary{}, errors.New("failed to summarize likes")
+	}
+
+	summary := model.LikesSummary{
+		Count:       count,
+		Thumbnails:  []string{},
+		CanSeeLikes: model.HasFeature(plan.Name, model.FeatureSeeLikes),
+	}
+
+	if count == 0 {
+		return summary, nil
+	}
+
+	likes, err := s.ReactionRepo.FindLatestLikes(ctx, userID, model.LikesTeaserSize)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find latest likes", err)
+
+		return model.LikesSummary{}, errors.New("failed to summarize likes")
+	}
+
+	now := time.Now()
+	for _, like := range likes {
+		if like.User.PrimaryImage() != nil {
+			summary.Thumbnails = append(summary.Thumbnails, s.TeaserURLs.Sign(model.TeaserPath(like.ID), userID, now))
+		}
+	}
+
+	return summary, nil
+}
+
+// OpenLikeTeaser checks a signed teaser URL and fetches the blurred photo of the like it names, which must
+// still be waiting on its viewer. The original photo URL stays on the server.
+func (s *ReactionService) OpenLikeTeaser(ctx context.Context, id string, query url.Values) (*imageproxy.Image, error) {
+	viewerID, err := s.TeaserURLs.Verify(model.TeaserPath(id), query, time.Now())
+	if err != nil {
+		return nil, ErrInvalidTeaserURL
+	}
+
+	like, err := s.ReactionRepo.FindPendingLike(ctx, viewerID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTeaserNotFound
+		}
+
+		logger.Errorln(ctx, "failed to find like", err)
+
+		return nil, errors.New("failed to open teaser")
+	}
+
+	photo := like.User.PrimaryImage()
+	if photo == nil {
+		return nil, ErrTeaserNotFound
+	}
+
+	image, err := s.ImageProxy.Fetch(ctx, photo.URL)
+	if err != nil {
+		logger.Errorln(ctx, "failed to fetch teaser", err)
+
+		return nil, errors.New("failed to open teaser")
+	}
+
+	return image, nil
+}
+
+// Rewind undoes the user's latest reaction while it's within the rewind window,
+// rolling back the match and notifications it created.
+func (s *ReactionService) Rewind(ctx context.Context, userID string) (model.Reaction, error) {
+	plan, err := s.QuotaService.FindPlan(ctx, userID)
+	if err != nil {
+		return model.Reaction{}, err
+	}
+
+	if !model.HasFeature(plan.Name, model.FeatureRewind) {
+		return model.Reaction{}, ErrRewindNotAllowed
+	}
+
+	quota, err := s.QuotaService.Check(ctx, userID, plan, model.QuotaRewind)
+	if err != nil {
+		return model.Reaction{}, err
+	}
+
+	if quota.Exceeded() {
+		return model.Reaction{}, ErrRewindLimitReached
+	}
+
+	var reaction model.Reaction
+	err = s.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		latest, err := s.ReactionRepo.FindLatest(ctx, userID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to find latest reaction", err)
+
+			return errors.New("failed to find latest reaction")
+		}
+
+		now := time.Now()
+		if latest.ID == "" || !latest.CanRewind(now) {
+			return ErrNothingToRewind
+		}
+
+		err = s.ReactionRepo.LockPair(ctx, latest.UserID, latest.MatchedUserID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to lock reaction pair", err)
+
+			return errors.New("failed to lock reaction pair")
+		}
+
+		// the pair might have matched since the reaction was read
+		reaction, err = s.ReactionRepo.HasSwiped(ctx, latest.UserID, latest.MatchedUserID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to find reaction", err)
+
+			return errors.New("failed to find latest reaction")
+		}
+
+		if reaction.ID != latest.ID {
+			return ErrNothingToRewind
+		}
+
+		if reaction.MatchedAt != nil {
+			err = s.rollbackMatch(ctx, reaction)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.ReactionRepo.Delete(ctx, reaction.ID, now)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete reaction", err)
+
+			return errors.New("failed to rewind reaction")
+		}
+
+		err = s.refundSuperLike(ctx, reaction)
+		if err != nil {
+			return err
+		}
+
+		if reaction.IsLike() {
+			err = s.BoostRepo.DecrementLikes(ctx, reaction.MatchedUserID, reaction.CreatedAt)
+			if err != nil {
+				logger.Errorln(ctx, "failed to take back boost like", err)
+
+				return errors.New("failed to rewind reaction")
+			}
+		}
+
+		err = s.NotificationRepo.DeleteByReferenceID(ctx, reaction.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete notifications", err)
+
+			return errors.New("failed to rewind reaction")
+		}
+
+		// the ones the worker hasn't sent yet
+		err = s.OutboxRepo.DeleteByReferenceID(ctx, reaction.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete pending notifications", err)
+
+			return errors.New("failed to rewind reaction")
+		}
+
+		// and the ones waiting for quiet hours to end
+		err = s.DeferredRepo.DeleteByReferenceID(ctx, reaction.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete deferred notifications", err)
+
+			return errors.New("failed to rewind reaction")
+		}
+
+		reaction.DeletedAt = &now
+
+		return nil
+	})
+	if err != nil {
+		return model.Reaction{}, err
+	}
+
+	return reaction, nil
+}
+
+// StartOver brings back every profile the user passed on. Likes and matches stay out of the deck.
+func (s *ReactionService) StartOver(ctx context.Context, userID string) error {
+	plan, err := s.QuotaService.FindPlan(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !model.HasFeature(plan.Name, model.FeatureStartOver) {
+		return ErrStartOverNotAllowed
+	}
+
+	user, err := s.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find user", err)
+
+		return errors.New("failed to start over")
+	}
+
+	now := time.Now()
+	user.DeckResetAt = &now
+
+	_, err = s.UserRepo.Update(user)
+	if err != nil {
+		logger.Errorln(ctx, "failed to reset deck", err)
+
+		return errors.New("failed to start over")
+	}
+
+	return nil
+}
+
+// FindSent returns the user's swipe history with the profile behind each swipe.
+func (s *ReactionService) FindSent(ctx context.Context, userID string, req model.SentReactionRequest) ([]model.SentReaction, int64, error) {
+	req.Normalize()
+
+	user, err := s.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find user", err)
+
+		return nil, 0, errors.New("failed to find sent reactions")
+	}
+
+	filter, err := req.ToFilter(user.Location())
+	if err != nil {
+		logger.Errorln(ctx, "failed to parse date range", err)
+
+		return nil, 0, ErrInvalidDateRange
+	}
+
+	if filter.Since != nil && filter.Until != nil && !filter.Until.After(*filter.Since) {
+		return nil, 0, ErrInvalidDateRange
+	}
+
+	reactions, total, err := s.ReactionRepo.FindSwiped(ctx, userID, filter, req.PaginationRequest)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find sent reactions", err)
+
+		return nil, 0, errors.New("failed to find sent reactions")
+	}
+
+	res := make([]model.SentReaction, 0, len(reactions))
+	for _, reaction := range reactions {
+		res = append(res, reaction.ToSentReaction())
+	}
+
+	return res, total, nil
+}
+
+// refundSuperLike gives back the credit a rewound super like was paid with, if it was paid with one.
+func (s *ReactionService) refundSuperLike(ctx context.Context, reaction model.Reaction) error {
+	if reaction.Type != model.ReactionSuperLike {
+		return nil
+	}
+
+	credits, err := s.CreditRepo.FindByReferenceID(ctx, reaction.UserID, reaction.ID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find super like credits", err)
+
+		return errors.New("failed to rewind reaction")
+	}
+
+	for _, credit := range credits {
+		if credit.Reason != model.CreditReasonConsume {
+			continue
+		}
+
+		err = s.CreditRepo.Create(ctx, model.NewCredit(reaction.UserID, credit.Type, -credit.Amount, model.CreditReasonRefund, reaction.ID))
+		if err != nil {
+			logger.Errorln(ctx, "failed to refund super like credit", err)
+
+			return errors.New("failed to rewind reaction")
+		}
+	}
+
+	return nil
+}
+
+func (s *ReactionService) rollbackMatch(ctx context.Context, reaction model.Reaction) error {
+	match, err := s.MatchRepo.FindByPair(ctx, reaction.UserID, reaction.MatchedUserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Errorln(ctx, "failed to find match", err)
+
+		return errors.New("failed to find match")
+	}
+
+	if match != nil {
+		// the pair may have started chatting, its messages and attachments go with the conversation
+		err = s.ConversationRepo.DeleteByMatchID(ctx, match.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete conversation", err)
+
+			return errors.New("failed to rollback match")
+		}
+
+		err = s.MatchRepo.Delete(ctx, match.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete match", err)
+
+			return errors.New("failed to rollback match")
+		}
+
+		// both users were told about the match, whoever liked last
+		err = s.NotificationRepo.DeleteByReferenceID(ctx, match.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete match notifications", err)
+
+			return errors.New("failed to rollback match")
+		}
+
+		err = s.OutboxRepo.DeleteByReferenceID(ctx, match.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete pending match notifications", err)
+
+			return errors.New("failed to rollback match")
+		}
+
+		err = s.DeferredRepo.DeleteByReferenceID(ctx, match.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to delete deferred match notifications", err)
+
+			return errors.New("failed to rollback match")
+		}
 	}
 
-	b, err := json.Marshal(content)
+	matched, err := s.ReactionRepo.FindMatch(ctx, reaction.MatchedUserID, reaction.UserID)
 	if err != nil {
-		logrus.WithField("reaction", reaction).Error(err.Error())
+		logger.Errorln(ctx, "failed to find match", err)
+
+		return errors.New("failed to find match")
 	}
 
-	notification := model.Notification{
-		ID:        ulid.Make().String(),
-		UserID:    reaction.UserID,
-		Content:   string(b),
-		IsRead:    false,
-		CreatedAt: time.Now(),
+	if matched.ID != "" {
+		err = s.ReactionRepo.ResetMatch(ctx, matched.ID)
+		if err != nil {
+			logger.Errorln(ctx, "failed to reset reaction", err)
+
+			return errors.New("failed to rollback match")
+		}
 	}
 
-	err = u.NotificationRepo.Create(notification)
+	return nil
+}
+
+// enqueueSwipeNotifications writes the swipe's notifications in its transaction, they're sent once it commits.
+func (s *ReactionService) enqueueSwipeNotifications(ctx context.Context, reaction, matched model.Reaction, match model.Match) error {
+	if reaction.MatchedAt == nil {
+		if reaction.Type != model.ReactionSuperLike {
+			return nil
+		}
+
+		return enqueueNotification(ctx, s.OutboxRepo, reaction.MatchedUserID, reaction.ID, model.LikeReceivedPayload{UserID: reaction.UserID, Super: true})
+	}
+
+	// to the swiper, the match is the reference so rewinding either like takes both notifications back
+	err := enqueueNotification(ctx, s.OutboxRepo, reaction.UserID, match.ID, model.MatchPayload{MatchID: match.ID, UserID: reaction.MatchedUserID})
 	if err != nil {
-		logrus.WithField("reaction", reaction).Error(err.Error())
+		return err
 	}
+
+	// to the one they matched with
+	return enqueueNotification(ctx, s.OutboxRepo, matched.UserID, match.ID, model.MatchPayload{MatchID: match.ID, UserID: matched.MatchedUserID})
 }
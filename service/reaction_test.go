@@ -2,11 +2,22 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/marvelalexius/jones/config"
 	"github.com/marvelalexius/jones/mocks"
 	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/decktoken"
+	"github.com/marvelalexius/jones/pkg/imageproxy"
+	"github.com/marvelalexius/jones/pkg/realtime"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
@@ -18,23 +29,23 @@ func TestReactionService_Swipe(t *testing.T) {
 	tests := []struct {
 		name          string
 		request       model.ReactionRequest
-		setupMocks    func(*mocks.IUserRepository, *mocks.IReactionRepository, *mocks.ISubscriptionRepository, *mocks.INotificationRepository)
+		setupMocks    func(*mocks.IUserRepository, *mocks.IReactionRepository, *mocks.ISubscriptionRepository, *mocks.INotificationRepository, *mocks.IMatchRepository)
 		expectedError error
 	}{
 		{
-			name: "Error - User Subscription Not Found",
+			name: "Error - Check Subscription",
 			request: model.ReactionRequest{
 				UserID:        "user1",
 				MatchedUserID: "user2",
 				Type:          model.ReactionLike,
 			},
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
 				sr.ExpectedCalls = nil
 				rr.ExpectedCalls = nil
 				ur.ExpectedCalls = nil
 				nr.ExpectedCalls = nil
 
-				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, gorm.ErrRecordNotFound)
+				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, errors.New("db error"))
 			},
 			expectedError: errors.New("failed to check subscription"),
 		},
@@ -45,14 +56,15 @@ func TestReactionService_Swipe(t *testing.T) {
 				MatchedUserID: "user2",
 				Type:          model.ReactionLike,
 			},
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
 				sr.ExpectedCalls = nil
 				rr.ExpectedCalls = nil
 				ur.ExpectedCalls = nil
 				nr.ExpectedCalls = nil
 
 				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
-				rr.On("FindSwipeCount", mock.Anything, "user1").Return(int64(0), gorm.ErrRecordNotFound).Once()
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), gorm.ErrRecordNotFound).Once()
 			},
 			expectedError: errors.New("failed to check swipe count"),
 		},
@@ -63,14 +75,16 @@ func TestReactionService_Swipe(t *testing.T) {
 				MatchedUserID: "user2",
 				Type:          model.ReactionLike,
 			},
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
 				sr.ExpectedCalls = nil
 				rr.ExpectedCalls = nil
 				ur.ExpectedCalls = nil
 				nr.ExpectedCalls = nil
 
 				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
-				rr.On("FindSwipeCount", mock.Anything, "user1").Return(int64(0), nil).Once()
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
 				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, gorm.ErrRecordNotFound).Once()
 			},
 			expectedError: errors.New("failed to check if user has swiped"),
@@ -82,14 +96,16 @@ func TestReactionService_Swipe(t *testing.T) {
 				MatchedUserID: "user2",
 				Type:          model.ReactionLike,
 			},
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
 				sr.ExpectedCalls = nil
 				rr.ExpectedCalls = nil
 				ur.ExpectedCalls = nil
 				nr.ExpectedCalls = nil
 
 				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
-				rr.On("FindSwipeCount", mock.Anything, "user1").Return(int64(0), nil).Once()
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
 				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
 				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{}, gorm.ErrRecordNotFound).Once()
 			},
@@ -102,14 +118,16 @@ func TestReactionService_Swipe(t *testing.T) {
 				MatchedUserID: "user2",
 				Type:          model.ReactionLike,
 			},
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
 				sr.ExpectedCalls = nil
 				rr.ExpectedCalls = nil
 				ur.ExpectedCalls = nil
 				nr.ExpectedCalls = nil
 
 				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
-				rr.On("FindSwipeCount", mock.Anything, "user1").Return(int64(0), nil).Once()
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
 				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
 				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{}, nil).Once()
 				rr.On("Create", mock.Anything, mock.AnythingOfType("model.Reaction")).Return(gorm.ErrRecordNotFound).Once()
@@ -123,14 +141,16 @@ func TestReactionService_Swipe(t *testing.T) {
 				MatchedUserID: "user2",
 				Type:          model.ReactionLike,
 			},
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
 				sr.ExpectedCalls = nil
 				rr.ExpectedCalls = nil
 				ur.ExpectedCalls = nil
 				nr.ExpectedCalls = nil
 
 				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
-				rr.On("FindSwipeCount", mock.Anything, "user1").Return(int64(0), nil).Once()
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
 				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
 
 				matchedReaction := model.Reaction{
@@ -151,14 +171,16 @@ func TestReactionService_Swipe(t *testing.T) {
 				MatchedUserID: "user2",
 				Type:          model.ReactionLike,
 			},
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
 				sr.ExpectedCalls = nil
 				rr.ExpectedCalls = nil
 				ur.ExpectedCalls = nil
 				nr.ExpectedCalls = nil
 
 				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
-				rr.On("FindSwipeCount", mock.Anything, "user1").Return(int64(0), nil).Once()
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
 				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
 
 				matchedReaction := model.Reaction{
@@ -169,10 +191,48 @@ func TestReactionService_Swipe(t *testing.T) {
 				}
 				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(matchedReaction, nil)
 				rr.On("Update", mock.Anything, mock.AnythingOfType("*model.Reaction")).Return(nil)
+				mr.On("Create", mock.Anything, mock.AnythingOfType("model.Match")).Return(nil)
 				rr.On("Create", mock.Anything, mock.AnythingOfType("model.Reaction")).Return(errors.New("failed to create reaction")).Once()
 			},
 			expectedError: errors.New("failed to create reaction"),
 		},
+		{
+			name: "Error - Create Match",
+			request: model.ReactionRequest{
+				UserID:        "user1",
+				MatchedUserID: "user2",
+				Type:          model.ReactionLike,
+			},
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
+				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
+				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{ID: "reaction1", UserID: "user2", MatchedUserID: "user1", Type: model.ReactionLike}, nil)
+				rr.On("Update", mock.Anything, mock.AnythingOfType("*model.Reaction")).Return(nil)
+				mr.On("Create", mock.Anything, mock.AnythingOfType("model.Match")).Return(gorm.ErrDuplicatedKey)
+			},
+			expectedError: errors.New("failed to create match"),
+		},
+		{
+			name: "Success - Pass On Someone Who Liked You Is Not A Match",
+			request: model.ReactionRequest{
+				UserID:        "user1",
+				MatchedUserID: "user2",
+				Type:          model.ReactionDislike,
+			},
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
+				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
+				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{ID: "reaction1", UserID: "user2", MatchedUserID: "user1", Type: model.ReactionLike}, nil)
+				rr.On("Create", mock.Anything, mock.AnythingOfType("model.Reaction")).Return(nil).Once()
+			},
+			expectedError: nil,
+		},
 		{
 			name: "Success - First Swipe No Match",
 			request: model.ReactionRequest{
@@ -180,14 +240,16 @@ func TestReactionService_Swipe(t *testing.T) {
 				MatchedUserID: "user2",
 				Type:          model.ReactionLike,
 			},
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
 				sr.ExpectedCalls = nil
 				rr.ExpectedCalls = nil
 				ur.ExpectedCalls = nil
 				nr.ExpectedCalls = nil
 
 				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
-				rr.On("FindSwipeCount", mock.Anything, "user1").Return(int64(0), nil).Once()
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
 				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
 				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{}, nil).Once()
 				rr.On("Create", mock.Anything, mock.AnythingOfType("model.Reaction")).Return(nil).Once()
@@ -201,14 +263,16 @@ func TestReactionService_Swipe(t *testing.T) {
 				MatchedUserID: "user2",
 				Type:          model.ReactionLike,
 			},
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
 				sr.ExpectedCalls = nil
 				rr.ExpectedCalls = nil
 				ur.ExpectedCalls = nil
 				nr.ExpectedCalls = nil
 
 				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
-				rr.On("FindSwipeCount", mock.Anything, "user1").Return(int64(0), nil).Once()
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
 				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil)
 
 				matchedReaction := model.Reaction{
@@ -219,9 +283,12 @@ func TestReactionService_Swipe(t *testing.T) {
 				}
 				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(matchedReaction, nil)
 				rr.On("Update", mock.Anything, mock.AnythingOfType("*model.Reaction")).Return(nil)
+				mr.On("Create", mock.Anything, mock.MatchedBy(func(match model.Match) bool {
+					return match.UserID == "user1" && match.MatchedUserID == "user2" && match.Status == model.MatchStatusActive
+				})).Return(nil)
 				rr.On("Create", mock.Anything, mock.AnythingOfType("model.Reaction")).Return(nil)
-				nr.On("Create", mock.AnythingOfType("model.Notification")).Return(nil)
-				nr.On("Create", mock.AnythingOfType("model.Notification")).Return(nil)
+				nr.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil)
+				nr.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil)
 			},
 			expectedError: nil,
 		},
@@ -232,15 +299,19 @@ func TestReactionService_Swipe(t *testing.T) {
 				MatchedUserID: "user2",
 				Type:          model.ReactionLike,
 			},
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
 				sr.ExpectedCalls = nil
 				rr.ExpectedCalls = nil
 				ur.ExpectedCalls = nil
 				nr.ExpectedCalls = nil
 
 				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{
-					ID: "sub1",
+					ID:     "sub1",
+					PlanID: 2,
 				}, nil)
+				sr.On("FindPlanByID", mock.Anything, 2).Return(&model.SubscriptionPlan{ID: 2, Name: model.SubscriptionPlanPro}, nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(10), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
 				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
 				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{}, nil).Once()
 				rr.On("Create", mock.Anything, mock.AnythingOfType("model.Reaction")).Return(nil).Once()
@@ -254,9 +325,11 @@ func TestReactionService_Swipe(t *testing.T) {
 				MatchedUserID: "user2",
 				Type:          model.ReactionLike,
 			},
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
 				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
-				rr.On("FindSwipeCount", mock.Anything, "user1").Return(int64(0), nil)
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
 				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{ID: "existing"}, nil)
 			},
 			expectedError: errors.New("user has already swiped"),
@@ -268,11 +341,12 @@ func TestReactionService_Swipe(t *testing.T) {
 				MatchedUserID: "user2",
 				Type:          model.ReactionLike,
 			},
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository) {
 				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
-				rr.On("FindSwipeCount", mock.Anything, "user1").Return(int64(10), nil)
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(10), nil)
 			},
-			expectedError: errors.New("cannot swipe more than 10 times. please try again tomorrow"),
+			expectedError: ErrSwipeLimitReached,
 		},
 	}
 
@@ -283,12 +357,14 @@ func TestReactionService_Swipe(t *testing.T) {
 			reactionRepo := new(mocks.IReactionRepository)
 			subscriptionRepo := new(mocks.ISubscriptionRepository)
 			notificationRepo := new(mocks.INotificationRepository)
+			matchRepo := new(mocks.IMatchRepository)
 
 			// Setup mocks
-			tt.setupMocks(userRepo, reactionRepo, subscriptionRepo, notificationRepo)
+			tt.setupMocks(userRepo, reactionRepo, subscriptionRepo, notificationRepo, matchRepo)
+			reactionRepo.On("LockUser", mock.Anything, "user1").Return(nil).Maybe()
 
 			// Create service
-			service := NewReactionService(userRepo, reactionRepo, subscriptionRepo, notificationRepo)
+			service := NewReactionService(&config.Config{}, passthroughTransactor(), swipeableUserRepo(userRepo), reactionRepo, subscriptionRepo, notificationRepo, neverMatched(matchRepo), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), NewQuotaService(utcUserRepo(), reactionRepo, subscriptionRepo, new(mocks.IBoostRepository)), new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(swipeableUserRepo(userRepo), notificationRepo, realtime.NewMemoryBroker()), noDeferred())
 
 			// Execute
 			reaction, err := service.Swipe(ctx, tt.request)
@@ -310,106 +386,1517 @@ func TestReactionService_Swipe(t *testing.T) {
 			reactionRepo.AssertExpectations(t)
 			subscriptionRepo.AssertExpectations(t)
 			notificationRepo.AssertExpectations(t)
+			matchRepo.AssertExpectations(t)
 		})
 	}
 }
 
-func TestReactionService_SeeLikes(t *testing.T) {
+func TestReactionService_Swipe_SuperLike(t *testing.T) {
 	ctx := context.Background()
+	request := model.ReactionRequest{
+		UserID:        "user1",
+		MatchedUserID: "user2",
+		Type:          model.ReactionSuperLike,
+	}
 
 	tests := []struct {
 		name          string
-		userID        string
-		setupMocks    func(*mocks.IUserRepository, *mocks.IReactionRepository, *mocks.ISubscriptionRepository, *mocks.INotificationRepository)
+		setupMocks    func(*mocks.IReactionRepository, *mocks.ISubscriptionRepository, *mocks.INotificationRepository, *mocks.ICreditRepository)
 		expectedError error
 	}{
 		{
-			name:   "Success - Pro User",
-			userID: "user1",
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
-				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{
-					ID:     "sub1",
-					PlanID: 2,
-				}, nil)
-				sr.On("FindPlanByID", mock.Anything, 2).Return(&model.SubscriptionPlan{
-					ID:   2,
-					Name: model.SubscriptionPlanPro,
-				}, nil)
-				rr.On("FindLikes", mock.Anything, "user1").Return([]model.Reaction{
-					{
-						ID:            "reaction1",
-						UserID:        "user2",
-						MatchedUserID: "user1",
-						Type:          model.ReactionLike,
-					},
-				}, nil)
+			name: "Success - Within Free Quota",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, cr *mocks.ICreditRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
+				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
+				lock := cr.On("LockUser", mock.Anything, "user1").Return(nil).Once()
+				rr.On("CountByTypeSince", mock.Anything, "user1", model.ReactionSuperLike, mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once().NotBefore(lock)
+				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{}, nil).Once()
+				rr.On("Create", mock.Anything, mock.AnythingOfType("model.Reaction")).Return(nil).Once()
+				nr.On("Create", mock.MatchedBy(func(n *model.Notification) bool {
+					return n.UserID == "user2"
+				})).Return(true, nil).Once()
 			},
 			expectedError: nil,
 		},
 		{
-			name:   "Error - General Error",
-			userID: "user1",
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
-				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, gorm.ErrInvalidDB)
+			name: "Success - Plan Quota Used Up, Consumes Credit",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, cr *mocks.ICreditRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{ID: "sub1", PlanID: 2}, nil)
+				sr.On("FindPlanByID", mock.Anything, 2).Return(&model.SubscriptionPlan{SuperLikeQuota: 5, SuperLikePeriod: model.QuotaPeriodDaily}, nil).Once()
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
+				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
+				lock := cr.On("LockUser", mock.Anything, "user1").Return(nil).Once()
+				rr.On("CountByTypeSince", mock.Anything, "user1", model.ReactionSuperLike, mock.AnythingOfType("time.Time")).Return(int64(5), nil).Once().NotBefore(lock)
+				cr.On("Balance", mock.Anything, "user1", model.CreditSuperLike).Return(3, nil).Once()
+				cr.On("Create", mock.Anything, mock.MatchedBy(func(c model.Credit) bool {
+					return c.Amount == -1 && c.Reason == model.CreditReasonConsume && c.ReferenceID != ""
+				})).Return(nil).Once()
+				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{}, nil).Once()
+				rr.On("Create", mock.Anything, mock.AnythingOfType("model.Reaction")).Return(nil).Once()
+				nr.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil).Once()
 			},
-			expectedError: errors.New("failed to check subscription"),
+			expectedError: nil,
 		},
 		{
-			name:   "Error - Not Subscribed",
-			userID: "user1",
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
-				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, gorm.ErrRecordNotFound)
+			name: "Error - No Super Likes Left",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, cr *mocks.ICreditRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
+				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
+				lock := cr.On("LockUser", mock.Anything, "user1").Return(nil).Once()
+				rr.On("CountByTypeSince", mock.Anything, "user1", model.ReactionSuperLike, mock.AnythingOfType("time.Time")).Return(int64(1), nil).Once().NotBefore(lock)
+				cr.On("Balance", mock.Anything, "user1", model.CreditSuperLike).Return(0, nil).Once()
 			},
-			expectedError: errors.New("you are not subscribed to any plan"),
+			expectedError: errors.New("no super likes left. please purchase more super likes"),
 		},
 		{
-			name:   "Error - Not Pro User",
-			userID: "user1",
-			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository) {
-				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{
-					ID:     "sub1",
-					PlanID: 1,
-				}, nil)
-				sr.On("FindPlanByID", mock.Anything, 1).Return(&model.SubscriptionPlan{
-					ID:   1,
-					Name: model.SubscriptionPlanBasic,
-				}, nil)
+			name: "Error - Count Super Likes",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, cr *mocks.ICreditRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+				rr.On("FindSwipeCount", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
+				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
+				cr.On("LockUser", mock.Anything, "user1").Return(nil).Once()
+				rr.On("CountByTypeSince", mock.Anything, "user1", model.ReactionSuperLike, mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("db error")).Once()
 			},
-			expectedError: errors.New("you are not a pro user"),
+			expectedError: errors.New("failed to check super like quota"),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Create mocks
-			userRepo := new(mocks.IUserRepository)
 			reactionRepo := new(mocks.IReactionRepository)
+			reactionRepo.On("LockUser", mock.Anything, "user1").Return(nil).Maybe()
 			subscriptionRepo := new(mocks.ISubscriptionRepository)
 			notificationRepo := new(mocks.INotificationRepository)
+			creditRepo := new(mocks.ICreditRepository)
 
-			// Setup mocks
-			tt.setupMocks(userRepo, reactionRepo, subscriptionRepo, notificationRepo)
+			tt.setupMocks(reactionRepo, subscriptionRepo, notificationRepo, creditRepo)
 
-			// Create service
-			service := NewReactionService(userRepo, reactionRepo, subscriptionRepo, notificationRepo)
+			service := NewReactionService(&config.Config{}, passthroughTransactor(), swipeableUserRepo(new(mocks.IUserRepository)), reactionRepo, subscriptionRepo, notificationRepo, neverMatched(new(mocks.IMatchRepository)), new(mocks.IConversationRepository), creditRepo, idleBoostRepo(), NewQuotaService(utcUserRepo(), reactionRepo, subscriptionRepo, new(mocks.IBoostRepository)), new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), notificationRepo, realtime.NewMemoryBroker()), noDeferred())
 
-			// Execute
-			reactions, err := service.SeeLikes(ctx, tt.userID)
+			reaction, err := service.Swipe(ctx, request)
 
-			// Assert
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedError.Error(), err.Error())
 			} else {
 				assert.NoError(t, err)
-				assert.NotEmpty(t, reactions)
+				assert.Equal(t, model.ReactionSuperLike, reaction.Type)
 			}
 
-			// Verify all mocks
-			userRepo.AssertExpectations(t)
 			reactionRepo.AssertExpectations(t)
 			subscriptionRepo.AssertExpectations(t)
 			notificationRepo.AssertExpectations(t)
+			creditRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReactionService_Swipe_Comment(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		request       model.ReactionRequest
+		setupMocks    func(*mocks.IUserRepository, *mocks.IReactionRepository, *mocks.IModerator)
+		expectedError error
+	}{
+		{
+			name: "Success - Comment On Photo",
+			request: model.ReactionRequest{
+				UserID:        "user1",
+				MatchedUserID: "user2",
+				Type:          model.ReactionLike,
+				Comment:       " Where was this taken? ",
+				TargetType:    model.LikeTargetImage,
+				TargetID:      "7",
+			},
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, m *mocks.IModerator) {
+				m.On("Allowed", mock.Anything, " Where was this taken? ").Return(true, nil).Once()
+				ur.On("HasImage", mock.Anything, "user2", 7).Return(true, nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
+				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
+				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{}, nil).Once()
+				rr.On("Create", mock.Anything, mock.MatchedBy(func(r model.Reaction) bool {
+					return r.Comment != nil && *r.Comment == "Where was this taken?" && *r.TargetType == model.LikeTargetImage && *r.TargetID == "7"
+				})).Return(nil).Once()
+			},
+			expectedError: nil,
+		},
+		{
+			name: "Error - Comment On A Pass",
+			request: model.ReactionRequest{
+				UserID:        "user1",
+				MatchedUserID: "user2",
+				Type:          model.ReactionDislike,
+				Comment:       "nope",
+			},
+			setupMocks:    func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, m *mocks.IModerator) {},
+			expectedError: ErrCommentNotAllowed,
+		},
+		{
+			name: "Error - Comment Rejected By Moderation",
+			request: model.ReactionRequest{
+				UserID:        "user1",
+				MatchedUserID: "user2",
+				Type:          model.ReactionLike,
+				Comment:       "add me on mysite.io",
+			},
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, m *mocks.IModerator) {
+				m.On("Allowed", mock.Anything, "add me on mysite.io").Return(false, nil).Once()
+			},
+			expectedError: ErrCommentRejected,
+		},
+		{
+			name: "Error - Photo Belongs To Someone Else",
+			request: model.ReactionRequest{
+				UserID:        "user1",
+				MatchedUserID: "user2",
+				Type:          model.ReactionSuperLike,
+				TargetType:    model.LikeTargetImage,
+				TargetID:      "9",
+			},
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, m *mocks.IModerator) {
+				ur.On("HasImage", mock.Anything, "user2", 9).Return(false, nil).Once()
+			},
+			expectedError: ErrInvalidLikeTarget,
+		},
+		{
+			name: "Error - Malformed Photo ID",
+			request: model.ReactionRequest{
+				UserID:        "user1",
+				MatchedUserID: "user2",
+				Type:          model.ReactionLike,
+				TargetType:    model.LikeTargetImage,
+				TargetID:      "abc",
+			},
+			setupMocks:    func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, m *mocks.IModerator) {},
+			expectedError: ErrInvalidLikeTarget,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			reactionRepo := new(mocks.IReactionRepository)
+			reactionRepo.On("LockUser", mock.Anything, "user1").Return(nil).Maybe()
+			moderator := new(mocks.IModerator)
+			tt.setupMocks(userRepo, reactionRepo, moderator)
+
+			service := NewReactionService(&config.Config{}, passthroughTransactor(), swipeableUserRepo(userRepo), reactionRepo, new(mocks.ISubscriptionRepository), new(mocks.INotificationRepository), neverMatched(new(mocks.IMatchRepository)), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), unlimitedQuotaService(), moderator, testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(swipeableUserRepo(userRepo), new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+
+			reaction, err := service.Swipe(ctx, tt.request)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "Where was this taken?", *reaction.Comment)
+			}
+
+			userRepo.AssertExpectations(t)
+			reactionRepo.AssertExpectations(t)
+			moderator.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReactionService_Swipe_Outbox(t *testing.T) {
+	ctx := context.Background()
+	request := model.ReactionRequest{UserID: "user1", MatchedUserID: "user2", Type: model.ReactionLike}
+
+	tests := []struct {
+		name          string
+		failAt        int
+		expectedError error
+		expectedSent  []string
+	}{
+		{
+			name:         "Success - Both Participants Are Told Once It Commits",
+			expectedSent: []string{"user1", "user2"},
+		},
+		{
+			name:          "Error - Nothing Is Sent When The Transaction Fails",
+			failAt:        2,
+			expectedError: errors.New("failed to enqueue notification"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reactionRepo := new(mocks.IReactionRepository)
+			reactionRepo.On("LockUser", mock.MatchedBy(inTransaction), "user1").Return(nil).Once()
+			reactionRepo.On("LockPair", mock.MatchedBy(inTransaction), "user1", "user2").Return(nil).Once()
+			reactionRepo.On("HasSwiped", mock.MatchedBy(inTransaction), "user1", "user2").Return(model.Reaction{}, nil)
+			reactionRepo.On("FindMatch", mock.MatchedBy(inTransaction), "user2", "user1").Return(model.Reaction{ID: "reaction1", UserID: "user2", MatchedUserID: "user1", Type: model.ReactionLike}, nil)
+			reactionRepo.On("Update", mock.MatchedBy(inTransaction), mock.AnythingOfType("*model.Reaction")).Return(nil)
+			reactionRepo.On("Create", mock.MatchedBy(inTransaction), mock.AnythingOfType("model.Reaction")).Return(nil).Once()
+
+			matchRepo := new(mocks.IMatchRepository)
+			matchRepo.On("Create", mock.MatchedBy(inTransaction), mock.AnythingOfType("model.Match")).Return(nil).Once()
+
+			outbox := &txOutbox{failAt: tt.failAt}
+			service := NewReactionService(&config.Config{}, outbox, swipeableUserRepo(new(mocks.IUserRepository)), reactionRepo, new(mocks.ISubscriptionRepository), new(mocks.INotificationRepository), neverMatched(matchRepo), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), unlimitedQuotaService(), new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), outbox, noDeferred())
+
+			_, err := service.Swipe(ctx, request)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			var sent []string
+			for _, message := range outbox.committed {
+				var notification model.NotificationMessage
+				assert.NoError(t, json.Unmarshal([]byte(message.Payload), &notification))
+				assert.Equal(t, model.NotificationTypeMatch, notification.Type)
+				sent = append(sent, notification.UserID)
+			}
+			assert.Equal(t, tt.expectedSent, sent)
+
+			reactionRepo.AssertExpectations(t)
+			matchRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReactionService_Swipe_Eligibility(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	swiper := &model.User{ID: "user1", Gender: model.GenderMale, Preference: model.PreferenceFemale}
+	validToken := testDeckTokens().Sign("user1", "user2", now)
+
+	tests := []struct {
+		name          string
+		request       model.ReactionRequest
+		requireToken  bool
+		setupMocks    func(*mocks.IUserRepository, *mocks.IReactionRepository, *mocks.IMatchRepository)
+		expectedError error
+	}{
+		{
+			name:    "Success - Served In Deck",
+			request: model.ReactionRequest{UserID: "user1", MatchedUserID: "user2", Type: model.ReactionLike, DeckToken: validToken},
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository) {
+				ur.On("FindByID", mock.Anything, "user2").Return(&model.User{ID: "user2", Gender: model.GenderFemale}, nil).Once()
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user1").Return([]string{}, nil).Once()
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
+				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
+				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{}, nil).Once()
+				rr.On("Create", mock.Anything, mock.AnythingOfType("model.Reaction")).Return(nil).Once()
+			},
+			requireToken: true,
+		},
+		{
+			name:          "Error - Swiping On Yourself",
+			request:       model.ReactionRequest{UserID: "user1", MatchedUserID: "user1", Type: model.ReactionLike},
+			setupMocks:    func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository) {},
+			expectedError: ErrCannotSwipeSelf,
+		},
+		{
+			name:    "Error - Profile Doesn't Exist",
+			request: model.ReactionRequest{UserID: "user1", MatchedUserID: "user9", Type: model.ReactionLike},
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository) {
+				ur.On("FindByID", mock.Anything, "user9").Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			expectedError: ErrSwipeTargetNotFound,
+		},
+		{
+			name:    "Error - Outside Preference",
+			request: model.ReactionRequest{UserID: "user1", MatchedUserID: "user3", Type: model.ReactionLike},
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository) {
+				ur.On("FindByID", mock.Anything, "user3").Return(&model.User{ID: "user3", Gender: model.GenderMale}, nil).Once()
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user1").Return([]string{}, nil).Once()
+			},
+			expectedError: ErrSwipeTargetNotEligible,
+		},
+		{
+			name:    "Error - Unmatched Before",
+			request: model.ReactionRequest{UserID: "user1", MatchedUserID: "user2", Type: model.ReactionLike},
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository) {
+				ur.On("FindByID", mock.Anything, "user2").Return(&model.User{ID: "user2", Gender: model.GenderFemale}, nil).Once()
+				// they unmatched, or one reported the other
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user1").Return([]string{"user2"}, nil).Once()
+			},
+			expectedError: ErrSwipeTargetNotEligible,
+		},
+		{
+			name:          "Error - Token For Another Profile",
+			request:       model.ReactionRequest{UserID: "user1", MatchedUserID: "user4", Type: model.ReactionLike, DeckToken: validToken},
+			setupMocks:    func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository) {},
+			expectedError: ErrInvalidDeckToken,
+		},
+		{
+			name:          "Error - Token Required",
+			request:       model.ReactionRequest{UserID: "user1", MatchedUserID: "user2", Type: model.ReactionLike},
+			requireToken:  true,
+			setupMocks:    func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository) {},
+			expectedError: ErrInvalidDeckToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			reactionRepo := new(mocks.IReactionRepository)
+			reactionRepo.On("LockUser", mock.Anything, "user1").Return(nil).Maybe()
+			matchRepo := new(mocks.IMatchRepository)
+			userRepo.On("FindByID", mock.Anything, "user1").Return(swiper, nil).Once()
+			tt.setupMocks(userRepo, reactionRepo, matchRepo)
+
+			deckTokens, err := decktoken.NewSigner("test-secret", model.DeckTokenTTL, tt.requireToken)
+			assert.NoError(t, err)
+			service := NewReactionService(&config.Config{}, passthroughTransactor(), userRepo, reactionRepo, new(mocks.ISubscriptionRepository), new(mocks.INotificationRepository), matchRepo, new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), unlimitedQuotaService(), new(mocks.IModerator), deckTokens, testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(userRepo, new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+
+			_, err = service.Swipe(ctx, tt.request)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			userRepo.AssertExpectations(t)
+			reactionRepo.AssertExpectations(t)
+			matchRepo.AssertExpectations(t)
 		})
 	}
 }
+
+func TestReactionService_BatchSwipe(t *testing.T) {
+	ctx := context.Background()
+	swipeLimit := 10
+	plan := &model.SubscriptionPlan{ID: 3, Name: model.SubscriptionPlanFree, DailySwipeLimit: &swipeLimit}
+
+	reactionRepo := new(mocks.IReactionRepository)
+	reactionRepo.On("LockUser", mock.Anything, "user1").Return(nil)
+	notificationRepo := new(mocks.INotificationRepository)
+	matchRepo := new(mocks.IMatchRepository)
+	quotaService := new(mocks.IQuotaService)
+
+	quotaService.On("FindPlan", mock.Anything, "user1").Return(plan, nil).Once()
+	quotaService.On("Check", mock.Anything, "user1", plan, model.QuotaSwipe).Return(model.NewQuota(model.QuotaSwipe, &swipeLimit, 0, time.Now()), nil).Times(3)
+	quotaService.On("Check", mock.Anything, "user1", plan, model.QuotaSwipe).Return(model.NewQuota(model.QuotaSwipe, &swipeLimit, 10, time.Now()), nil).Once()
+
+	for _, matchedUserID := range []string{"user2", "user3", "user4"} {
+		reactionRepo.On("LockPair", mock.Anything, "user1", matchedUserID).Return(nil).Once()
+	}
+
+	// created
+	reactionRepo.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
+	reactionRepo.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{}, nil).Once()
+	reactionRepo.On("Create", mock.Anything, mock.MatchedBy(func(r model.Reaction) bool {
+		return r.MatchedUserID == "user2" && *r.IdempotencyKey == "key1" && r.SwipedAt != nil
+	})).Return(nil).Once()
+
+	// matched
+	reactionRepo.On("HasSwiped", mock.Anything, "user1", "user3").Return(model.Reaction{}, nil).Once()
+	reactionRepo.On("FindMatch", mock.Anything, "user3", "user1").Return(model.Reaction{ID: "reaction3", UserID: "user3", MatchedUserID: "user1", Type: model.ReactionLike}, nil).Once()
+	reactionRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Reaction")).Return(nil).Once()
+	matchRepo.On("Create", mock.Anything, mock.AnythingOfType("model.Match")).Return(nil).Once()
+	reactionRepo.On("Create", mock.Anything, mock.MatchedBy(func(r model.Reaction) bool {
+		return r.MatchedUserID == "user3"
+	})).Return(nil).Once()
+	notificationRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil).Twice()
+
+	// duplicate
+	reactionRepo.On("HasSwiped", mock.Anything, "user1", "user4").Return(model.Reaction{ID: "existing"}, nil).Once()
+
+	swipedAt := time.Now().Add(-time.Hour)
+	reqs := []model.ReactionRequest{
+		{MatchedUserID: "user2", Type: model.ReactionLike, IdempotencyKey: "key1", SwipedAt: &swipedAt},
+		{MatchedUserID: "user3", Type: model.ReactionLike, IdempotencyKey: "key2", SwipedAt: &swipedAt},
+		{MatchedUserID: "user4", Type: model.ReactionDislike, IdempotencyKey: "key3", SwipedAt: &swipedAt},
+		{MatchedUserID: "user5", Type: model.ReactionDislike, IdempotencyKey: "key4", Comment: "nope"},
+		{MatchedUserID: "user6", Type: model.ReactionLike, IdempotencyKey: "key5"},
+		{MatchedUserID: "user7", Type: model.ReactionLike, IdempotencyKey: "key6"},
+	}
+
+	service := NewReactionService(&config.Config{}, passthroughTransactor(), swipeableUserRepo(new(mocks.IUserRepository)), reactionRepo, new(mocks.ISubscriptionRepository), notificationRepo, neverMatched(matchRepo), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), quotaService, new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), notificationRepo, realtime.NewMemoryBroker()), noDeferred())
+	results, err := service.BatchSwipe(ctx, "user1", reqs)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, len(reqs))
+
+	statuses := make([]string, 0, len(results))
+	for i, result := range results {
+		assert.Equal(t, reqs[i].IdempotencyKey, result.IdempotencyKey)
+		assert.Equal(t, reqs[i].MatchedUserID, result.MatchedUserID)
+		statuses = append(statuses, result.Status)
+	}
+
+	assert.Equal(t, []string{
+		model.BatchReactionCreated,
+		model.BatchReactionMatched,
+		model.BatchReactionDuplicate,
+		model.BatchReactionRejected,
+		model.BatchReactionQuotaExceeded,
+		model.BatchReactionQuotaExceeded,
+	}, statuses)
+	assert.Equal(t, "user1", results[0].Reaction.UserID)
+	assert.Nil(t, results[2].Reaction)
+	assert.Equal(t, ErrSwipeLimitReached.Error(), results[5].Error)
+
+	reactionRepo.AssertExpectations(t)
+	notificationRepo.AssertExpectations(t)
+	matchRepo.AssertExpectations(t)
+	quotaService.AssertExpectations(t)
+}
+
+func TestReactionService_SeeLikes(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		userID        string
+		setupMocks    func(*mocks.IReactionRepository, *mocks.IQuotaService)
+		expectedError error
+	}{
+		{
+			name:   "Success - Pro User",
+			userID: "user1",
+			setupMocks: func(rr *mocks.IReactionRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(&model.SubscriptionPlan{ID: 2, Name: model.SubscriptionPlanPro}, nil)
+				rr.On("FindLikes", mock.Anything, "user1").Return([]model.Reaction{
+					{
+						ID:            "reaction1",
+						UserID:        "user2",
+						MatchedUserID: "user1",
+						Type:          model.ReactionLike,
+					},
+				}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name:   "Error - General Error",
+			userID: "user1",
+			setupMocks: func(rr *mocks.IReactionRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(nil, errors.New("failed to check subscription"))
+			},
+			expectedError: errors.New("failed to check subscription"),
+		},
+		{
+			name:   "Error - Plan Can't See Likes",
+			userID: "user1",
+			setupMocks: func(rr *mocks.IReactionRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(&model.SubscriptionPlan{ID: 1, Name: model.SubscriptionPlanBasic}, nil)
+			},
+			expectedError: ErrSeeLikesNotAllowed,
+		},
+		{
+			name:   "Error - Failed to Find Likes",
+			userID: "user1",
+			setupMocks: func(rr *mocks.IReactionRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(&model.SubscriptionPlan{ID: 2, Name: model.SubscriptionPlanPro}, nil)
+				rr.On("FindLikes", mock.Anything, "user1").Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("failed to find likes"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reactionRepo := new(mocks.IReactionRepository)
+			quotaService := new(mocks.IQuotaService)
+			tt.setupMocks(reactionRepo, quotaService)
+
+			service := NewReactionService(&config.Config{}, passthroughTransactor(), new(mocks.IUserRepository), reactionRepo, new(mocks.ISubscriptionRepository), new(mocks.INotificationRepository), new(mocks.IMatchRepository), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), quotaService, new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(new(mocks.IUserRepository), new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+
+			reactions, err := service.SeeLikes(ctx, tt.userID)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.NotEmpty(t, reactions)
+				assert.NoError(t, testDeckTokens().Verify(reactions[0].DeckToken, "user1", "user2", time.Now()))
+			}
+
+			reactionRepo.AssertExpectations(t)
+			quotaService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReactionService_SummarizeLikes(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		plan            *model.SubscriptionPlan
+		setupMocks      func(*mocks.IReactionRepository)
+		expected        model.LikesSummary
+		expectedTeasers []string
+		expectedError   error
+	}{
+		{
+			name: "Success - Free User Gets Blurred Thumbnails",
+			plan: freePlan(),
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("CountLikes", mock.Anything, "user1").Return(int64(7), nil)
+				rr.On("FindLatestLikes", mock.Anything, "user1", model.LikesTeaserSize).Return([]model.Reaction{
+					{ID: "reaction1", UserID: "user2", User: model.User{ID: "user2", Images: []model.Image{{URL: "https://cdn.test/user2.jpg", IsPrimary: true}}}},
+					{ID: "reaction2", UserID: "user3", User: model.User{ID: "user3"}},
+				}, nil)
+			},
+			expected:        model.LikesSummary{Count: 7},
+			expectedTeasers: []string{"reaction1"},
+		},
+		{
+			name: "Success - Pro User Can See Likes",
+			plan: &model.SubscriptionPlan{ID: 2, Name: model.SubscriptionPlanPro},
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("CountLikes", mock.Anything, "user1").Return(int64(0), nil)
+			},
+			expected:        model.LikesSummary{Count: 0, CanSeeLikes: true},
+			expectedTeasers: []string{},
+		},
+		{
+			name: "Error - Failed to Count Likes",
+			plan: freePlan(),
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("CountLikes", mock.Anything, "user1").Return(int64(0), errors.New("db error"))
+			},
+			expectedError: errors.New("failed to summarize likes"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reactionRepo := new(mocks.IReactionRepository)
+			quotaService := new(mocks.IQuotaService)
+			quotaService.On("FindPlan", mock.Anything, "user1").Return(tt.plan, nil)
+			tt.setupMocks(reactionRepo)
+
+			service := NewReactionService(&config.Config{}, passthroughTransactor(), new(mocks.IUserRepository), reactionRepo, new(mocks.ISubscriptionRepository), new(mocks.INotificationRepository), new(mocks.IMatchRepository), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), quotaService, new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(new(mocks.IUserRepository), new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+
+			summary, err := service.SummarizeLikes(ctx, "user1")
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.Count, summary.Count)
+				assert.Equal(t, tt.expected.CanSeeLikes, summary.CanSeeLikes)
+				assert.Len(t, summary.Thumbnails, len(tt.expectedTeasers))
+
+				// thumbnails name the like, never the photo, and only work for the viewer
+				for i, thumbnail := range summary.Thumbnails {
+					assert.NotContains(t, thumbnail, "cdn.test")
+
+					link, err := url.Parse(thumbnail)
+					assert.NoError(t, err)
+					assert.Equal(t, model.TeaserPath(tt.expectedTeasers[i]), link.Path)
+
+					viewerID, err := testURLSigner().Verify(link.Path, link.Query(), time.Now())
+					assert.NoError(t, err)
+					assert.Equal(t, "user1", viewerID)
+				}
+			}
+
+			reactionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReactionService_OpenLikeTeaser(t *testing.T) {
+	ctx := context.Background()
+	signed := func(id, viewerID string) url.Values {
+		link, _ := url.Parse(testURLSigner().Sign(model.TeaserPath(id), viewerID, time.Now()))
+
+		return link.Query()
+	}
+	liker := model.User{ID: "user2", Images: []model.Image{{URL: "https://cdn.test/user2.jpg", IsPrimary: true}}}
+
+	tests := []struct {
+		name          string
+		id            string
+		query         url.Values
+		setupMocks    func(*mocks.IReactionRepository, *mocks.IProxy)
+		expectedError error
+	}{
+		{
+			name:  "Success",
+			id:    "reaction1",
+			query: signed("reaction1", "user1"),
+			setupMocks: func(rr *mocks.IReactionRepository, p *mocks.IProxy) {
+				rr.On("FindPendingLike", mock.Anything, "user1", "reaction1").Return(model.Reaction{ID: "reaction1", User: liker}, nil)
+				p.On("Fetch", mock.Anything, "https://cdn.test/user2.jpg").Return(&imageproxy.Image{Body: io.NopCloser(strings.NewReader("blurred")), ContentType: "image/jpeg", Size: 7}, nil)
+			},
+		},
+		{
+			name:          "Error - Signed For Another Like",
+			id:            "reaction2",
+			query:         signed("reaction1", "user1"),
+			setupMocks:    func(rr *mocks.IReactionRepository, p *mocks.IProxy) {},
+			expectedError: ErrInvalidTeaserURL,
+		},
+		{
+			name:  "Error - Like Already Answered",
+			id:    "reaction1",
+			query: signed("reaction1", "user1"),
+			setupMocks: func(rr *mocks.IReactionRepository, p *mocks.IProxy) {
+				rr.On("FindPendingLike", mock.Anything, "user1", "reaction1").Return(model.Reaction{}, gorm.ErrRecordNotFound)
+			},
+			expectedError: ErrTeaserNotFound,
+		},
+		{
+			name:  "Error - Liker Has No Photo",
+			id:    "reaction1",
+			query: signed("reaction1", "user1"),
+			setupMocks: func(rr *mocks.IReactionRepository, p *mocks.IProxy) {
+				rr.On("FindPendingLike", mock.Anything, "user1", "reaction1").Return(model.Reaction{ID: "reaction1", User: model.User{ID: "user2"}}, nil)
+			},
+			expectedError: ErrTeaserNotFound,
+		},
+		{
+			name:  "Error - Image Proxy Down",
+			id:    "reaction1",
+			query: signed("reaction1", "user1"),
+			setupMocks: func(rr *mocks.IReactionRepository, p *mocks.IProxy) {
+				rr.On("FindPendingLike", mock.Anything, "user1", "reaction1").Return(model.Reaction{ID: "reaction1", User: liker}, nil)
+				p.On("Fetch", mock.Anything, "https://cdn.test/user2.jpg").Return(nil, errors.New("connection refused"))
+			},
+			expectedError: errors.New("failed to open teaser"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reactionRepo := new(mocks.IReactionRepository)
+			proxy := new(mocks.IProxy)
+			tt.setupMocks(reactionRepo, proxy)
+
+			service := NewReactionService(&config.Config{}, passthroughTransactor(), new(mocks.IUserRepository), reactionRepo, new(mocks.ISubscriptionRepository), new(mocks.INotificationRepository), new(mocks.IMatchRepository), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), unlimitedQuotaService(), new(mocks.IModerator), testDeckTokens(), testURLSigner(), proxy, realtime.NewMemoryBroker(), testOutbox(new(mocks.IUserRepository), new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+
+			image, err := service.OpenLikeTeaser(ctx, tt.id, tt.query)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "image/jpeg", image.ContentType)
+			}
+
+			reactionRepo.AssertExpectations(t)
+			proxy.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReactionService_Rewind(t *testing.T) {
+	ctx := context.Background()
+	proSubscription := &model.Subscription{ID: "sub1", PlanID: 2}
+	rewindLimit := 5
+	proPlan := &model.SubscriptionPlan{ID: 2, Name: model.SubscriptionPlanPro, DailyRewindLimit: &rewindLimit}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IReactionRepository, *mocks.ISubscriptionRepository, *mocks.INotificationRepository, *mocks.IMatchRepository, *mocks.IConversationRepository, *mocks.ICreditRepository)
+		likeTakenBack bool
+		// the references whose notifications held back by quiet hours are dropped
+		deferredCleared []string
+		expectedError   error
+	}{
+		{
+			name: "Success - Rewind Pass",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository, cvr *mocks.IConversationRepository, cr *mocks.ICreditRepository) {
+				latest := model.Reaction{ID: "reaction1", UserID: "user1", MatchedUserID: "user2", Type: model.ReactionDislike, CreatedAt: time.Now().Add(-time.Minute)}
+
+				sr.On("FindByUserID", mock.Anything, "user1").Return(proSubscription, nil)
+				sr.On("FindPlanByID", mock.Anything, 2).Return(proPlan, nil)
+				rr.On("CountRewoundSince", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+				rr.On("FindLatest", mock.Anything, "user1").Return(latest, nil)
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil)
+				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(latest, nil)
+				rr.On("Delete", mock.Anything, "reaction1", mock.AnythingOfType("time.Time")).Return(nil)
+				nr.On("DeleteByReferenceID", mock.Anything, "reaction1").Return(nil)
+			},
+			deferredCleared: []string{"reaction1"},
+			expectedError:   nil,
+		},
+		{
+			name: "Success - Rewind Match With Conversation",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository, cvr *mocks.IConversationRepository, cr *mocks.ICreditRepository) {
+				matchedAt := time.Now().Add(-time.Minute)
+				latest := model.Reaction{ID: "reaction1", UserID: "user1", MatchedUserID: "user2", Type: model.ReactionLike, MatchedAt: &matchedAt, CreatedAt: matchedAt}
+
+				sr.On("FindByUserID", mock.Anything, "user1").Return(proSubscription, nil)
+				sr.On("FindPlanByID", mock.Anything, 2).Return(proPlan, nil)
+				rr.On("CountRewoundSince", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+				rr.On("FindLatest", mock.Anything, "user1").Return(latest, nil)
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil)
+				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(latest, nil)
+				mr.On("FindByPair", mock.Anything, "user1", "user2").Return(&model.Match{ID: "match1"}, nil)
+				// the conversation still references the match, it has to go first
+				deleteConversation := cvr.On("DeleteByMatchID", mock.Anything, "match1").Return(nil)
+				mr.On("Delete", mock.Anything, "match1").Return(nil).NotBefore(deleteConversation)
+				rr.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{ID: "reaction2", MatchedAt: &matchedAt}, nil)
+				rr.On("ResetMatch", mock.Anything, "reaction2").Return(nil)
+				rr.On("Delete", mock.Anything, "reaction1", mock.AnythingOfType("time.Time")).Return(nil)
+				nr.On("DeleteByReferenceID", mock.Anything, "reaction1").Return(nil)
+				// the match notifications reference the match, the other user's one included
+				nr.On("DeleteByReferenceID", mock.Anything, "match1").Return(nil)
+			},
+			likeTakenBack:   true,
+			deferredCleared: []string{"match1", "reaction1"},
+			expectedError:   nil,
+		},
+		{
+			name: "Success - Rewind Super Like Paid With Credit",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository, cvr *mocks.IConversationRepository, cr *mocks.ICreditRepository) {
+				latest := model.Reaction{ID: "reaction1", UserID: "user1", MatchedUserID: "user2", Type: model.ReactionSuperLike, CreatedAt: time.Now().Add(-time.Minute)}
+
+				sr.On("FindByUserID", mock.Anything, "user1").Return(proSubscription, nil)
+				sr.On("FindPlanByID", mock.Anything, 2).Return(proPlan, nil)
+				rr.On("CountRewoundSince", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+				rr.On("FindLatest", mock.Anything, "user1").Return(latest, nil)
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil)
+				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(latest, nil)
+				rr.On("Delete", mock.Anything, "reaction1", mock.AnythingOfType("time.Time")).Return(nil)
+				cr.On("FindByReferenceID", mock.Anything, "user1", "reaction1").Return([]model.Credit{
+					{ID: "credit1", UserID: "user1", Type: model.CreditSuperLike, Amount: -1, Reason: model.CreditReasonConsume, ReferenceID: "reaction1"},
+				}, nil)
+				cr.On("Create", mock.Anything, mock.MatchedBy(func(c model.Credit) bool {
+					return c.UserID == "user1" && c.Type == model.CreditSuperLike && c.Amount == 1 && c.Reason == model.CreditReasonRefund && c.ReferenceID == "reaction1"
+				})).Return(nil).Once()
+				nr.On("DeleteByReferenceID", mock.Anything, "reaction1").Return(nil)
+			},
+			likeTakenBack: true,
+			expectedError: nil,
+		},
+		{
+			name: "Error - Failed to Delete Conversation",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository, cvr *mocks.IConversationRepository, cr *mocks.ICreditRepository) {
+				matchedAt := time.Now().Add(-time.Minute)
+				latest := model.Reaction{ID: "reaction1", UserID: "user1", MatchedUserID: "user2", Type: model.ReactionLike, MatchedAt: &matchedAt, CreatedAt: matchedAt}
+
+				sr.On("FindByUserID", mock.Anything, "user1").Return(proSubscription, nil)
+				sr.On("FindPlanByID", mock.Anything, 2).Return(proPlan, nil)
+				rr.On("CountRewoundSince", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+				rr.On("FindLatest", mock.Anything, "user1").Return(latest, nil)
+				rr.On("LockPair", mock.Anything, "user1", "user2").Return(nil)
+				rr.On("HasSwiped", mock.Anything, "user1", "user2").Return(latest, nil)
+				mr.On("FindByPair", mock.Anything, "user1", "user2").Return(&model.Match{ID: "match1"}, nil)
+				cvr.On("DeleteByMatchID", mock.Anything, "match1").Return(errors.New("db error"))
+			},
+			expectedError: errors.New("failed to rollback match"),
+		},
+		{
+			name: "Error - Not Subscribed",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository, cvr *mocks.IConversationRepository, cr *mocks.ICreditRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(nil, gorm.ErrRecordNotFound)
+				sr.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+			},
+			expectedError: ErrRewindNotAllowed,
+		},
+		{
+			name: "Error - Plan Without Rewind",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository, cvr *mocks.IConversationRepository, cr *mocks.ICreditRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{ID: "sub1", PlanID: 1}, nil)
+				sr.On("FindPlanByID", mock.Anything, 1).Return(&model.SubscriptionPlan{ID: 1, Name: model.SubscriptionPlanBasic}, nil)
+			},
+			expectedError: ErrRewindNotAllowed,
+		},
+		{
+			name: "Error - Daily Limit Reached",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository, cvr *mocks.IConversationRepository, cr *mocks.ICreditRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(proSubscription, nil)
+				sr.On("FindPlanByID", mock.Anything, 2).Return(proPlan, nil)
+				rr.On("CountRewoundSince", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(5), nil)
+			},
+			expectedError: ErrRewindLimitReached,
+		},
+		{
+			name: "Error - Outside Rewind Window",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository, cvr *mocks.IConversationRepository, cr *mocks.ICreditRepository) {
+				latest := model.Reaction{ID: "reaction1", UserID: "user1", MatchedUserID: "user2", CreatedAt: time.Now().Add(-time.Hour)}
+
+				sr.On("FindByUserID", mock.Anything, "user1").Return(proSubscription, nil)
+				sr.On("FindPlanByID", mock.Anything, 2).Return(proPlan, nil)
+				rr.On("CountRewoundSince", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+				rr.On("FindLatest", mock.Anything, "user1").Return(latest, nil)
+			},
+			expectedError: ErrNothingToRewind,
+		},
+		{
+			name: "Error - No Reaction",
+			setupMocks: func(rr *mocks.IReactionRepository, sr *mocks.ISubscriptionRepository, nr *mocks.INotificationRepository, mr *mocks.IMatchRepository, cvr *mocks.IConversationRepository, cr *mocks.ICreditRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(proSubscription, nil)
+				sr.On("FindPlanByID", mock.Anything, 2).Return(proPlan, nil)
+				rr.On("CountRewoundSince", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+				rr.On("FindLatest", mock.Anything, "user1").Return(model.Reaction{}, nil)
+			},
+			expectedError: ErrNothingToRewind,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reactionRepo := new(mocks.IReactionRepository)
+			subscriptionRepo := new(mocks.ISubscriptionRepository)
+			notificationRepo := new(mocks.INotificationRepository)
+			matchRepo := new(mocks.IMatchRepository)
+			conversationRepo := new(mocks.IConversationRepository)
+			creditRepo := new(mocks.ICreditRepository)
+			boostRepo := idleBoostRepo()
+
+			deferredRepo := noDeferred()
+
+			tt.setupMocks(reactionRepo, subscriptionRepo, notificationRepo, matchRepo, conversationRepo, creditRepo)
+
+			service := NewReactionService(&config.Config{}, passthroughTransactor(), new(mocks.IUserRepository), reactionRepo, subscriptionRepo, notificationRepo, matchRepo, conversationRepo, creditRepo, boostRepo, NewQuotaService(utcUserRepo(), reactionRepo, subscriptionRepo, new(mocks.IBoostRepository)), new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(new(mocks.IUserRepository), notificationRepo, realtime.NewMemoryBroker()), deferredRepo)
+
+			reaction, err := service.Rewind(ctx, "user1")
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "reaction1", reaction.ID)
+				assert.NotNil(t, reaction.DeletedAt)
+			}
+
+			reactionRepo.AssertExpectations(t)
+			subscriptionRepo.AssertExpectations(t)
+			notificationRepo.AssertExpectations(t)
+			matchRepo.AssertExpectations(t)
+			conversationRepo.AssertExpectations(t)
+			creditRepo.AssertExpectations(t)
+			for _, referenceID := range tt.deferredCleared {
+				deferredRepo.AssertCalled(t, "DeleteByReferenceID", mock.Anything, referenceID)
+			}
+			// the like is taken back from the boost it counted towards
+			if tt.likeTakenBack {
+				boostRepo.AssertCalled(t, "DecrementLikes", mock.Anything, "user2", mock.Anything)
+			} else {
+				boostRepo.AssertNotCalled(t, "DecrementLikes", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestReactionService_Swipe_Resurfaced(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	conf := &config.Config{Discovery: config.Discovery{PassCooldown: 30 * 24 * time.Hour}}
+	resetAt := now.Add(-time.Hour)
+
+	tests := []struct {
+		name          string
+		swiper        *model.User
+		previous      model.Reaction
+		expectedError error
+	}{
+		{
+			name:     "Success - Pass Cooled Down",
+			swiper:   &model.User{ID: "user1"},
+			previous: model.Reaction{ID: "reaction1", Type: model.ReactionDislike, CreatedAt: now.Add(-31 * 24 * time.Hour)},
+		},
+		{
+			name:     "Success - Deck Started Over",
+			swiper:   &model.User{ID: "user1", DeckResetAt: &resetAt},
+			previous: model.Reaction{ID: "reaction1", Type: model.ReactionDislike, CreatedAt: now.Add(-2 * time.Hour)},
+		},
+		{
+			name:          "Error - Pass Still Cooling Down",
+			swiper:        &model.User{ID: "user1"},
+			previous:      model.Reaction{ID: "reaction1", Type: model.ReactionDislike, CreatedAt: now.Add(-2 * time.Hour)},
+			expectedError: ErrAlreadySwiped,
+		},
+		{
+			name:          "Error - Likes Never Resurface",
+			swiper:        &model.User{ID: "user1", DeckResetAt: &resetAt},
+			previous:      model.Reaction{ID: "reaction1", Type: model.ReactionLike, CreatedAt: now.Add(-31 * 24 * time.Hour)},
+			expectedError: ErrAlreadySwiped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			reactionRepo := new(mocks.IReactionRepository)
+			reactionRepo.On("LockUser", mock.Anything, "user1").Return(nil).Once()
+			userRepo.On("FindByID", mock.Anything, "user1").Return(tt.swiper, nil)
+			userRepo.On("FindByID", mock.Anything, "user2").Return(&model.User{ID: "user2"}, nil).Once()
+			reactionRepo.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
+			reactionRepo.On("HasSwiped", mock.Anything, "user1", "user2").Return(tt.previous, nil).Once()
+
+			if tt.expectedError == nil {
+				reactionRepo.On("Replace", mock.Anything, "reaction1", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil).Once()
+				reactionRepo.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{}, nil).Once()
+				reactionRepo.On("Create", mock.Anything, mock.AnythingOfType("model.Reaction")).Return(nil).Once()
+			}
+
+			service := NewReactionService(conf, passthroughTransactor(), userRepo, reactionRepo, new(mocks.ISubscriptionRepository), new(mocks.INotificationRepository), neverMatched(new(mocks.IMatchRepository)), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), unlimitedQuotaService(), new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(userRepo, new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+
+			_, err := service.Swipe(ctx, model.ReactionRequest{UserID: "user1", MatchedUserID: "user2", Type: model.ReactionLike})
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			userRepo.AssertExpectations(t)
+			reactionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReactionService_StartOver(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IUserRepository, *mocks.IQuotaService)
+		expectedError error
+	}{
+		{
+			name: "Success",
+			setupMocks: func(ur *mocks.IUserRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(&model.SubscriptionPlan{ID: 1, Name: model.SubscriptionPlanBasic}, nil)
+				ur.On("FindByID", mock.Anything, "user1").Return(&model.User{ID: "user1"}, nil)
+				ur.On("Update", mock.MatchedBy(func(u *model.User) bool {
+					return u.DeckResetAt != nil
+				})).Return(&model.User{ID: "user1"}, nil)
+			},
+		},
+		{
+			name: "Error - Free Plan",
+			setupMocks: func(ur *mocks.IUserRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(freePlan(), nil)
+			},
+			expectedError: ErrStartOverNotAllowed,
+		},
+		{
+			name: "Error - Failed to Update User",
+			setupMocks: func(ur *mocks.IUserRepository, qs *mocks.IQuotaService) {
+				qs.On("FindPlan", mock.Anything, "user1").Return(&model.SubscriptionPlan{ID: 2, Name: model.SubscriptionPlanPro}, nil)
+				ur.On("FindByID", mock.Anything, "user1").Return(&model.User{ID: "user1"}, nil)
+				ur.On("Update", mock.Anything).Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("failed to start over"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			quotaService := new(mocks.IQuotaService)
+			tt.setupMocks(userRepo, quotaService)
+
+			service := NewReactionService(&config.Config{}, passthroughTransactor(), userRepo, new(mocks.IReactionRepository), new(mocks.ISubscriptionRepository), new(mocks.INotificationRepository), new(mocks.IMatchRepository), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), quotaService, new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(userRepo, new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+
+			err := service.StartOver(ctx, "user1")
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			userRepo.AssertExpectations(t)
+			quotaService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReactionService_FindSent(t *testing.T) {
+	ctx := context.Background()
+	matchedAt := time.Now()
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+
+	tests := []struct {
+		name          string
+		request       model.SentReactionRequest
+		setupMocks    func(*mocks.IReactionRepository)
+		expected      []model.SentReaction
+		expectedTotal int64
+		expectedError error
+	}{
+		{
+			name:    "Success",
+			request: model.SentReactionRequest{Type: model.ReactionLike},
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("FindSwiped", mock.Anything, "user1", model.SentReactionFilter{Type: model.ReactionLike}, model.PaginationRequest{Page: 1, Limit: model.DefaultPageLimit}).Return([]model.Reaction{
+					{ID: "reaction1", Type: model.ReactionLike, MatchedAt: &matchedAt, MatchedUser: model.User{ID: "user2", Name: "User 2"}},
+					{ID: "reaction2", Type: model.ReactionLike, MatchedUser: model.User{ID: "user3", Name: "User 3"}},
+				}, int64(2), nil)
+			},
+			expected: []model.SentReaction{
+				{ID: "reaction1", Type: model.ReactionLike, Matched: true, MatchedAt: &matchedAt, User: model.MatchProfile{ID: "user2", Name: "User 2"}},
+				{ID: "reaction2", Type: model.ReactionLike, User: model.MatchProfile{ID: "user3", Name: "User 3"}},
+			},
+			expectedTotal: 2,
+		},
+		{
+			name:    "Success - Dates In User Timezone",
+			request: model.SentReactionRequest{From: "2026-10-01", To: "2026-10-01"},
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("FindSwiped", mock.Anything, "user1", mock.MatchedBy(func(f model.SentReactionFilter) bool {
+					return f.Since.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, jakarta)) && f.Until.Equal(time.Date(2026, 10, 2, 0, 0, 0, 0, jakarta))
+				}), mock.Anything).Return([]model.Reaction{}, int64(0), nil)
+			},
+			expected: []model.SentReaction{},
+		},
+		{
+			name:          "Error - End Before Start",
+			request:       model.SentReactionRequest{From: "2026-10-02", To: "2026-10-01"},
+			setupMocks:    func(rr *mocks.IReactionRepository) {},
+			expectedError: ErrInvalidDateRange,
+		},
+		{
+			name:    "Error - Failed to Find Reactions",
+			request: model.SentReactionRequest{},
+			setupMocks: func(rr *mocks.IReactionRepository) {
+				rr.On("FindSwiped", mock.Anything, "user1", mock.Anything, mock.Anything).Return(nil, int64(0), errors.New("db error"))
+			},
+			expectedError: errors.New("failed to find sent reactions"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			reactionRepo := new(mocks.IReactionRepository)
+			userRepo.On("FindByID", mock.Anything, "user1").Return(&model.User{ID: "user1", Timezone: "Asia/Jakarta"}, nil)
+			tt.setupMocks(reactionRepo)
+
+			service := NewReactionService(&config.Config{}, passthroughTransactor(), userRepo, reactionRepo, new(mocks.ISubscriptionRepository), new(mocks.INotificationRepository), new(mocks.IMatchRepository), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), unlimitedQuotaService(), new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(userRepo, new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+
+			reactions, total, err := service.FindSent(ctx, "user1", tt.request)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, reactions)
+				assert.Equal(t, tt.expectedTotal, total)
+			}
+
+			reactionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReactionService_Swipe_MatchRules(t *testing.T) {
+	ctx := context.Background()
+	man := &model.User{ID: "user1", Gender: model.GenderMale}
+	woman := &model.User{ID: "user2", Gender: model.GenderFemale}
+	otherMan := &model.User{ID: "user2", Gender: model.GenderMale}
+
+	tests := []struct {
+		name            string
+		conf            config.Matches
+		swiper, target  *model.User
+		expectedFirst   *string
+		expectedExpires bool
+	}{
+		{
+			name:   "Anyone Goes First And Never Expires By Default",
+			swiper: man, target: woman,
+		},
+		{
+			name:   "Women First",
+			conf:   config.Matches{FirstMove: model.FirstMoveWomenFirst, ExpiresAfter: 24 * time.Hour},
+			swiper: man, target: woman,
+			expectedFirst: &woman.ID, expectedExpires: true,
+		},
+		{
+			name:   "Women First Doesn't Apply Between Men",
+			conf:   config.Matches{FirstMove: model.FirstMoveWomenFirst},
+			swiper: man, target: otherMan,
+		},
+		{
+			name:   "First Liker Goes First",
+			conf:   config.Matches{FirstMove: model.FirstMoveFirstLiker},
+			swiper: man, target: otherMan,
+			expectedFirst: &otherMan.ID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			reactionRepo := new(mocks.IReactionRepository)
+			reactionRepo.On("LockUser", mock.Anything, "user1").Return(nil).Once()
+			matchRepo := new(mocks.IMatchRepository)
+			notificationRepo := new(mocks.INotificationRepository)
+			userRepo.On("FindByID", mock.Anything, "user1").Return(tt.swiper, nil)
+			userRepo.On("FindByID", mock.Anything, "user2").Return(tt.target, nil)
+			reactionRepo.On("LockPair", mock.Anything, "user1", "user2").Return(nil).Once()
+			reactionRepo.On("HasSwiped", mock.Anything, "user1", "user2").Return(model.Reaction{}, nil).Once()
+			reactionRepo.On("FindMatch", mock.Anything, "user2", "user1").Return(model.Reaction{ID: "reaction1", UserID: "user2", MatchedUserID: "user1", Type: model.ReactionLike}, nil).Once()
+			reactionRepo.On("Update", mock.Anything, mock.AnythingOfType("*model.Reaction")).Return(nil).Once()
+			reactionRepo.On("Create", mock.Anything, mock.AnythingOfType("model.Reaction")).Return(nil).Once()
+			notificationRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil).Twice()
+
+			var created model.Match
+			matchRepo.On("Create", mock.Anything, mock.AnythingOfType("model.Match")).Run(func(args mock.Arguments) {
+				created = args.Get(1).(model.Match)
+			}).Return(nil).Once()
+
+			conf := &config.Config{Matches: tt.conf}
+			service := NewReactionService(conf, passthroughTransactor(), userRepo, reactionRepo, new(mocks.ISubscriptionRepository), notificationRepo, neverMatched(matchRepo), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), unlimitedQuotaService(), new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(userRepo, notificationRepo, realtime.NewMemoryBroker()), noDeferred())
+
+			_, err := service.Swipe(ctx, model.ReactionRequest{UserID: "user1", MatchedUserID: "user2", Type: model.ReactionLike})
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedFirst, created.FirstMoveBy)
+			if tt.expectedExpires {
+				assert.Equal(t, created.MatchedAt.Add(tt.conf.ExpiresAfter), *created.ExpiresAt)
+			} else {
+				assert.Nil(t, created.ExpiresAt)
+			}
+
+			userRepo.AssertExpectations(t)
+			reactionRepo.AssertExpectations(t)
+			matchRepo.AssertExpectations(t)
+			notificationRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func freePlan() *model.SubscriptionPlan {
+	swipeLimit, rewindLimit := 10, 0
+
+	return &model.SubscriptionPlan{
+		ID:               3,
+		Name:             model.SubscriptionPlanFree,
+		SuperLikeQuota:   1,
+		SuperLikePeriod:  model.QuotaPeriodWeekly,
+		DailySwipeLimit:  &swipeLimit,
+		DailyRewindLimit: &rewindLimit,
+	}
+}
+
+func unlimitedQuotaService() *mocks.IQuotaService {
+	plan := &model.SubscriptionPlan{ID: 2, Name: model.SubscriptionPlanPro}
+
+	quotaService := new(mocks.IQuotaService)
+	quotaService.On("FindPlan", mock.Anything, mock.Anything).Return(plan, nil)
+	quotaService.On("Check", mock.Anything, mock.Anything, plan, mock.Anything).Return(model.Quota{}, nil)
+
+	return quotaService
+}
+
+func idleBoostRepo() *mocks.IBoostRepository {
+	boostRepo := new(mocks.IBoostRepository)
+	boostRepo.On("IncrementLikes", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	boostRepo.On("DecrementLikes", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	return boostRepo
+}
+
+// swipeableUserRepo lets every profile through the eligibility checks of a swipe.
+func swipeableUserRepo(userRepo *mocks.IUserRepository) *mocks.IUserRepository {
+	userRepo.On("FindByID", mock.Anything, mock.Anything).Return(func(ctx context.Context, id string) (*model.User, error) {
+		return &model.User{ID: id}, nil
+	}).Maybe()
+
+	return userRepo
+}
+
+func neverMatched(matchRepo *mocks.IMatchRepository) *mocks.IMatchRepository {
+	matchRepo.On("FindByPair", mock.Anything, mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Maybe()
+	matchRepo.On("FindUnmatchedUserIDs", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+
+	return matchRepo
+}
+
+func testDeckTokens() decktoken.ISigner {
+	deckTokens, _ := decktoken.NewSigner("test-secret", model.DeckTokenTTL, false)
+
+	return deckTokens
+}
+
+// passthroughTransactor returns a transactor mock that simply runs the given function.
+func passthroughTransactor() *mocks.ITransactor {
+	transactor := new(mocks.ITransactor)
+	transactor.On("WithTransaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+
+	return transactor
+}
+
+// txTransactor returns a transactor mock that runs the given function with the returned context, so
+// expectations on that context only match calls made inside the transaction.
+func txTransactor(ctx context.Context) (*mocks.ITransactor, context.Context) {
+	txCtx := context.WithValue(ctx, txKey{}, true)
+
+	transactor := new(mocks.ITransactor)
+	transactor.On("WithTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(txCtx)
+	}).Maybe()
+
+	return transactor, txCtx
+}
+
+type (
+	txKey     struct{}
+	fakeTxKey struct{}
+
+	// fakeTransactor mimics postgres transaction-scoped advisory locks, every lock taken
+	// through LockPair or LockUser is released once the transaction ends.
+	fakeTransactor struct{}
+
+	fakeReactionRepository struct {
+		*mocks.IReactionRepository
+
+		mu        sync.Mutex
+		locks     map[string]*sync.Mutex
+		reactions []model.Reaction
+	}
+)
+
+func (fakeTransactor) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	held := &[]*sync.Mutex{}
+	err := fn(context.WithValue(ctx, fakeTxKey{}, held))
+
+	for _, lock := range *held {
+		lock.Unlock()
+	}
+
+	return err
+}
+
+func newFakeReactionRepository() *fakeReactionRepository {
+	return &fakeReactionRepository{IReactionRepository: new(mocks.IReactionRepository), locks: map[string]*sync.Mutex{}}
+}
+
+func (r *fakeReactionRepository) lock(ctx context.Context, key string) {
+	r.mu.Lock()
+	lock, ok := r.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		r.locks[key] = lock
+	}
+	r.mu.Unlock()
+
+	lock.Lock()
+	held := ctx.Value(fakeTxKey{}).(*[]*sync.Mutex)
+	*held = append(*held, lock)
+}
+
+func (r *fakeReactionRepository) LockPair(ctx context.Context, userID, matchedUserID string) error {
+	key := userID + ":" + matchedUserID
+	if matchedUserID < userID {
+		key = matchedUserID + ":" + userID
+	}
+
+	r.lock(ctx, key)
+
+	return nil
+}
+
+func (r *fakeReactionRepository) LockUser(ctx context.Context, userID string) error {
+	r.lock(ctx, "reactions:"+userID)
+
+	return nil
+}
+
+func (r *fakeReactionRepository) FindSwipeCount(ctx context.Context, userID string, since time.Time) (int64, error) {
+	r.mu.Lock()
+	count := int64(0)
+	for _, reaction := range r.reactions {
+		if reaction.UserID == userID {
+			count++
+		}
+	}
+	r.mu.Unlock()
+
+	// widen the window between the count and the write so a missing lock shows up as swipes past the limit
+	time.Sleep(time.Millisecond)
+
+	return count, nil
+}
+
+func (r *fakeReactionRepository) find(userID, matchedUserID, reactionType string) model.Reaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, reaction := range r.reactions {
+		if reaction.UserID == userID && reaction.MatchedUserID == matchedUserID && (reactionType == "" || reaction.Type == reactionType) {
+			return reaction
+		}
+	}
+
+	return model.Reaction{}
+}
+
+func (r *fakeReactionRepository) HasSwiped(ctx context.Context, userID, matchedUserID string) (model.Reaction, error) {
+	return r.find(userID, matchedUserID, ""), nil
+}
+
+func (r *fakeReactionRepository) FindMatch(ctx context.Context, userID, matchedUserID string) (model.Reaction, error) {
+	reaction := r.find(userID, matchedUserID, model.ReactionLike)
+
+	// widen the window between the read and the write so a missing lock shows up as a missed match
+	time.Sleep(time.Millisecond)
+
+	return reaction, nil
+}
+
+func (r *fakeReactionRepository) Create(ctx context.Context, reaction model.Reaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.reactions {
+		if existing.UserID == reaction.UserID && existing.MatchedUserID == reaction.MatchedUserID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	r.reactions = append(r.reactions, reaction)
+
+	return nil
+}
+
+func (r *fakeReactionRepository) Update(ctx context.Context, reaction *model.Reaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.reactions {
+		if existing.ID == reaction.ID {
+			r.reactions[i] = *reaction
+		}
+	}
+
+	return nil
+}
+
+func TestReactionService_Swipe_Concurrent(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Mutual Likes Produce Exactly One Match", func(t *testing.T) {
+		for i := 0; i < 50; i++ {
+			reactionRepo := newFakeReactionRepository()
+			notificationRepo := new(mocks.INotificationRepository)
+			notificationRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil)
+			matchRepo := new(mocks.IMatchRepository)
+			matchRepo.On("Create", mock.Anything, mock.AnythingOfType("model.Match")).Return(nil)
+
+			service := NewReactionService(&config.Config{}, fakeTransactor{}, swipeableUserRepo(new(mocks.IUserRepository)), reactionRepo, new(mocks.ISubscriptionRepository), notificationRepo, neverMatched(matchRepo), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), unlimitedQuotaService(), new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), notificationRepo, realtime.NewMemoryBroker()), noDeferred())
+
+			var wg sync.WaitGroup
+			for _, req := range []model.ReactionRequest{
+				{UserID: "user1", MatchedUserID: "user2", Type: model.ReactionLike},
+				{UserID: "user2", MatchedUserID: "user1", Type: model.ReactionLike},
+			} {
+				wg.Add(1)
+				go func(req model.ReactionRequest) {
+					defer wg.Done()
+
+					_, err := service.Swipe(ctx, req)
+					assert.NoError(t, err)
+				}(req)
+			}
+			wg.Wait()
+
+			matched := 0
+			for _, reaction := range reactionRepo.reactions {
+				if reaction.MatchedAt != nil {
+					matched++
+				}
+			}
+
+			assert.Len(t, reactionRepo.reactions, 2)
+			assert.Equal(t, 2, matched)
+			matchRepo.AssertNumberOfCalls(t, "Create", 1)
+			notificationRepo.AssertNumberOfCalls(t, "Create", 2)
+		}
+	})
+
+	t.Run("Duplicate Swipes Create One Reaction", func(t *testing.T) {
+		reactionRepo := newFakeReactionRepository()
+
+		service := NewReactionService(&config.Config{}, fakeTransactor{}, swipeableUserRepo(new(mocks.IUserRepository)), reactionRepo, new(mocks.ISubscriptionRepository), new(mocks.INotificationRepository), neverMatched(new(mocks.IMatchRepository)), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), unlimitedQuotaService(), new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			succeeded int
+		)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				_, err := service.Swipe(ctx, model.ReactionRequest{UserID: "user1", MatchedUserID: "user2", Type: model.ReactionLike})
+				if err == nil {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+
+					return
+				}
+
+				assert.Equal(t, "user has already swiped", err.Error())
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, succeeded)
+		assert.Len(t, reactionRepo.reactions, 1)
+	})
+
+	t.Run("Daily Swipe Limit Holds", func(t *testing.T) {
+		reactionRepo := newFakeReactionRepository()
+		// two swipes left of the free plan's ten
+		for i := 0; i < 8; i++ {
+			reactionRepo.reactions = append(reactionRepo.reactions, model.Reaction{ID: fmt.Sprintf("reaction%d", i), UserID: "user1", MatchedUserID: fmt.Sprintf("swiped%d", i), Type: model.ReactionDislike})
+		}
+
+		subscriptionRepo := new(mocks.ISubscriptionRepository)
+		subscriptionRepo.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{}, nil)
+		subscriptionRepo.On("FindPlanByName", mock.Anything, model.SubscriptionPlanFree).Return(freePlan(), nil)
+
+		service := NewReactionService(&config.Config{}, fakeTransactor{}, swipeableUserRepo(new(mocks.IUserRepository)), reactionRepo, subscriptionRepo, new(mocks.INotificationRepository), neverMatched(new(mocks.IMatchRepository)), new(mocks.IConversationRepository), new(mocks.ICreditRepository), idleBoostRepo(), NewQuotaService(utcUserRepo(), reactionRepo, subscriptionRepo, new(mocks.IBoostRepository)), new(mocks.IModerator), testDeckTokens(), testURLSigner(), new(mocks.IProxy), realtime.NewMemoryBroker(), testOutbox(swipeableUserRepo(new(mocks.IUserRepository)), new(mocks.INotificationRepository), realtime.NewMemoryBroker()), noDeferred())
+
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			succeeded int
+		)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(matchedUserID string) {
+				defer wg.Done()
+
+				_, err := service.Swipe(ctx, model.ReactionRequest{UserID: "user1", MatchedUserID: matchedUserID, Type: model.ReactionDislike})
+				if err == nil {
+					mu.Lock()
+					succeeded++
+					mu.Unlock()
+
+					return
+				}
+
+				assert.ErrorIs(t, err, ErrSwipeLimitReached)
+			}(fmt.Sprintf("user%d", i+2))
+		}
+		wg.Wait()
+
+		assert.Equal(t, 2, succeeded)
+		assert.Len(t, reactionRepo.reactions, 10)
+	})
+}
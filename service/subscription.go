@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/marvelalexius/jones/config"
@@ -20,9 +19,11 @@ import (
 type (
 	SubscriptionService struct {
 		Conf             *config.Config
+		Transactor       repository.ITransactor
 		StripeClient     stripePkg.IStripeClient
 		UserRepo         repository.IUserRepository
 		SubscriptionRepo repository.ISubscriptionRepository
+		OutboxRepo       repository.IOutboxRepository
 	}
 
 	ISubscriptionService interface {
@@ -35,8 +36,8 @@ type (
 	}
 )
 
-func NewSubscriptionService(conf *config.Config, stripeClient stripePkg.IStripeClient, userRepo repository.IUserRepository, subscriptionRepo repository.ISubscriptionRepository) ISubscriptionService {
-	return &SubscriptionService{Conf: conf, StripeClient: stripeClient, UserRepo: userRepo, SubscriptionRepo: subscriptionRepo}
+func NewSubscriptionService(conf *config.Config, transactor repository.ITransactor, stripeClient stripePkg.IStripeClient, userRepo repository.IUserRepository, subscriptionRepo repository.ISubscriptionRepository, outboxRepo repository.IOutboxRepository) ISubscriptionService {
+	return &SubscriptionService{Conf: conf, Transactor: transactor, StripeClient: stripeClient, UserRepo: userRepo, SubscriptionRepo: subscriptionRepo, OutboxRepo: outboxRepo}
 }
 
 func (s *SubscriptionService) Subscribe(ctx context.Context, userID string, req model.SubscriptionRequest) (string, error) {
@@ -54,6 +55,10 @@ func (s *SubscriptionService) Subscribe(ctx context.Context, userID string, req
 		return "", err
 	}
 
+	if plan.Name == model.SubscriptionPlanFree {
+		return "", errors.New("cannot subscribe to the free plan")
+	}
+
 	subscription, err := s.SubscriptionRepo.FindByUserID(ctx, user.ID)
 	if err != nil && err != gorm.ErrRecordNotFound {
 		logger.Errorln(ctx, "error finding subscription by user ID", err)
@@ -176,50 +181,53 @@ func (s *SubscriptionService) HandleInvoicePaid(ctx context.Context, invoice *st
 	*	If the latest subscription's Stripe subscription ID is not equal to the Stripe subscription ID in the invoice, we need to update the subscription to canceled, and create a new one based on the new subscribed plan.
 	*
 	 */
-	if subscription != nil {
-		if subscription.StripeSubscriptionID == invoice.Subscription.ID && subscription.PlanID == paidPlan.ID {
-			subscription.ExpiredAt = time.Unix(int64(stripeProduct.Period.End), 0)
-
-			err = s.SubscriptionRepo.Update(ctx, subscription)
-			if err != nil {
-				logger.Errorln(ctx, "error updating subscription", err)
-
-				return err
-			}
-
-			return nil
-		} else {
-			now := time.Now()
-			subscription.CanceledAt = sql.NullTime{Time: now, Valid: true}
-
-			err = s.SubscriptionRepo.Update(ctx, subscription)
-			if err != nil {
-				logger.Errorln(ctx, "error updating subscription", err)
-
-				return err
+	// the notification is written with the subscription, Stripe retries the webhook when either fails
+	return s.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if subscription != nil {
+			if subscription.StripeSubscriptionID == invoice.Subscription.ID && subscription.PlanID == paidPlan.ID {
+				subscription.ExpiredAt = time.Unix(int64(stripeProduct.Period.End), 0)
+
+				err = s.SubscriptionRepo.Update(ctx, subscription)
+				if err != nil {
+					logger.Errorln(ctx, "error updating subscription", err)
+
+					return err
+				}
+
+				return s.enqueueNotification(ctx, user.ID, subscription.ID, model.SubscriptionPayload{Type: model.NotificationTypeSubscriptionRenewed, SubscriptionID: subscription.ID, ExpiresAt: subscription.ExpiredAt})
+			} else {
+				now := time.Now()
+				subscription.CanceledAt = sql.NullTime{Time: now, Valid: true}
+
+				err = s.SubscriptionRepo.Update(ctx, subscription)
+				if err != nil {
+					logger.Errorln(ctx, "error updating subscription", err)
+
+					return err
+				}
 			}
 		}
-	}
 
-	periodStart := time.Unix(int64(stripeProduct.Period.Start), 0)
-	periodEnd := time.Unix(int64(stripeProduct.Period.End), 0)
-	newSubscription := model.Subscription{
-		ID:                   ulid.Make().String(),
-		UserID:               user.ID,
-		StripeSubscriptionID: invoice.Subscription.ID,
-		PlanID:               paidPlan.ID,
-		StartedAt:            periodStart,
-		ExpiredAt:            periodEnd,
-	}
+		periodStart := time.Unix(int64(stripeProduct.Period.Start), 0)
+		periodEnd := time.Unix(int64(stripeProduct.Period.End), 0)
+		newSubscription := model.Subscription{
+			ID:                   ulid.Make().String(),
+			UserID:               user.ID,
+			StripeSubscriptionID: invoice.Subscription.ID,
+			PlanID:               paidPlan.ID,
+			StartedAt:            periodStart,
+			ExpiredAt:            periodEnd,
+		}
 
-	err = s.SubscriptionRepo.Create(ctx, newSubscription)
-	if err != nil {
-		logger.Errorln(ctx, "error creating subscription", err)
+		err = s.SubscriptionRepo.Create(ctx, newSubscription)
+		if err != nil {
+			logger.Errorln(ctx, "error creating subscription", err)
 
-		return err
-	}
+			return err
+		}
 
-	return nil
+		return s.enqueueNotification(ctx, user.ID, newSubscription.ID, model.SubscriptionPayload{Type: model.NotificationTypeSubscriptionStarted, SubscriptionID: newSubscription.ID, ExpiresAt: periodEnd})
+	})
 }
 
 func (s *SubscriptionService) HandleInvoicePaymentFailed(ctx context.Context, customerEmail string) error {
@@ -238,15 +246,10 @@ func (s *SubscriptionService) HandleInvoicePaymentFailed(ctx context.Context, cu
 	}
 
 	if subscription != nil {
-		// TODO: notify user that payment failed and their subscription is still active until expiration date
-		logger.Infoln(ctx, "your payment has failed. your subscription is still active until expiration date")
-		return nil
+		return s.enqueueNotification(ctx, user.ID, subscription.ID, model.PaymentFailedPayload{ActiveUntil: &subscription.ExpiredAt})
 	}
 
-	// TODO: notify user that payment failed
-	logger.Infoln(ctx, "your payment has failed. Not to worry, your credit card has not been charged. please try again, or contact support")
-
-	return nil
+	return s.enqueueNotification(ctx, user.ID, "", model.PaymentFailedPayload{})
 }
 
 func (s *SubscriptionService) HandleSubscriptionUpdated(ctx context.Context, stripeSubscription *stripe.Subscription) error {
@@ -293,20 +296,20 @@ func (s *SubscriptionService) HandleSubscriptionUpdated(ctx context.Context, str
 	subscription.PlanID = updatedPlan.ID
 	subscription.ExpiredAt = time.Unix(int64(stripeSubscription.CurrentPeriodEnd), 0)
 
-	err = s.SubscriptionRepo.Update(ctx, subscription)
-	if err != nil {
-		logger.Errorln(ctx, "error updating subscription", err)
+	return s.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		err := s.SubscriptionRepo.Update(ctx, subscription)
+		if err != nil {
+			logger.Errorln(ctx, "error updating subscription", err)
 
-		return err
-	}
+			return err
+		}
 
-	// TODO: notify user that their subscription has been canceled
-	if stripeSubscription.CanceledAt != 0 {
-		logger.Infoln(ctx, fmt.Sprintf("your subscription has been canceled. You still have access to your account until %s", subscription.ExpiredAt.Format("2006-01-02")))
-	}
+		if stripeSubscription.CanceledAt == 0 {
+			return nil
+		}
 
-	fmt.Println(user)
-	return nil
+		return s.enqueueNotification(ctx, user.ID, subscription.ID, model.SubscriptionPayload{Type: model.NotificationTypeSubscriptionCanceled, SubscriptionID: subscription.ID, ExpiresAt: subscription.ExpiredAt})
+	})
 }
 
 func (s *SubscriptionService) HandleSubscriptionDeleted(ctx context.Context, stripeSubscription *stripe.Subscription) error {
@@ -327,16 +330,36 @@ func (s *SubscriptionService) HandleSubscriptionDeleted(ctx context.Context, str
 	canceledAt := time.Unix(int64(stripeSubscription.CanceledAt), 0)
 	subscription.CanceledAt = sql.NullTime{Time: canceledAt, Valid: true}
 
-	err = s.SubscriptionRepo.Update(ctx, subscription)
+	return s.Transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		err := s.SubscriptionRepo.Update(ctx, subscription)
+		if err != nil {
+			logger.Errorln(ctx, "error updating subscription", err)
+
+			return err
+		}
+
+		return s.enqueueNotification(ctx, user.ID, subscription.ID, model.SubscriptionPayload{Type: model.NotificationTypeSubscriptionCanceled, SubscriptionID: subscription.ID, ExpiresAt: subscription.ExpiredAt})
+	})
+}
+
+func (s *SubscriptionService) enqueueNotification(ctx context.Context, userID, referenceID string, payload model.NotificationPayload) error {
+	return enqueueNotification(ctx, s.OutboxRepo, userID, referenceID, payload)
+}
+
+// findActivePlan returns the plan the user is currently subscribed to, or nil when the user has no subscription.
+func findActivePlan(ctx context.Context, subscriptionRepo repository.ISubscriptionRepository, userID string) (*model.SubscriptionPlan, error) {
+	subscription, err := subscriptionRepo.FindByUserID(ctx, userID)
 	if err != nil {
-		logger.Errorln(ctx, "error updating subscription", err)
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
 
-		return err
+		return nil, err
 	}
 
-	// TODO: notify user that their subscription has been canceled
-	logger.Infoln(ctx, fmt.Sprintf("your subscription has been canceled. You still have access to your account until %s", subscription.ExpiredAt.Format("2006-01-02")))
+	if subscription == nil || subscription.ID == "" {
+		return nil, nil
+	}
 
-	fmt.Println(user)
-	return nil
+	return subscriptionRepo.FindPlanByID(ctx, subscription.PlanID)
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/marvelalexius/jones/config"
 	"github.com/marvelalexius/jones/mocks"
 	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/realtime"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stripe/stripe-go/v76"
@@ -20,9 +21,11 @@ func TestSubscribe(t *testing.T) {
 	mockStripeClient := new(mocks.IStripeClient)
 	mockUserRepo := new(mocks.IUserRepository)
 	mockSubscriptionRepo := new(mocks.ISubscriptionRepository)
+	mockNotificationRepo := new(mocks.INotificationRepository)
+	mockNotificationRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil)
 
 	conf := &config.Config{}
-	subscriptionService := NewSubscriptionService(conf, mockStripeClient, mockUserRepo, mockSubscriptionRepo)
+	subscriptionService := NewSubscriptionService(conf, passthroughTransactor(), mockStripeClient, mockUserRepo, mockSubscriptionRepo, testOutbox(mockUserRepo, mockNotificationRepo, realtime.NewMemoryBroker()))
 
 	tests := []struct {
 		name          string
@@ -131,9 +134,11 @@ func TestCustomerPortal(t *testing.T) {
 	mockStripeClient := new(mocks.IStripeClient)
 	mockUserRepo := new(mocks.IUserRepository)
 	mockSubscriptionRepo := new(mocks.ISubscriptionRepository)
+	mockNotificationRepo := new(mocks.INotificationRepository)
+	mockNotificationRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil)
 
 	conf := &config.Config{}
-	subscriptionService := NewSubscriptionService(conf, mockStripeClient, mockUserRepo, mockSubscriptionRepo)
+	subscriptionService := NewSubscriptionService(conf, passthroughTransactor(), mockStripeClient, mockUserRepo, mockSubscriptionRepo, testOutbox(mockUserRepo, mockNotificationRepo, realtime.NewMemoryBroker()))
 
 	tests := []struct {
 		name          string
@@ -192,9 +197,13 @@ func TestHandleInvoicePaid(t *testing.T) {
 	mockStripeClient := new(mocks.IStripeClient)
 	mockUserRepo := new(mocks.IUserRepository)
 	mockSubscriptionRepo := new(mocks.ISubscriptionRepository)
+	mockNotificationRepo := new(mocks.INotificationRepository)
+	mockNotificationRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil)
+	// notifications are written in the user's language
+	mockUserRepo.On("FindByID", ctx, "user123").Return(&model.User{ID: "user123", Language: model.DefaultLanguage}, nil)
 
 	conf := &config.Config{}
-	subscriptionService := NewSubscriptionService(conf, mockStripeClient, mockUserRepo, mockSubscriptionRepo)
+	subscriptionService := NewSubscriptionService(conf, passthroughTransactor(), mockStripeClient, mockUserRepo, mockSubscriptionRepo, testOutbox(mockUserRepo, mockNotificationRepo, realtime.NewMemoryBroker()))
 
 	testTime := time.Now()
 	periodEnd := int64(time.Now().Add(30 * 24 * time.Hour).Unix())
@@ -295,9 +304,11 @@ func TestHandleSubscriptionUpdated(t *testing.T) {
 	mockStripeClient := new(mocks.IStripeClient)
 	mockUserRepo := new(mocks.IUserRepository)
 	mockSubscriptionRepo := new(mocks.ISubscriptionRepository)
+	mockNotificationRepo := new(mocks.INotificationRepository)
+	mockNotificationRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil)
 
 	conf := &config.Config{}
-	subscriptionService := NewSubscriptionService(conf, mockStripeClient, mockUserRepo, mockSubscriptionRepo)
+	subscriptionService := NewSubscriptionService(conf, passthroughTransactor(), mockStripeClient, mockUserRepo, mockSubscriptionRepo, testOutbox(mockUserRepo, mockNotificationRepo, realtime.NewMemoryBroker()))
 
 	periodEnd := int64(time.Now().Add(30 * 24 * time.Hour).Unix())
 
@@ -392,9 +403,12 @@ func TestHandleInvoicePaymentFailed(t *testing.T) {
 	mockStripeClient := new(mocks.IStripeClient)
 	mockUserRepo := new(mocks.IUserRepository)
 	mockSubscriptionRepo := new(mocks.ISubscriptionRepository)
+	mockNotificationRepo := new(mocks.INotificationRepository)
+	mockNotificationRepo.On("Create", mock.AnythingOfType("*model.Notification")).Return(true, nil)
+	mockUserRepo.On("FindByID", ctx, "user123").Return(&model.User{ID: "user123", Language: model.DefaultLanguage}, nil)
 
 	conf := &config.Config{}
-	subscriptionService := NewSubscriptionService(conf, mockStripeClient, mockUserRepo, mockSubscriptionRepo)
+	subscriptionService := NewSubscriptionService(conf, passthroughTransactor(), mockStripeClient, mockUserRepo, mockSubscriptionRepo, testOutbox(mockUserRepo, mockNotificationRepo, realtime.NewMemoryBroker()))
 
 	tests := []struct {
 		name          string
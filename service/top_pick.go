@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/decktoken"
+	"github.com/marvelalexius/jones/repository"
+	"github.com/marvelalexius/jones/utils/logger"
+	"github.com/marvelalexius/jones/utils/str"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	topPicksBatchSize = 100
+	// the closest in age score the highest, scoring more of them rarely changes the picks
+	topPickCandidatesSize = 100
+)
+
+type (
+	TopPickService struct {
+		UserRepo         repository.IUserRepository
+		ReactionRepo     repository.IReactionRepository
+		MatchRepo        repository.IMatchRepository
+		SubscriptionRepo repository.ISubscriptionRepository
+		TopPickRepo      repository.ITopPickRepository
+		DeckTokens       decktoken.ISigner
+	}
+
+	ITopPickService interface {
+		Generate(ctx context.Context) error
+		GenerateForUser(ctx context.Context, user model.User) error
+		FindTopPicks(ctx context.Context, userID string) (picks []model.TopPick, total int, err error)
+	}
+)
+
+func NewTopPickService(userRepo repository.IUserRepository, reactionRepo repository.IReactionRepository, matchRepo repository.IMatchRepository, subscriptionRepo repository.ISubscriptionRepository, topPickRepo repository.ITopPickRepository, deckTokens decktoken.ISigner) ITopPickService {
+	return &TopPickService{UserRepo: userRepo, ReactionRepo: reactionRepo, MatchRepo: matchRepo, SubscriptionRepo: subscriptionRepo, TopPickRepo: topPickRepo, DeckTokens: deckTokens}
+}
+
+// Generate walks through every user and creates today's top picks for those who don't have an active batch yet.
+// It is safe to run it several times a day, users whose picks are still active are skipped.
+func (s *TopPickService) Generate(ctx context.Context) error {
+	if err := s.TopPickRepo.DeleteExpired(ctx, time.Now()); err != nil {
+		logger.Errorln(ctx, "failed to delete expired top picks", err)
+
+		return errors.New("failed to delete expired top picks")
+	}
+
+	lastID := ""
+	for {
+		users, err := s.UserRepo.FindBatch(ctx, lastID, topPicksBatchSize)
+		if err != nil {
+			logger.Errorln(ctx, "failed to find users batch", err)
+
+			return errors.New("failed to find users")
+		}
+
+		for _, user := range users {
+			if err := s.GenerateForUser(ctx, user); err != nil {
+				logger.Errorln(ctx, "failed to generate top picks for user", user.ID, err)
+			}
+		}
+
+		if len(users) < topPicksBatchSize {
+			break
+		}
+
+		lastID = users[len(users)-1].ID
+	}
+
+	return nil
+}
+
+func (s *TopPickService) GenerateForUser(ctx context.Context, user model.User) error {
+	now := time.Now()
+
+	active, err := s.TopPickRepo.HasActive(ctx, user.ID, now)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find active top picks", err)
+
+		return errors.New("failed to find active top picks")
+	}
+
+	if active {
+		return nil
+	}
+
+	// picks are hand selected, a profile the user passed on never comes back as one
+	swiped, err := s.ReactionRepo.FindSwipedUserIDs(ctx, user.ID, time.Time{})
+	if err != nil {
+		logger.Errorln(ctx, "failed to find swiped", err)
+
+		return errors.New("failed to find swiped")
+	}
+
+	// nor does anyone either of them unmatched
+	unmatched, err := s.MatchRepo.FindUnmatchedUserIDs(ctx, user.ID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find unmatched users", err)
+
+		return errors.New("failed to find unmatched users")
+	}
+
+	userIDs := append(append([]string{user.ID}, swiped...), unmatched...)
+
+	candidates, err := s.UserRepo.FindTopPickCandidates(ctx, user, userIDs, topPickCandidatesSize)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find candidates", err)
+
+		return errors.New("failed to find candidates")
+	}
+
+	// picks expire at midnight in the user's timezone
+	_, endOfDay := str.GetDayTimeRange(now, user.Location())
+
+	picks := []model.TopPick{}
+	for _, candidate := range candidates {
+		picks = append(picks, model.TopPick{
+			ID:           ulid.Make().String(),
+			UserID:       user.ID,
+			PickedUserID: candidate.ID,
+			Score:        compatibilityScore(user, candidate),
+			ExpiredAt:    endOfDay,
+			CreatedAt:    now,
+		})
+	}
+
+	if len(picks) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(picks, func(i, j int) bool {
+		return picks[i].Score > picks[j].Score
+	})
+
+	if len(picks) > model.TopPicksDailySize {
+		picks = picks[:model.TopPicksDailySize]
+	}
+
+	if err := s.TopPickRepo.BulkCreate(ctx, picks); err != nil {
+		logger.Errorln(ctx, "failed to create top picks", err)
+
+		return errors.New("failed to create top picks")
+	}
+
+	return nil
+}
+
+func (s *TopPickService) FindTopPicks(ctx context.Context, userID string) ([]model.TopPick, int, error) {
+	plan, err := findActivePlan(ctx, s.SubscriptionRepo, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to check subscription", err)
+
+		return nil, 0, errors.New("failed to check subscription")
+	}
+
+	now := time.Now()
+	picks, err := s.TopPickRepo.FindActiveByUserID(ctx, userID, now)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find top picks", err)
+
+		return nil, 0, errors.New("failed to find top picks")
+	}
+
+	total := len(picks)
+	limit := model.TopPicksFreeSize
+	if plan != nil && model.HasFeature(plan.Name, model.FeatureTopPicks) {
+		limit = model.TopPicksDailySize
+	}
+
+	if len(picks) > limit {
+		picks = picks[:limit]
+	}
+
+	for i := range picks {
+		picks[i].PickedUser.DeckToken = s.DeckTokens.Sign(userID, picks[i].PickedUserID, now)
+	}
+
+	return picks, total, nil
+}
+
+// compatibilityScore ranks a candidate for the given user. Closer age and a more complete profile score higher.
+func compatibilityScore(user, candidate model.User) int {
+	ageDiff := user.Age - candidate.Age
+	if ageDiff < 0 {
+		ageDiff = -ageDiff
+	}
+
+	score := 50 - ageDiff*5
+	if score < 0 {
+		score = 0
+	}
+
+	if candidate.Bio != "" {
+		score += 10
+	}
+
+	images := candidate.ImageCount
+	if images > 5 {
+		images = 5
+	}
+
+	return score + images*5
+}
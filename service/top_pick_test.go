@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/marvelalexius/jones/mocks"
+	"github.com/marvelalexius/jones/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestTopPickService_FindTopPicks(t *testing.T) {
+	ctx := context.Background()
+
+	picks := []model.TopPick{}
+	for i := 0; i < model.TopPicksDailySize; i++ {
+		picks = append(picks, model.TopPick{ID: "pick", UserID: "user1"})
+	}
+
+	tests := []struct {
+		name          string
+		userID        string
+		setupMocks    func(*mocks.ISubscriptionRepository, *mocks.ITopPickRepository)
+		expectedCount int
+		expectedTotal int
+		expectedError error
+	}{
+		{
+			name:   "Success - Free User Sees Limited Picks",
+			userID: "user1",
+			setupMocks: func(sr *mocks.ISubscriptionRepository, tr *mocks.ITopPickRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(nil, gorm.ErrRecordNotFound)
+				tr.On("FindActiveByUserID", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(picks, nil)
+			},
+			expectedCount: model.TopPicksFreeSize,
+			expectedTotal: model.TopPicksDailySize,
+		},
+		{
+			name:   "Success - Basic User Sees Limited Picks",
+			userID: "user1",
+			setupMocks: func(sr *mocks.ISubscriptionRepository, tr *mocks.ITopPickRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{ID: "sub1", PlanID: 1}, nil)
+				sr.On("FindPlanByID", mock.Anything, 1).Return(&model.SubscriptionPlan{ID: 1, Name: model.SubscriptionPlanBasic}, nil)
+				tr.On("FindActiveByUserID", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(picks, nil)
+			},
+			expectedCount: model.TopPicksFreeSize,
+			expectedTotal: model.TopPicksDailySize,
+		},
+		{
+			name:   "Success - Pro User Sees Full Set",
+			userID: "user1",
+			setupMocks: func(sr *mocks.ISubscriptionRepository, tr *mocks.ITopPickRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(&model.Subscription{ID: "sub1", PlanID: 2}, nil)
+				sr.On("FindPlanByID", mock.Anything, 2).Return(&model.SubscriptionPlan{ID: 2, Name: model.SubscriptionPlanPro}, nil)
+				tr.On("FindActiveByUserID", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(picks, nil)
+			},
+			expectedCount: model.TopPicksDailySize,
+			expectedTotal: model.TopPicksDailySize,
+		},
+		{
+			name:   "Error - Check Subscription",
+			userID: "user1",
+			setupMocks: func(sr *mocks.ISubscriptionRepository, tr *mocks.ITopPickRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(nil, gorm.ErrInvalidDB)
+			},
+			expectedError: errors.New("failed to check subscription"),
+		},
+		{
+			name:   "Error - Find Top Picks",
+			userID: "user1",
+			setupMocks: func(sr *mocks.ISubscriptionRepository, tr *mocks.ITopPickRepository) {
+				sr.On("FindByUserID", mock.Anything, "user1").Return(nil, gorm.ErrRecordNotFound)
+				tr.On("FindActiveByUserID", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(nil, gorm.ErrInvalidDB)
+			},
+			expectedError: errors.New("failed to find top picks"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			reactionRepo := new(mocks.IReactionRepository)
+			subscriptionRepo := new(mocks.ISubscriptionRepository)
+			topPickRepo := new(mocks.ITopPickRepository)
+
+			tt.setupMocks(subscriptionRepo, topPickRepo)
+
+			service := NewTopPickService(userRepo, reactionRepo, new(mocks.IMatchRepository), subscriptionRepo, topPickRepo, testDeckTokens())
+			result, total, err := service.FindTopPicks(ctx, tt.userID)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result, tt.expectedCount)
+				assert.Equal(t, tt.expectedTotal, total)
+			}
+
+			subscriptionRepo.AssertExpectations(t)
+			topPickRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTopPickService_GenerateForUser(t *testing.T) {
+	ctx := context.Background()
+
+	user := model.User{ID: "user1", Gender: model.GenderMale, Preference: model.PreferenceFemale, Age: 25}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(*mocks.IUserRepository, *mocks.IReactionRepository, *mocks.IMatchRepository, *mocks.ITopPickRepository)
+		expectedError error
+	}{
+		{
+			name: "Success - Skip When Picks Are Still Active",
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, tr *mocks.ITopPickRepository) {
+				tr.On("HasActive", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(true, nil)
+			},
+		},
+		{
+			name: "Success - Candidates Ranked By Score Without Swiped Or Unmatched",
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, tr *mocks.ITopPickRepository) {
+				tr.On("HasActive", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(false, nil)
+				rr.On("FindSwipedUserIDs", mock.Anything, "user1", time.Time{}).Return([]string{"user2"}, nil)
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user1").Return([]string{"user6"}, nil)
+
+				candidates := []model.User{
+					{ID: "user4", Gender: model.GenderFemale, Preference: model.PreferenceMale, Age: 35, ImageCount: 5},
+					{ID: "user5", Gender: model.GenderFemale, Preference: model.PreferenceBoth, Age: 26, Bio: "hi", ImageCount: 1},
+				}
+				ur.On("FindTopPickCandidates", mock.Anything, user, []string{"user1", "user2", "user6"}, topPickCandidatesSize).Return(candidates, nil)
+				tr.On("BulkCreate", mock.Anything, mock.MatchedBy(func(picks []model.TopPick) bool {
+					return len(picks) == 2 && picks[0].PickedUserID == "user5" && picks[0].Score == 60 && picks[1].PickedUserID == "user4" && picks[1].Score == 25
+				})).Return(nil)
+			},
+		},
+		{
+			name: "Error - Find Unmatched Users",
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, tr *mocks.ITopPickRepository) {
+				tr.On("HasActive", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(false, nil)
+				rr.On("FindSwipedUserIDs", mock.Anything, "user1", time.Time{}).Return([]string{}, nil)
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user1").Return(nil, gorm.ErrInvalidDB)
+			},
+			expectedError: errors.New("failed to find unmatched users"),
+		},
+		{
+			name: "Success - No Candidates",
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, tr *mocks.ITopPickRepository) {
+				tr.On("HasActive", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(false, nil)
+				rr.On("FindSwipedUserIDs", mock.Anything, "user1", time.Time{}).Return([]string{}, nil)
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user1").Return(nil, nil)
+				ur.On("FindTopPickCandidates", mock.Anything, user, []string{"user1"}, topPickCandidatesSize).Return([]model.User{}, nil)
+			},
+		},
+		{
+			name: "Error - Create Top Picks",
+			setupMocks: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, tr *mocks.ITopPickRepository) {
+				tr.On("HasActive", mock.Anything, "user1", mock.AnythingOfType("time.Time")).Return(false, nil)
+				rr.On("FindSwipedUserIDs", mock.Anything, "user1", time.Time{}).Return([]string{}, nil)
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user1").Return(nil, nil)
+				ur.On("FindTopPickCandidates", mock.Anything, user, []string{"user1"}, topPickCandidatesSize).Return([]model.User{
+					{ID: "user3", Gender: model.GenderFemale, Preference: model.PreferenceMale, Age: 25},
+				}, nil)
+				tr.On("BulkCreate", mock.Anything, mock.Anything).Return(gorm.ErrInvalidDB)
+			},
+			expectedError: errors.New("failed to create top picks"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			reactionRepo := new(mocks.IReactionRepository)
+			matchRepo := new(mocks.IMatchRepository)
+			subscriptionRepo := new(mocks.ISubscriptionRepository)
+			topPickRepo := new(mocks.ITopPickRepository)
+
+			tt.setupMocks(userRepo, reactionRepo, matchRepo, topPickRepo)
+
+			service := NewTopPickService(userRepo, reactionRepo, matchRepo, subscriptionRepo, topPickRepo, testDeckTokens())
+			err := service.GenerateForUser(ctx, user)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			userRepo.AssertExpectations(t)
+			reactionRepo.AssertExpectations(t)
+			matchRepo.AssertExpectations(t)
+			topPickRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/marvelalexius/jones/config"
 	"github.com/marvelalexius/jones/model"
+	"github.com/marvelalexius/jones/pkg/decktoken"
 	"github.com/marvelalexius/jones/repository"
 	"github.com/marvelalexius/jones/utils/logger"
 	"github.com/marvelalexius/jones/utils/str"
@@ -21,19 +22,24 @@ type (
 		Config       *config.Config
 		UserRepo     repository.IUserRepository
 		ReactionRepo repository.IReactionRepository
+		MatchRepo    repository.IMatchRepository
+		BoostRepo    repository.IBoostRepository
+		DeckTokens   decktoken.ISigner
 	}
 
 	IUserService interface {
 		Login(ctx context.Context, req model.LoginUser) (*model.User, error)
 		Register(ctx context.Context, user *model.RegisterUser) (*model.User, error)
-		FindAll(ctx context.Context, userID string) (users []model.User, total int64, err error)
+		FindAll(ctx context.Context, userID string, pagination model.PaginationRequest) (users []model.User, total int64, err error)
 		RefreshAuthToken(ctx context.Context, refreshToken string) (string, string, error)
 		GenerateAuthTokens(user *model.User) (string, string, error)
+		UpdateTimezone(ctx context.Context, userID string, req model.UpdateTimezoneRequest) (*model.User, error)
+		UpdateLanguage(ctx context.Context, userID string, req model.UpdateLanguageRequest) (*model.User, error)
 	}
 )
 
-func NewUserService(config *config.Config, userRepo repository.IUserRepository, reactionRepo repository.IReactionRepository) IUserService {
-	return &UserService{Config: config, UserRepo: userRepo, ReactionRepo: reactionRepo}
+func NewUserService(config *config.Config, userRepo repository.IUserRepository, reactionRepo repository.IReactionRepository, matchRepo repository.IMatchRepository, boostRepo repository.IBoostRepository, deckTokens decktoken.ISigner) IUserService {
+	return &UserService{Config: config, UserRepo: userRepo, ReactionRepo: reactionRepo, MatchRepo: matchRepo, BoostRepo: boostRepo, DeckTokens: deckTokens}
 }
 
 func (s *UserService) Login(ctx context.Context, req model.LoginUser) (*model.User, error) {
@@ -94,6 +100,47 @@ func (s *UserService) Register(ctx context.Context, req *model.RegisterUser) (*m
 	return user, nil
 }
 
+func (s *UserService) UpdateTimezone(ctx context.Context, userID string, req model.UpdateTimezoneRequest) (*model.User, error) {
+	user, err := s.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find user", err)
+
+		return nil, err
+	}
+
+	user.Timezone = req.Timezone
+
+	user, err = s.UserRepo.Update(user)
+	if err != nil {
+		logger.Errorln(ctx, "failed to update user", err)
+
+		return nil, errors.New("failed to update timezone")
+	}
+
+	return user, nil
+}
+
+// UpdateLanguage picks the language notifications are written in from now on.
+func (s *UserService) UpdateLanguage(ctx context.Context, userID string, req model.UpdateLanguageRequest) (*model.User, error) {
+	user, err := s.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find user", err)
+
+		return nil, err
+	}
+
+	user.Language = req.Language
+
+	user, err = s.UserRepo.Update(user)
+	if err != nil {
+		logger.Errorln(ctx, "failed to update user", err)
+
+		return nil, errors.New("failed to update language")
+	}
+
+	return user, nil
+}
+
 func (s *UserService) RefreshAuthToken(ctx context.Context, refreshToken string) (string, string, error) {
 	claims, err := str.ParseJWT(refreshToken, s.Config.App.RefreshTokenSecret)
 	if err != nil {
@@ -119,7 +166,7 @@ func (s *UserService) RefreshAuthToken(ctx context.Context, refreshToken string)
 	return token, refreshToken, nil
 }
 
-func (s *UserService) FindAll(ctx context.Context, userID string) (users []model.User, total int64, err error) {
+func (s *UserService) FindAll(ctx context.Context, userID string, pagination model.PaginationRequest) (users []model.User, total int64, err error) {
 	loggedInUser, err := s.UserRepo.FindByID(ctx, userID)
 	if err != nil {
 		logger.Errorln(ctx, "failed to get logged in user", err)
@@ -127,26 +174,115 @@ func (s *UserService) FindAll(ctx context.Context, userID string) (users []model
 		return []model.User{}, 0, err
 	}
 
-	swiped, err := s.ReactionRepo.FindSwiped(ctx, userID)
+	now := time.Now()
+	swiped, err := s.ReactionRepo.FindSwipedUserIDs(ctx, userID, loggedInUser.PassCutoff(now, s.Config.Discovery.PassCooldown))
 	if err != nil {
 		logger.Errorln(ctx, "failed to find swiped", err)
 
 		return []model.User{}, 0, err
 	}
 
-	userIDs := []string{loggedInUser.ID}
-	for _, swipedUser := range swiped {
-		userIDs = append(userIDs, swipedUser.MatchedUserID)
+	unmatched, err := s.MatchRepo.FindUnmatchedUserIDs(ctx, userID)
+	if err != nil {
+		logger.Errorln(ctx, "failed to find unmatched users", err)
+
+		return []model.User{}, 0, err
 	}
 
-	users, total, err = s.UserRepo.FindAll(ctx, userIDs, loggedInUser.Preference)
+	userIDs := []string{loggedInUser.ID}
+	userIDs = append(userIDs, swiped...)
+	userIDs = append(userIDs, unmatched...)
+
+	total, boostedTotal, err := s.UserRepo.CountDeck(ctx, userIDs, loggedInUser.Preference, now)
 	if err != nil {
-		logger.Errorln(ctx, "failed to find users", err)
+		logger.Errorln(ctx, "failed to count users", err)
 
 		return []model.User{}, 0, err
 	}
 
-	return users, total, err
+	pagination.Normalize()
+	start, end := pagination.Offset(), pagination.Offset()+pagination.Limit
+	if int64(start) >= total {
+		return []model.User{}, total, nil
+	}
+
+	// boosted users take their slots of the whole deck, so pages don't shift, only this page's are loaded
+	organicTotal := int(total - boostedTotal)
+	boostedStart, boostedEnd := boostedSlots(start, int(boostedTotal), organicTotal), boostedSlots(end, int(boostedTotal), organicTotal)
+
+	boosted := []model.User{}
+	if boostedEnd > boostedStart {
+		boosted, err = s.UserRepo.FindDeck(ctx, loggedInUser.ID, userIDs, loggedInUser.Preference, true, now, boostedStart, boostedEnd-boostedStart)
+		if err != nil {
+			logger.Errorln(ctx, "failed to find boosted users", err)
+
+			return []model.User{}, 0, err
+		}
+	}
+
+	organic := []model.User{}
+	if organicLimit := pagination.Limit - (boostedEnd - boostedStart); organicLimit > 0 {
+		organic, err = s.UserRepo.FindDeck(ctx, loggedInUser.ID, userIDs, loggedInUser.Preference, false, now, start-boostedStart, organicLimit)
+		if err != nil {
+			logger.Errorln(ctx, "failed to find users", err)
+
+			return []model.User{}, 0, err
+		}
+	}
+
+	// only the page that's served counts as seen
+	if len(boosted) > 0 {
+		viewed := make([]string, 0, len(boosted))
+		for _, user := range boosted {
+			viewed = append(viewed, user.ID)
+		}
+
+		err = s.BoostRepo.IncrementViews(ctx, viewed, now)
+		if err != nil {
+			logger.Errorln(ctx, "failed to count boost views", err)
+		}
+	}
+
+	users = interleaveBoosted(start, boosted, organic, int(boostedTotal), organicTotal)
+	for i := range users {
+		users[i].DeckToken = s.DeckTokens.Sign(loggedInUser.ID, users[i].ID, now)
+	}
+
+	return users, total, nil
+}
+
+// boostedSlots returns how many of the first n slots of a deck go to its boosted users. They take every
+// BoostSlotInterval-th slot so that everyone else still gets seen while many users are boosted, and every
+// slot once everyone else has been.
+func boostedSlots(n, boosted, organic int) int {
+	slots := (n + model.BoostSlotInterval - 1) / model.BoostSlotInterval
+	if n-organic > slots {
+		slots = n - organic
+	}
+
+	if slots > boosted {
+		slots = boosted
+	}
+
+	return slots
+}
+
+// interleaveBoosted lays out the page of the deck starting at the given slot from its boosted and other users.
+func interleaveBoosted(start int, boosted, organic []model.User, boostedTotal, organicTotal int) []model.User {
+	deck := make([]model.User, 0, len(boosted)+len(organic))
+	for slot := start; len(boosted) > 0 || len(organic) > 0; slot++ {
+		if len(organic) == 0 || (len(boosted) > 0 && boostedSlots(slot+1, boostedTotal, organicTotal) > boostedSlots(slot, boostedTotal, organicTotal)) {
+			deck = append(deck, boosted[0])
+			boosted = boosted[1:]
+
+			continue
+		}
+
+		deck = append(deck, organic[0])
+		organic = organic[1:]
+	}
+
+	return deck
 }
 
 func (a *UserService) GenerateAuthTokens(user *model.User) (string, string, error) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/marvelalexius/jones/config"
 	"github.com/marvelalexius/jones/mocks"
@@ -77,6 +78,7 @@ func TestUserService_Login(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			userRepo := new(mocks.IUserRepository)
 			reactionRepo := new(mocks.IReactionRepository)
+			matchRepo := new(mocks.IMatchRepository)
 			tt.mockSetup(userRepo)
 
 			service := NewUserService(&config.Config{
@@ -84,7 +86,7 @@ func TestUserService_Login(t *testing.T) {
 					Secret:             "some-secret-key",
 					RefreshTokenSecret: "some-refresh-token-secret",
 				},
-			}, userRepo, reactionRepo)
+			}, userRepo, reactionRepo, matchRepo, new(mocks.IBoostRepository), testDeckTokens())
 			user, err := service.Login(context.Background(), tt.input)
 
 			if tt.expectedError != nil {
@@ -142,9 +144,10 @@ func TestUserService_Register(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			userRepo := new(mocks.IUserRepository)
 			reactionRepo := new(mocks.IReactionRepository)
+			matchRepo := new(mocks.IMatchRepository)
 			tt.mockSetup(userRepo)
 
-			service := NewUserService(&config.Config{}, userRepo, reactionRepo)
+			service := NewUserService(&config.Config{}, userRepo, reactionRepo, matchRepo, new(mocks.IBoostRepository), testDeckTokens())
 			user, err := service.Register(context.Background(), tt.input)
 
 			if tt.expectedError != nil {
@@ -165,7 +168,8 @@ func TestUserService_FindAll(t *testing.T) {
 	tests := []struct {
 		name          string
 		userID        string
-		mockSetup     func(*mocks.IUserRepository, *mocks.IReactionRepository)
+		pagination    model.PaginationRequest
+		mockSetup     func(*mocks.IUserRepository, *mocks.IReactionRepository, *mocks.IMatchRepository, *mocks.IBoostRepository)
 		expectedUsers []model.User
 		expectedTotal int64
 		expectedError error
@@ -173,24 +177,22 @@ func TestUserService_FindAll(t *testing.T) {
 		{
 			name:   "successful find all",
 			userID: "user123",
-			mockSetup: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository) {
+			mockSetup: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, br *mocks.IBoostRepository) {
 				loggedInUser := &model.User{
 					ID:         "user123",
 					Preference: "female",
 				}
 				ur.On("FindByID", mock.Anything, "user123").Return(loggedInUser, nil)
 
-				swiped := []model.Reaction{
-					{MatchedUserID: "user456"},
-					{MatchedUserID: "user789"},
-				}
-				rr.On("FindSwiped", mock.Anything, "user123").Return(swiped, nil)
+				rr.On("FindSwipedUserIDs", mock.Anything, "user123", mock.Anything).Return([]string{"user456", "user789"}, nil)
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user123").Return([]string{}, nil)
 
 				users := []model.User{
 					{ID: "user111", Name: "User 1"},
 					{ID: "user222", Name: "User 2"},
 				}
-				ur.On("FindAll", mock.Anything, mock.Anything, "female").Return(users, int64(2), nil)
+				ur.On("CountDeck", mock.Anything, mock.Anything, "female", mock.Anything).Return(int64(2), int64(0), nil)
+				ur.On("FindDeck", mock.Anything, "user123", mock.Anything, "female", false, mock.Anything, 0, model.DefaultPageLimit).Return(users, nil)
 			},
 			expectedUsers: []model.User{
 				{ID: "user111", Name: "User 1"},
@@ -199,10 +201,130 @@ func TestUserService_FindAll(t *testing.T) {
 			expectedTotal: 2,
 			expectedError: nil,
 		},
+		{
+			name:   "unmatched users are hidden",
+			userID: "user123",
+			mockSetup: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, br *mocks.IBoostRepository) {
+				ur.On("FindByID", mock.Anything, "user123").Return(&model.User{ID: "user123", Preference: "female"}, nil)
+				rr.On("FindSwipedUserIDs", mock.Anything, "user123", mock.Anything).Return([]string{"user456"}, nil)
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user123").Return([]string{"user789"}, nil)
+
+				users := []model.User{{ID: "user111", Name: "User 1"}}
+				ur.On("CountDeck", mock.Anything, []string{"user123", "user456", "user789"}, "female", mock.Anything).Return(int64(1), int64(0), nil)
+				ur.On("FindDeck", mock.Anything, "user123", []string{"user123", "user456", "user789"}, "female", false, mock.Anything, 0, model.DefaultPageLimit).Return(users, nil)
+			},
+			expectedUsers: []model.User{{ID: "user111", Name: "User 1"}},
+			expectedTotal: 1,
+			expectedError: nil,
+		},
+		{
+			name:   "boosted users are interleaved",
+			userID: "user123",
+			mockSetup: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, br *mocks.IBoostRepository) {
+				ur.On("FindByID", mock.Anything, "user123").Return(&model.User{ID: "user123", Preference: "female"}, nil)
+				rr.On("FindSwipedUserIDs", mock.Anything, "user123", mock.Anything).Return([]string{}, nil)
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user123").Return([]string{}, nil)
+
+				ur.On("CountDeck", mock.Anything, []string{"user123"}, "female", mock.Anything).Return(int64(5), int64(2), nil)
+				ur.On("FindDeck", mock.Anything, "user123", []string{"user123"}, "female", true, mock.Anything, 0, 2).Return([]model.User{{ID: "user4"}, {ID: "user5"}}, nil)
+				ur.On("FindDeck", mock.Anything, "user123", []string{"user123"}, "female", false, mock.Anything, 0, model.DefaultPageLimit-2).Return([]model.User{{ID: "user1"}, {ID: "user2"}, {ID: "user3"}}, nil)
+				br.On("IncrementViews", mock.Anything, []string{"user4", "user5"}, mock.Anything).Return(nil)
+			},
+			expectedUsers: []model.User{{ID: "user4"}, {ID: "user1"}, {ID: "user2"}, {ID: "user5"}, {ID: "user3"}},
+			expectedTotal: 5,
+			expectedError: nil,
+		},
+		{
+			name:       "only boosted users on the page are counted as viewed",
+			userID:     "user123",
+			pagination: model.PaginationRequest{Page: 1, Limit: 2},
+			mockSetup: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, br *mocks.IBoostRepository) {
+				ur.On("FindByID", mock.Anything, "user123").Return(&model.User{ID: "user123", Preference: "female"}, nil)
+				rr.On("FindSwipedUserIDs", mock.Anything, "user123", mock.Anything).Return([]string{}, nil)
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user123").Return([]string{}, nil)
+
+				ur.On("CountDeck", mock.Anything, []string{"user123"}, "female", mock.Anything).Return(int64(5), int64(2), nil)
+				ur.On("FindDeck", mock.Anything, "user123", []string{"user123"}, "female", true, mock.Anything, 0, 1).Return([]model.User{{ID: "user4"}}, nil)
+				ur.On("FindDeck", mock.Anything, "user123", []string{"user123"}, "female", false, mock.Anything, 0, 1).Return([]model.User{{ID: "user1"}}, nil)
+				br.On("IncrementViews", mock.Anything, []string{"user4"}, mock.Anything).Return(nil)
+			},
+			expectedUsers: []model.User{{ID: "user4"}, {ID: "user1"}},
+			expectedTotal: 5,
+			expectedError: nil,
+		},
+		{
+			name:       "boosted users keep their slots on later pages",
+			userID:     "user123",
+			pagination: model.PaginationRequest{Page: 2, Limit: 2},
+			mockSetup: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, br *mocks.IBoostRepository) {
+				ur.On("FindByID", mock.Anything, "user123").Return(&model.User{ID: "user123", Preference: "female"}, nil)
+				rr.On("FindSwipedUserIDs", mock.Anything, "user123", mock.Anything).Return([]string{}, nil)
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user123").Return([]string{}, nil)
+
+				// the deck is user4, user1, user2, user5, user3
+				ur.On("CountDeck", mock.Anything, []string{"user123"}, "female", mock.Anything).Return(int64(5), int64(2), nil)
+				ur.On("FindDeck", mock.Anything, "user123", []string{"user123"}, "female", true, mock.Anything, 1, 1).Return([]model.User{{ID: "user5"}}, nil)
+				ur.On("FindDeck", mock.Anything, "user123", []string{"user123"}, "female", false, mock.Anything, 1, 1).Return([]model.User{{ID: "user2"}}, nil)
+				br.On("IncrementViews", mock.Anything, []string{"user5"}, mock.Anything).Return(nil)
+			},
+			expectedUsers: []model.User{{ID: "user2"}, {ID: "user5"}},
+			expectedTotal: 5,
+			expectedError: nil,
+		},
+		{
+			name:       "boosted users fill the deck once everyone else was seen",
+			userID:     "user123",
+			pagination: model.PaginationRequest{Page: 2, Limit: 2},
+			mockSetup: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, br *mocks.IBoostRepository) {
+				ur.On("FindByID", mock.Anything, "user123").Return(&model.User{ID: "user123", Preference: "female"}, nil)
+				rr.On("FindSwipedUserIDs", mock.Anything, "user123", mock.Anything).Return([]string{}, nil)
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user123").Return([]string{}, nil)
+
+				// the deck is user3, user1, user4, user5
+				ur.On("CountDeck", mock.Anything, []string{"user123"}, "female", mock.Anything).Return(int64(4), int64(3), nil)
+				ur.On("FindDeck", mock.Anything, "user123", []string{"user123"}, "female", true, mock.Anything, 1, 2).Return([]model.User{{ID: "user4"}, {ID: "user5"}}, nil)
+				br.On("IncrementViews", mock.Anything, []string{"user4", "user5"}, mock.Anything).Return(nil)
+			},
+			expectedUsers: []model.User{{ID: "user4"}, {ID: "user5"}},
+			expectedTotal: 4,
+			expectedError: nil,
+		},
+		{
+			name:       "page past the end of the deck",
+			userID:     "user123",
+			pagination: model.PaginationRequest{Page: 2, Limit: 20},
+			mockSetup: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, br *mocks.IBoostRepository) {
+				ur.On("FindByID", mock.Anything, "user123").Return(&model.User{ID: "user123", Preference: "female"}, nil)
+				rr.On("FindSwipedUserIDs", mock.Anything, "user123", mock.Anything).Return([]string{}, nil)
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user123").Return([]string{}, nil)
+
+				ur.On("CountDeck", mock.Anything, []string{"user123"}, "female", mock.Anything).Return(int64(2), int64(1), nil)
+			},
+			expectedUsers: []model.User{},
+			expectedTotal: 2,
+			expectedError: nil,
+		},
+		{
+			name:   "passes resurface after start over",
+			userID: "user123",
+			mockSetup: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, br *mocks.IBoostRepository) {
+				resetAt := time.Now().Add(-time.Hour)
+				ur.On("FindByID", mock.Anything, "user123").Return(&model.User{ID: "user123", Preference: "female", DeckResetAt: &resetAt}, nil)
+				rr.On("FindSwipedUserIDs", mock.Anything, "user123", resetAt).Return([]string{"user456"}, nil)
+				mr.On("FindUnmatchedUserIDs", mock.Anything, "user123").Return([]string{}, nil)
+
+				users := []model.User{{ID: "user111", Name: "User 1"}}
+				ur.On("CountDeck", mock.Anything, []string{"user123", "user456"}, "female", mock.Anything).Return(int64(1), int64(0), nil)
+				ur.On("FindDeck", mock.Anything, "user123", []string{"user123", "user456"}, "female", false, mock.Anything, 0, model.DefaultPageLimit).Return(users, nil)
+			},
+			expectedUsers: []model.User{{ID: "user111", Name: "User 1"}},
+			expectedTotal: 1,
+			expectedError: nil,
+		},
 		{
 			name:   "user not found",
 			userID: "nonexistent",
-			mockSetup: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository) {
+			mockSetup: func(ur *mocks.IUserRepository, rr *mocks.IReactionRepository, mr *mocks.IMatchRepository, br *mocks.IBoostRepository) {
 				ur.On("FindByID", mock.Anything, "nonexistent").Return(nil, gorm.ErrRecordNotFound)
 			},
 			expectedUsers: []model.User{},
@@ -215,21 +337,124 @@ func TestUserService_FindAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			userRepo := new(mocks.IUserRepository)
 			reactionRepo := new(mocks.IReactionRepository)
-			tt.mockSetup(userRepo, reactionRepo)
+			matchRepo := new(mocks.IMatchRepository)
+			boostRepo := new(mocks.IBoostRepository)
+			tt.mockSetup(userRepo, reactionRepo, matchRepo, boostRepo)
 
-			service := NewUserService(&config.Config{}, userRepo, reactionRepo)
-			users, total, err := service.FindAll(context.Background(), tt.userID)
+			service := NewUserService(&config.Config{}, userRepo, reactionRepo, matchRepo, boostRepo, testDeckTokens())
+			users, total, err := service.FindAll(context.Background(), tt.userID, tt.pagination)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tt.expectedError, err)
 			} else {
 				assert.NoError(t, err)
+
+				// every profile served can be swiped with its deck token
+				for i := range users {
+					assert.NoError(t, testDeckTokens().Verify(users[i].DeckToken, tt.userID, users[i].ID, time.Now()))
+					users[i].DeckToken = ""
+				}
+
 				assert.Equal(t, tt.expectedUsers, users)
 				assert.Equal(t, tt.expectedTotal, total)
 			}
 			userRepo.AssertExpectations(t)
 			reactionRepo.AssertExpectations(t)
+			matchRepo.AssertExpectations(t)
+			boostRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_UpdateTimezone(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockSetup     func(*mocks.IUserRepository)
+		expectedError error
+	}{
+		{
+			name: "successful update",
+			mockSetup: func(ur *mocks.IUserRepository) {
+				ur.On("FindByID", mock.Anything, "user123").Return(&model.User{ID: "user123", Timezone: model.DefaultTimezone}, nil)
+				ur.On("Update", mock.MatchedBy(func(u *model.User) bool {
+					return u.Timezone == "Asia/Jakarta"
+				})).Return(&model.User{ID: "user123", Timezone: "Asia/Jakarta"}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "failed update",
+			mockSetup: func(ur *mocks.IUserRepository) {
+				ur.On("FindByID", mock.Anything, "user123").Return(&model.User{ID: "user123"}, nil)
+				ur.On("Update", mock.AnythingOfType("*model.User")).Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("failed to update timezone"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			tt.mockSetup(userRepo)
+
+			service := NewUserService(&config.Config{}, userRepo, new(mocks.IReactionRepository), new(mocks.IMatchRepository), new(mocks.IBoostRepository), testDeckTokens())
+			user, err := service.UpdateTimezone(context.Background(), "user123", model.UpdateTimezoneRequest{Timezone: "Asia/Jakarta"})
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "Asia/Jakarta", user.Timezone)
+			}
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_UpdateLanguage(t *testing.T) {
+	tests := []struct {
+		name          string
+		mockSetup     func(*mocks.IUserRepository)
+		expectedError error
+	}{
+		{
+			name: "successful update",
+			mockSetup: func(ur *mocks.IUserRepository) {
+				ur.On("FindByID", mock.Anything, "user123").Return(&model.User{ID: "user123", Language: model.DefaultLanguage}, nil)
+				ur.On("Update", mock.MatchedBy(func(u *model.User) bool {
+					return u.Language == "id"
+				})).Return(&model.User{ID: "user123", Language: "id"}, nil)
+			},
+			expectedError: nil,
+		},
+		{
+			name: "failed update",
+			mockSetup: func(ur *mocks.IUserRepository) {
+				ur.On("FindByID", mock.Anything, "user123").Return(&model.User{ID: "user123"}, nil)
+				ur.On("Update", mock.AnythingOfType("*model.User")).Return(nil, errors.New("db error"))
+			},
+			expectedError: errors.New("failed to update language"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.IUserRepository)
+			tt.mockSetup(userRepo)
+
+			service := NewUserService(&config.Config{}, userRepo, new(mocks.IReactionRepository), new(mocks.IMatchRepository), new(mocks.IBoostRepository), testDeckTokens())
+			user, err := service.UpdateLanguage(context.Background(), "user123", model.UpdateLanguageRequest{Language: "id"})
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "id", user.Language)
+			}
+			userRepo.AssertExpectations(t)
 		})
 	}
 }
@@ -267,6 +492,7 @@ func TestUserService_RefreshAuthToken(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			userRepo := new(mocks.IUserRepository)
 			reactionRepo := new(mocks.IReactionRepository)
+			matchRepo := new(mocks.IMatchRepository)
 			tt.mockSetup(userRepo)
 
 			config := &config.Config{
@@ -276,7 +502,7 @@ func TestUserService_RefreshAuthToken(t *testing.T) {
 				},
 			}
 
-			service := NewUserService(config, userRepo, reactionRepo)
+			service := NewUserService(config, userRepo, reactionRepo, matchRepo, new(mocks.IBoostRepository), testDeckTokens())
 			token, refresh, err := service.RefreshAuthToken(context.Background(), tt.refreshToken)
 
 			if tt.expectedError != nil {
@@ -307,7 +533,8 @@ func TestUserService_GenerateAuthTokens(t *testing.T) {
 	t.Run("successful token generation", func(t *testing.T) {
 		userRepo := new(mocks.IUserRepository)
 		reactionRepo := new(mocks.IReactionRepository)
-		service := NewUserService(config, userRepo, reactionRepo)
+		matchRepo := new(mocks.IMatchRepository)
+		service := NewUserService(config, userRepo, reactionRepo, matchRepo, new(mocks.IBoostRepository), testDeckTokens())
 
 		token, refresh, err := service.GenerateAuthTokens(user)
 
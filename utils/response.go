@@ -3,6 +3,7 @@ package utils
 import (
 	"encoding/json"
 	"errors"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -31,6 +32,10 @@ func getErrorMsg(fe validator.FieldError) string {
 	switch fe.Tag() {
 	case "required":
 		return "This field is required"
+	case "required_if":
+		return "This field is required when " + strings.Replace(fe.Param(), " ", " is ", 1)
+	case "required_with":
+		return "This field is required when " + fe.Param() + " is set"
 	case "email":
 		return "Should be a valid email address"
 	case "file":
@@ -43,6 +48,8 @@ func getErrorMsg(fe validator.FieldError) string {
 		return "Should be greater than " + fe.Param()
 	case "eqfield":
 		return "Should be equal to " + fe.Param()
+	case "nefield":
+		return "Should not be equal to " + fe.Param()
 	case "contains":
 		return "Should contains " + fe.Param()
 	case "startsnotwith":
@@ -63,6 +70,10 @@ func getErrorMsg(fe validator.FieldError) string {
 		return "Should be a boolean"
 	case "gt":
 		return "Should be greater than " + fe.Param() + " digits"
+	case "datetime":
+		return "Should be a date in the " + fe.Param() + " format"
+	case "timezone":
+		return "Should be a valid IANA timezone, e.g. Asia/Jakarta"
 	default:
 		return "Unknown error"
 	}
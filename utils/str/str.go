@@ -2,32 +2,38 @@ package str
 
 import "time"
 
+// GetTodayTimeRange returns the start and end of today in the server's local time.
 func GetTodayTimeRange() (time.Time, time.Time) {
-	now := time.Now()
-
-	// Get start of day (00:00:00)
-	startOfDay := time.Date(
-		now.Year(),
-		now.Month(),
-		now.Day(),
-		0, // hour
-		0, // minute
-		0, // second
-		0, // nanosecond
-		now.Location(),
-	)
-
-	// Get end of day (23:59:59)
-	endOfDay := time.Date(
-		now.Year(),
-		now.Month(),
-		now.Day(),
-		23,        // hour
-		59,        // minute
-		59,        // second
-		999999999, // nanosecond (optional, for complete precision)
-		now.Location(),
-	)
+	return GetDayTimeRange(time.Now(), time.Local)
+}
+
+// GetWeekTimeRange returns the start of the current week (Monday 00:00:00) and its end (Sunday 23:59:59)
+// in the server's local time.
+func GetWeekTimeRange() (time.Time, time.Time) {
+	return GetWeekTimeRangeIn(time.Now(), time.Local)
+}
+
+// GetDayTimeRange returns the start (00:00:00) and end (23:59:59) of the day t falls on in loc.
+func GetDayTimeRange(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	t = t.In(loc)
+
+	startOfDay := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+
+	// end at the next midnight instead of adding 24 hours, days are 23 or 25 hours long on DST transitions
+	endOfDay := time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc).Add(-time.Nanosecond)
 
 	return startOfDay, endOfDay
 }
+
+// GetWeekTimeRangeIn returns the start (Monday 00:00:00) and end (Sunday 23:59:59) of the week t falls on in loc.
+func GetWeekTimeRangeIn(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	t = t.In(loc)
+
+	// time.Weekday starts on Sunday, shift it so the week starts on Monday
+	offset := (int(t.Weekday()) + 6) % 7
+
+	startOfWeek := time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, loc)
+	endOfWeek := time.Date(t.Year(), t.Month(), t.Day()-offset+7, 0, 0, 0, 0, loc).Add(-time.Nanosecond)
+
+	return startOfWeek, endOfWeek
+}
@@ -0,0 +1,111 @@
+package str
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDayTimeRange(t *testing.T) {
+	jakarta, _ := time.LoadLocation("Asia/Jakarta")
+	london, _ := time.LoadLocation("Europe/London")
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		loc           *time.Location
+		expectedStart time.Time
+		expectedHours float64
+	}{
+		{
+			name:          "Jakarta Is Already Tomorrow",
+			now:           time.Date(2026, 3, 10, 20, 0, 0, 0, time.UTC),
+			loc:           jakarta,
+			expectedStart: time.Date(2026, 3, 11, 0, 0, 0, 0, jakarta),
+			expectedHours: 24,
+		},
+		{
+			name:          "London Same Moment Is Still Today",
+			now:           time.Date(2026, 3, 10, 20, 0, 0, 0, time.UTC),
+			loc:           london,
+			expectedStart: time.Date(2026, 3, 10, 0, 0, 0, 0, london),
+			expectedHours: 24,
+		},
+		{
+			name:          "London Spring Forward Day Is 23 Hours",
+			now:           time.Date(2026, 3, 29, 12, 0, 0, 0, london),
+			loc:           london,
+			expectedStart: time.Date(2026, 3, 29, 0, 0, 0, 0, london),
+			expectedHours: 23,
+		},
+		{
+			name:          "London Fall Back Day Is 25 Hours",
+			now:           time.Date(2026, 10, 25, 12, 0, 0, 0, london),
+			loc:           london,
+			expectedStart: time.Date(2026, 10, 25, 0, 0, 0, 0, london),
+			expectedHours: 25,
+		},
+		{
+			name:          "New York Just After Spring Forward",
+			now:           time.Date(2026, 3, 8, 3, 30, 0, 0, newYork),
+			loc:           newYork,
+			expectedStart: time.Date(2026, 3, 8, 0, 0, 0, 0, newYork),
+			expectedHours: 23,
+		},
+		{
+			name:          "New York Repeated Hour On Fall Back",
+			now:           time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC), // 01:30 EST, the second time
+			loc:           newYork,
+			expectedStart: time.Date(2026, 11, 1, 0, 0, 0, 0, newYork),
+			expectedHours: 25,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := GetDayTimeRange(tt.now, tt.loc)
+
+			assert.True(t, start.Equal(tt.expectedStart), "start %s", start)
+			assert.Equal(t, tt.expectedHours, end.Add(time.Nanosecond).Sub(start).Hours())
+			assert.Equal(t, 0, end.Add(time.Nanosecond).Hour())
+			assert.False(t, tt.now.Before(start))
+			assert.False(t, tt.now.After(end))
+		})
+	}
+}
+
+func TestGetWeekTimeRangeIn(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+
+	tests := []struct {
+		name          string
+		now           time.Time
+		expectedStart time.Time
+		expectedHours float64
+	}{
+		{
+			name:          "Sunday Belongs To The Week Started Monday",
+			now:           time.Date(2026, 10, 18, 23, 0, 0, 0, london),
+			expectedStart: time.Date(2026, 10, 12, 0, 0, 0, 0, london),
+			expectedHours: 7 * 24,
+		},
+		{
+			name:          "Week With Fall Back Is An Hour Longer",
+			now:           time.Date(2026, 10, 21, 9, 0, 0, 0, london),
+			expectedStart: time.Date(2026, 10, 19, 0, 0, 0, 0, london),
+			expectedHours: 7*24 + 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := GetWeekTimeRangeIn(tt.now, london)
+
+			assert.True(t, start.Equal(tt.expectedStart), "start %s", start)
+			assert.Equal(t, time.Monday, start.Weekday())
+			assert.Equal(t, tt.expectedHours, end.Add(time.Nanosecond).Sub(start).Hours())
+		})
+	}
+}